@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const ansiReset = "\x1b[0m"
+
+// styleSGR returns the ANSI SGR escape sequence that selects style, starting
+// from a reset so sequences can be emitted independently of each other.
+func styleSGR(style tcell.Style) string {
+	fg, bg, attrs := style.Decompose()
+
+	params := []string{"0"}
+	if attrs&tcell.AttrBold != 0 {
+		params = append(params, "1")
+	}
+	if attrs&tcell.AttrDim != 0 {
+		params = append(params, "2")
+	}
+	if attrs&tcell.AttrItalic != 0 {
+		params = append(params, "3")
+	}
+	if attrs&tcell.AttrUnderline != 0 {
+		params = append(params, "4")
+	}
+	if attrs&tcell.AttrBlink != 0 {
+		params = append(params, "5")
+	}
+	if attrs&tcell.AttrReverse != 0 {
+		params = append(params, "7")
+	}
+	if attrs&tcell.AttrStrikeThrough != 0 {
+		params = append(params, "9")
+	}
+	params = append(params, colorSGR(fg, 30)...)
+	params = append(params, colorSGR(bg, 40)...)
+
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// colorSGR encodes c as SGR parameters; base is 30 for foreground and 40 for background.
+func colorSGR(c tcell.Color, base int) []string {
+	if !c.Valid() {
+		return nil
+	}
+	if c.IsRGB() {
+		r, g, b := c.RGB()
+		return []string{strconv.Itoa(base + 8), "2", strconv.Itoa(int(r)), strconv.Itoa(int(g)), strconv.Itoa(int(b))}
+	}
+	idx := int(c - tcell.ColorValid)
+	switch {
+	case idx < 8:
+		return []string{strconv.Itoa(base + idx)}
+	case idx < 16:
+		return []string{strconv.Itoa(base + 60 + idx - 8)}
+	case idx < 256:
+		return []string{strconv.Itoa(base + 8), "5", strconv.Itoa(idx)}
+	}
+	r, g, b := c.RGB()
+	if r < 0 {
+		return nil
+	}
+	return []string{strconv.Itoa(base + 8), "2", strconv.Itoa(int(r)), strconv.Itoa(int(g)), strconv.Itoa(int(b))}
+}
+
+// captureScreenANSI is captureScreenLines with colors and attributes preserved
+// as SGR escapes. Each line ends with a reset.
+func captureScreenANSI(screen tcell.Screen, width, height int) []string {
+	if screen == nil || width <= 0 || height <= 0 {
+		return nil
+	}
+
+	lines := make([]string, height)
+	for y := 0; y < height; y++ {
+		var b strings.Builder
+		prev := ""
+		for x := 0; x < width; x++ {
+			mainc, _, style, _ := screen.GetContent(x, y)
+			if mainc == 0 {
+				mainc = ' '
+			}
+			if sgr := styleSGR(style); sgr != prev {
+				b.WriteString(sgr)
+				prev = sgr
+			}
+			b.WriteRune(mainc)
+		}
+		b.WriteString(ansiReset)
+		lines[y] = b.String()
+	}
+	return lines
+}
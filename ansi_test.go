@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestStyleSGR(t *testing.T) {
+	cases := []struct {
+		name  string
+		style tcell.Style
+		want  string
+	}{
+		{name: "default", style: tcell.StyleDefault, want: "\x1b[0m"},
+		{name: "basic fg", style: tcell.StyleDefault.Foreground(tcell.ColorMaroon), want: "\x1b[0;31m"},
+		{name: "bright fg on black", style: tcell.StyleDefault.Foreground(tcell.ColorRed).Background(tcell.ColorBlack), want: "\x1b[0;91;40m"},
+		{name: "palette", style: tcell.StyleDefault.Foreground(tcell.PaletteColor(200)), want: "\x1b[0;38;5;200m"},
+		{name: "rgb", style: tcell.StyleDefault.Background(tcell.NewRGBColor(1, 2, 3)), want: "\x1b[0;48;2;1;2;3m"},
+		{name: "bold", style: tcell.StyleDefault.Bold(true), want: "\x1b[0;1m"},
+	}
+	for _, tc := range cases {
+		if got := styleSGR(tc.style); got != tc.want {
+			t.Fatalf("%s: expected %q, got %q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestCaptureScreenANSIKeepsColors(t *testing.T) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("init screen: %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(3, 1)
+
+	red := tcell.StyleDefault.Foreground(tcell.ColorMaroon)
+	screen.SetContent(0, 0, 'a', nil, red)
+	screen.SetContent(1, 0, 'b', nil, red)
+	screen.SetContent(2, 0, 'c', nil, tcell.StyleDefault)
+
+	lines := captureScreenANSI(screen, 3, 1)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line, got %d", len(lines))
+	}
+	want := "\x1b[0;31mab\x1b[0mc" + ansiReset
+	if lines[0] != want {
+		t.Fatalf("expected %q, got %q", want, lines[0])
+	}
+	if strings.Count(lines[0], "\x1b[0;31m") != 1 {
+		t.Fatal("expected unchanged style runs to share one escape")
+	}
+}
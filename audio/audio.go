@@ -0,0 +1,158 @@
+// Package audio is the game's sound hook: gameplay code emits Events on a
+// Bus and pluggable backends decide what, if anything, to play.
+package audio
+
+import (
+	"sync"
+)
+
+// Event is something the player might hear.
+type Event int
+
+const (
+	Footstep Event = iota
+	Turn
+	StairsDescended
+	WhisperShown
+	WatcherAppears
+	CorruptionThreshold
+)
+
+var eventNames = [...]string{
+	Footstep:            "footstep",
+	Turn:                "turn",
+	StairsDescended:     "stairs",
+	WhisperShown:        "whisper",
+	WatcherAppears:      "watcher",
+	CorruptionThreshold: "corruption",
+}
+
+// Events lists every event, in declaration order.
+func Events() []Event {
+	out := make([]Event, len(eventNames))
+	for i := range eventNames {
+		out[i] = Event(i)
+	}
+	return out
+}
+
+func (e Event) String() string {
+	if e < 0 || int(e) >= len(eventNames) {
+		return "unknown"
+	}
+	return eventNames[e]
+}
+
+// ParseEvent returns the event with the given String name.
+func ParseEvent(name string) (Event, bool) {
+	for i, n := range eventNames {
+		if n == name {
+			return Event(i), true
+		}
+	}
+	return 0, false
+}
+
+// Backend plays events. Play is called on the game loop and must not block.
+type Backend interface {
+	Play(Event)
+}
+
+// Bus fans events out to its backends. A nil *Bus is valid and silent, so
+// callers can emit unconditionally.
+type Bus struct {
+	mu       sync.Mutex
+	backends []Backend
+	lastKey  map[Event]int
+}
+
+// NewBus returns a bus delivering to backends in order.
+func NewBus(backends ...Backend) *Bus {
+	return &Bus{backends: backends}
+}
+
+// Add appends a backend to the bus.
+func (b *Bus) Add(backend Backend) {
+	if b == nil || backend == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.backends = append(b.backends, backend)
+}
+
+// Emit delivers e to every backend.
+func (b *Bus) Emit(e Event) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	backends := b.backends
+	b.mu.Unlock()
+	for _, backend := range backends {
+		backend.Play(e)
+	}
+}
+
+// EmitOnce emits e unless the last EmitOnce for e used the same key. Effects
+// that stay on screen for several frames use it to sound once per showing.
+func (b *Bus) EmitOnce(e Event, key int) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	if b.lastKey == nil {
+		b.lastKey = make(map[Event]int)
+	}
+	last, seen := b.lastKey[e]
+	b.lastKey[e] = key
+	b.mu.Unlock()
+	if seen && last == key {
+		return
+	}
+	b.Emit(e)
+}
+
+// Null discards every event.
+type Null struct{}
+
+func (Null) Play(Event) {}
+
+// Recorder keeps every event it is played, for tests.
+type Recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *Recorder) Play(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+// Events returns a copy of the events played so far.
+func (r *Recorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+// Count returns how many times e was played.
+func (r *Recorder) Count(e Event) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, got := range r.events {
+		if got == e {
+			n++
+		}
+	}
+	return n
+}
+
+// Reset forgets recorded events.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
@@ -0,0 +1,141 @@
+package audio
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBusFansOutToBackends(t *testing.T) {
+	a, b := &Recorder{}, &Recorder{}
+	bus := NewBus(a)
+	bus.Add(b)
+	bus.Emit(Footstep)
+	bus.Emit(StairsDescended)
+
+	want := []Event{Footstep, StairsDescended}
+	if !reflect.DeepEqual(a.Events(), want) || !reflect.DeepEqual(b.Events(), want) {
+		t.Fatalf("expected both backends to get %v, got %v and %v", want, a.Events(), b.Events())
+	}
+
+	var nilBus *Bus
+	nilBus.Emit(Turn)
+	nilBus.EmitOnce(Turn, 1)
+	nilBus.Add(a)
+}
+
+func TestBusEmitOnceDedupesByKey(t *testing.T) {
+	rec := &Recorder{}
+	bus := NewBus(rec)
+	for _, key := range []int{0, 0, 0, 1, 1, 0} {
+		bus.EmitOnce(WhisperShown, key)
+	}
+	bus.EmitOnce(WatcherAppears, 0)
+	if got := rec.Count(WhisperShown); got != 3 {
+		t.Fatalf("expected 3 whisper events (keys 0,1,0), got %d", got)
+	}
+	if got := rec.Count(WatcherAppears); got != 1 {
+		t.Fatalf("expected keys tracked per event, got %d watcher events", got)
+	}
+	rec.Reset()
+	if len(rec.Events()) != 0 {
+		t.Fatal("expected reset to clear events")
+	}
+}
+
+func TestEventNamesRoundTrip(t *testing.T) {
+	for _, e := range Events() {
+		got, ok := ParseEvent(e.String())
+		if !ok || got != e {
+			t.Fatalf("round trip failed for %v", e)
+		}
+	}
+	if _, ok := ParseEvent("kazoo"); ok {
+		t.Fatal("expected unknown event name to fail")
+	}
+	if Event(99).String() != "unknown" {
+		t.Fatal("expected out-of-range events to print as unknown")
+	}
+}
+
+func TestBellRingsOnlyForChosenEvents(t *testing.T) {
+	rings := 0
+	bell := NewBell(func() error { rings++; return nil })
+	for _, e := range Events() {
+		bell.Play(e)
+	}
+	if rings != len(DefaultBellEvents) {
+		t.Fatalf("expected %d rings, got %d", len(DefaultBellEvents), rings)
+	}
+
+	rings = 0
+	custom := NewBell(func() error { rings++; return nil }, Footstep)
+	custom.Play(Footstep)
+	custom.Play(StairsDescended)
+	if rings != 1 {
+		t.Fatalf("expected custom bell to ring once, got %d", rings)
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	c, err := ParseCommand("play sounds/{event}.wav")
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+	if got := c.CommandFor(StairsDescended); got != "play sounds/stairs.wav" {
+		t.Fatalf("unexpected command %q", got)
+	}
+
+	c, err = ParseCommand("footstep=aplay step.wav; *=echo {event}")
+	if err != nil {
+		t.Fatalf("parse pairs: %v", err)
+	}
+	if got := c.CommandFor(Footstep); got != "aplay step.wav" {
+		t.Fatalf("unexpected footstep command %q", got)
+	}
+	if got := c.CommandFor(Turn); got != "echo turn" {
+		t.Fatalf("unexpected default command %q", got)
+	}
+
+	c, _ = ParseCommand("stairs=bell")
+	if got := c.CommandFor(Turn); got != "" {
+		t.Fatalf("expected no command for unmapped event, got %q", got)
+	}
+
+	// An '=' that doesn't follow an event name belongs to the template.
+	for _, spec := range []string{"paplay --volume=40000 {event}.oga", "sh -c 'X=1 play {event}'", "kazoo=x"} {
+		c, err := ParseCommand(spec)
+		if err != nil {
+			t.Fatalf("parse %q: %v", spec, err)
+		}
+		if c.PerEvent != nil || c.Default != spec {
+			t.Fatalf("expected %q as the default template, got %+v", spec, c)
+		}
+	}
+	c, _ = ParseCommand("paplay --volume=40000 {event}.oga")
+	if got := c.CommandFor(Footstep); got != "paplay --volume=40000 footstep.oga" {
+		t.Fatalf("unexpected command %q", got)
+	}
+
+	for _, bad := range []string{"", "stairs=", "stairs=bell; kazoo=x"} {
+		if _, err := ParseCommand(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestCommandPlayStartsExpandedCommand(t *testing.T) {
+	var started []string
+	c := &Command{
+		Default:  "say {event}",
+		PerEvent: map[Event]string{Footstep: ""},
+		start: func(cmdline string) error {
+			started = append(started, cmdline)
+			return nil
+		},
+	}
+	c.Play(WatcherAppears)
+	c.Play(Footstep) // mapped to nothing
+	if !reflect.DeepEqual(started, []string{"say watcher"}) {
+		t.Fatalf("unexpected commands %v", started)
+	}
+}
@@ -0,0 +1,31 @@
+package audio
+
+// DefaultBellEvents are the events worth a terminal bell; footsteps and turns
+// would ring on every key press.
+var DefaultBellEvents = []Event{StairsDescended, WatcherAppears, CorruptionThreshold}
+
+// Bell rings the terminal bell for a chosen set of events.
+type Bell struct {
+	beep   func() error
+	events map[Event]bool
+}
+
+// NewBell returns a bell backend calling beep (usually tcell.Screen.Beep) for
+// events, or DefaultBellEvents when none are given.
+func NewBell(beep func() error, events ...Event) *Bell {
+	if len(events) == 0 {
+		events = DefaultBellEvents
+	}
+	b := &Bell{beep: beep, events: make(map[Event]bool, len(events))}
+	for _, e := range events {
+		b.events[e] = true
+	}
+	return b
+}
+
+func (b *Bell) Play(e Event) {
+	if b == nil || b.beep == nil || !b.events[e] {
+		return
+	}
+	_ = b.beep()
+}
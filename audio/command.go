@@ -0,0 +1,105 @@
+package audio
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Command plays events by running a shell command per event, for example
+// `aplay sounds/{event}.wav`. Commands run in the background; their output is
+// discarded and failures are ignored so a missing player never stalls a frame.
+type Command struct {
+	// Default runs for events without an entry in PerEvent. "{event}" is
+	// replaced by the event name.
+	Default  string
+	PerEvent map[Event]string
+
+	// start launches a command; tests replace it.
+	start func(cmdline string) error
+}
+
+// ParseCommand builds a Command from a spec: either a single template used
+// for every event, or "event=cmd" pairs separated by ';' (with an optional
+// "*=cmd" default). A spec is read as pairs only when it starts with an event
+// name or '*' and '=', so templates like "paplay --volume=40000 {event}.oga"
+// keep their '='.
+func ParseCommand(spec string) (*Command, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("empty audio command")
+	}
+	c := &Command{}
+	if !isCommandPairs(spec) {
+		c.Default = spec
+		return c, nil
+	}
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, cmd, ok := strings.Cut(part, "=")
+		if !ok || strings.TrimSpace(cmd) == "" {
+			return nil, fmt.Errorf("audio command %q: want event=command", part)
+		}
+		name = strings.TrimSpace(name)
+		if name == "*" {
+			c.Default = strings.TrimSpace(cmd)
+			continue
+		}
+		e, ok := ParseEvent(name)
+		if !ok {
+			return nil, fmt.Errorf("audio command %q: unknown event %q", part, name)
+		}
+		if c.PerEvent == nil {
+			c.PerEvent = make(map[Event]string)
+		}
+		c.PerEvent[e] = strings.TrimSpace(cmd)
+	}
+	return c, nil
+}
+
+func isCommandPairs(spec string) bool {
+	first, _, _ := strings.Cut(spec, ";")
+	name, _, ok := strings.Cut(first, "=")
+	if !ok {
+		return false
+	}
+	name = strings.TrimSpace(name)
+	_, known := ParseEvent(name)
+	return known || name == "*"
+}
+
+// CommandFor returns the expanded command line for e, or "" when none applies.
+func (c *Command) CommandFor(e Event) string {
+	if c == nil {
+		return ""
+	}
+	cmd, ok := c.PerEvent[e]
+	if !ok {
+		cmd = c.Default
+	}
+	return strings.ReplaceAll(cmd, "{event}", e.String())
+}
+
+func (c *Command) Play(e Event) {
+	cmdline := c.CommandFor(e)
+	if cmdline == "" {
+		return
+	}
+	start := c.start
+	if start == nil {
+		start = startShell
+	}
+	_ = start(cmdline)
+}
+
+func startShell(cmdline string) error {
+	cmd := exec.Command("sh", "-c", cmdline)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
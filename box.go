@@ -0,0 +1,73 @@
+package main
+
+import "github.com/gdamore/tcell/v2"
+
+// drawCenteredBox draws lines inside a bordered box centered on screen. A box
+// too big for the screen loses its padding, then is cut to fit. It returns
+// false when there is nothing to draw.
+func (g *Game) drawCenteredBox(lines []string) bool {
+	if g == nil || g.Screen == nil || len(lines) == 0 {
+		return false
+	}
+	l := layoutHUD(g.Width, g.Height, []hudPanel{boxPanel("box", hudZModal, lines)})
+	l.drawHUD(g.Screen, g.Width, g.Height)
+	return len(l.Placed) > 0
+}
+
+// boxPanel is a centred, bordered box of lines: padded, then tight, then
+// clipped to the screen.
+func boxPanel(name string, z int, lines []string) hudPanel {
+	menuW := 0
+	for _, line := range lines {
+		if w := len([]rune(line)); w > menuW {
+			menuW = w
+		}
+	}
+	menuH := len(lines)
+	pads := []int{2, 1}
+
+	p := hudPanel{Name: name, Anchor: anchorCenter, Z: z, Clip: true}
+	for _, pad := range pads {
+		p.Sizes = append(p.Sizes, hudSize{W: menuW + pad*2, H: menuH + 2})
+	}
+	p.Draw = func(c hudCanvas, variant int) {
+		drawBox(c, lines, pads[variant])
+	}
+	return p
+}
+
+// drawBox fills c with a border and lines inset by pad columns.
+func drawBox(c hudCanvas, lines []string, pad int) {
+	boxW, boxH := c.rect.W, c.rect.H
+	if boxW < 2 || boxH < 2 {
+		return
+	}
+
+	borderStyle := tcell.StyleDefault.Foreground(tcell.ColorGray).Background(tcell.ColorBlack)
+	fillStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack)
+
+	// Border.
+	for x := 0; x < boxW; x++ {
+		c.set(x, 0, '-', borderStyle)
+		c.set(x, boxH-1, '-', borderStyle)
+	}
+	for y := 0; y < boxH; y++ {
+		c.set(0, y, '|', borderStyle)
+		c.set(boxW-1, y, '|', borderStyle)
+	}
+	c.set(0, 0, '+', borderStyle)
+	c.set(boxW-1, 0, '+', borderStyle)
+	c.set(0, boxH-1, '+', borderStyle)
+	c.set(boxW-1, boxH-1, '+', borderStyle)
+
+	// Fill + text, cut at the border.
+	for y := 1; y < boxH-1; y++ {
+		for x := 1; x < boxW-1; x++ {
+			c.set(x, y, ' ', fillStyle)
+		}
+	}
+	text := c.sub(pad, 1, boxW-pad*2, boxH-2)
+	for y, line := range lines {
+		text.drawString(0, y, line, fillStyle)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// castMinInterval caps the recording at ~30fps to keep files small.
+const castMinInterval = 33 * time.Millisecond
+
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// castRecorder streams frames to an asciinema asciicast v2 file.
+type castRecorder struct {
+	path   string
+	file   *os.File
+	w      *bufio.Writer
+	start  time.Time
+	last   time.Time
+	width  int
+	height int
+	prev   string
+	frames int
+}
+
+func newCastRecorder(path string, width, height int, start time.Time) (*castRecorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &castRecorder{
+		path:   path,
+		file:   f,
+		w:      bufio.NewWriter(f),
+		start:  start,
+		width:  width,
+		height: height,
+	}
+	header := castHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: start.Unix(),
+		Title:     "The Abyss",
+		Env:       map[string]string{"TERM": "xterm-256color"},
+	}
+	if err := c.writeJSONLine(header); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *castRecorder) writeJSONLine(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := c.w.Write(data); err != nil {
+		return err
+	}
+	return c.w.WriteByte('\n')
+}
+
+func (c *castRecorder) writeEvent(now time.Time, kind, data string) error {
+	elapsed := now.Sub(c.start).Seconds()
+	return c.writeJSONLine([]any{elapsed, kind, data})
+}
+
+// captureFrame records the current screen contents as a full repaint. Frames
+// identical to the previous one, or arriving faster than castMinInterval, are skipped.
+func (c *castRecorder) captureFrame(screen tcell.Screen, width, height int, now time.Time) error {
+	if c == nil {
+		return nil
+	}
+	if c.frames > 0 && now.Sub(c.last) < castMinInterval {
+		return nil
+	}
+	if width != c.width || height != c.height {
+		c.width, c.height = width, height
+		if err := c.writeEvent(now, "r", fmt.Sprintf("%dx%d", width, height)); err != nil {
+			return err
+		}
+		c.prev = ""
+	}
+
+	lines := captureScreenANSI(screen, width, height)
+	if len(lines) == 0 {
+		return nil
+	}
+	frame := "\x1b[H" + strings.Join(lines, "\r\n")
+	if frame == c.prev {
+		return nil
+	}
+	c.prev = frame
+	c.last = now
+	c.frames++
+	return c.writeEvent(now, "o", frame)
+}
+
+func (c *castRecorder) Close() error {
+	if c == nil {
+		return nil
+	}
+	if err := c.w.Flush(); err != nil {
+		c.file.Close()
+		return err
+	}
+	return c.file.Close()
+}
+
+func castFilename(depth int, ts time.Time) string {
+	return fmt.Sprintf("depth-%02d-%s.cast", depth, ts.UTC().Format("20060102-150405"))
+}
+
+// toggleCastRecording starts a new asciicast recording under ./recordings, or
+// stops the active one. It returns a status message for the cheat console.
+func (g *Game) toggleCastRecording() string {
+	if g.cast != nil {
+		path := g.cast.path
+		frames := g.cast.frames
+		err := g.cast.Close()
+		g.cast = nil
+		if err != nil {
+			return fmt.Sprintf("Recording failed: %v", err)
+		}
+		return fmt.Sprintf("Recording saved: %s (%d frames)", filepath.Base(path), frames)
+	}
+
+	dir := filepath.Join(".", "recordings")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Sprintf("Recording failed: %v", err)
+	}
+	depth := 0
+	if g.Floor != nil {
+		depth = g.Floor.Depth
+	}
+	now := time.Now()
+	c, err := newCastRecorder(filepath.Join(dir, castFilename(depth, now)), g.Width, g.Height, now)
+	if err != nil {
+		return fmt.Sprintf("Recording failed: %v", err)
+	}
+	g.cast = c
+	return "Recording started"
+}
+
+// recordCastFrame appends the rendered frame to the active recording, if any.
+func (g *Game) recordCastFrame(now time.Time) {
+	if g.cast == nil {
+		return
+	}
+	if err := g.cast.captureFrame(g.Screen, g.Width, g.Height, now); err != nil {
+		g.cast.Close()
+		g.cast = nil
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func readCastLines(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open cast: %v", err)
+	}
+	defer f.Close()
+
+	var lines []string
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatalf("scan cast: %v", err)
+	}
+	return lines
+}
+
+func TestCastRecorderWritesAsciicastV2(t *testing.T) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("init screen: %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(4, 2)
+
+	start := time.Unix(1000, 0)
+	path := filepath.Join(t.TempDir(), "run.cast")
+	c, err := newCastRecorder(path, 4, 2, start)
+	if err != nil {
+		t.Fatalf("new recorder: %v", err)
+	}
+
+	screen.SetContent(0, 0, 'x', nil, tcell.StyleDefault.Foreground(tcell.ColorRed))
+	if err := c.captureFrame(screen, 4, 2, start.Add(100*time.Millisecond)); err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	// Unchanged frame: skipped.
+	if err := c.captureFrame(screen, 4, 2, start.Add(200*time.Millisecond)); err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	// Too soon after the last frame: skipped.
+	screen.SetContent(1, 0, 'y', nil, tcell.StyleDefault)
+	if err := c.captureFrame(screen, 4, 2, start.Add(110*time.Millisecond)); err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	if err := c.captureFrame(screen, 4, 2, start.Add(500*time.Millisecond)); err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	lines := readCastLines(t, path)
+	if len(lines) != 3 {
+		t.Fatalf("expected header + 2 frames, got %d lines", len(lines))
+	}
+
+	var header castHeader
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatalf("decode header: %v", err)
+	}
+	if header.Version != 2 || header.Width != 4 || header.Height != 2 || header.Timestamp != 1000 {
+		t.Fatalf("unexpected header %+v", header)
+	}
+
+	var ev []any
+	if err := json.Unmarshal([]byte(lines[2]), &ev); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	if len(ev) != 3 || ev[0].(float64) != 0.5 || ev[1].(string) != "o" {
+		t.Fatalf("unexpected event %v", ev)
+	}
+}
+
+func TestCastRecorderEmitsResizeEvent(t *testing.T) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("init screen: %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(6, 3)
+
+	start := time.Unix(0, 0)
+	path := filepath.Join(t.TempDir(), "resize.cast")
+	c, err := newCastRecorder(path, 4, 2, start)
+	if err != nil {
+		t.Fatalf("new recorder: %v", err)
+	}
+	if err := c.captureFrame(screen, 6, 3, start.Add(time.Second)); err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	c.Close()
+
+	lines := readCastLines(t, path)
+	var ev []any
+	if err := json.Unmarshal([]byte(lines[1]), &ev); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	if ev[1].(string) != "r" || ev[2].(string) != "6x3" {
+		t.Fatalf("expected resize event, got %v", ev)
+	}
+}
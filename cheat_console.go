@@ -0,0 +1,461 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"game/console"
+	"game/engine"
+	"game/events"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	// Console command limits; wider than the pause settings so QA can reach
+	// edge cases.
+	cheatMinFOVDeg = 10
+	cheatMaxFOVDeg = 170
+	cheatMinDist   = 1
+	cheatMaxDist   = 64
+
+	// cheatConsoleRows is the console's height, scrollback plus the input
+	// line, before it shrinks to fit.
+	cheatConsoleRows = 8
+	cheatDumpPath    = "map.txt"
+)
+
+func (g *Game) handleCheatEvent(ev *tcell.EventKey) bool {
+	if g == nil || ev == nil || g.daily {
+		return false
+	}
+
+	if !g.cheatConsoleOpen {
+		if ev.Key() != tcell.KeyRune {
+			return false
+		}
+		switch ev.Rune() {
+		case 'c', 'C', '`':
+			g.openCheatConsole()
+			// Even a look at the cheats ends a speedrun's eligibility.
+			if g.speedrun != nil {
+				g.speedrun.Disqualified = true
+			}
+			return true
+		}
+		return false
+	}
+
+	// The console takes every key while open; ` closes it like Esc.
+	if ev.Key() == tcell.KeyRune && ev.Rune() == '`' {
+		g.closeCheatConsole()
+		return true
+	}
+	if g.cheatConsole.HandleKey(ev) == console.Closed {
+		g.closeCheatConsole()
+	}
+	return true
+}
+
+func (g *Game) openCheatConsole() {
+	if g.cheatConsole == nil {
+		g.cheatConsole = g.newCheatConsole()
+	}
+	g.cheatConsole.Reset()
+	g.cheatConsoleOpen = true
+}
+
+func (g *Game) closeCheatConsole() {
+	g.cheatConsoleOpen = false
+}
+
+// newCheatConsole registers the cheat commands. Each successful command is
+// published as CheatUsed with its name and arguments.
+func (g *Game) newCheatConsole() *console.Console {
+	c := console.New()
+	c.Print("Cheat console. Tab completes, Up/Down recalls, Esc closes.")
+	for _, cmd := range g.cheatCommands() {
+		run := cmd.Run
+		name := cmd.Name
+		sideEffect := cmd.SideEffect
+		cmd.Run = func(args []string) (string, error) {
+			// Replays and restored saves re-type every command; only the
+			// live session touches files.
+			var out string
+			var err error
+			if sideEffect && g.resimulating() {
+				out = "Skipped while replaying: " + name
+			} else {
+				out, err = run(args)
+			}
+			if err == nil {
+				g.Events.Publish(events.CheatUsed{Action: name, Detail: strings.Join(args, " ")})
+			}
+			return out, err
+		}
+		c.Register(cmd)
+	}
+	return c
+}
+
+func (g *Game) cheatCommands() []*console.Command {
+	return []*console.Command{
+		{
+			Name:  "tp",
+			Usage: "tp DEPTH",
+			Help:  "teleport to a new floor at DEPTH",
+			Run: oneArg(func(arg string) (string, error) {
+				depth, err := console.Int(arg)
+				if err != nil {
+					return "", err
+				}
+				if depth < 1 {
+					return "", errors.New("invalid depth")
+				}
+				if !g.teleportToDepth(depth) {
+					return "", errors.New("teleport failed")
+				}
+				return fmt.Sprintf("Teleported to depth %d", depth), nil
+			}),
+		},
+		{
+			Name:  "seed",
+			Usage: "seed [SEED]",
+			Help:  "show the floor seed, or regenerate this floor from SEED",
+			Run: func(args []string) (string, error) {
+				gen := g.FloorManager.Generator
+				switch len(args) {
+				case 0:
+					return fmt.Sprintf("Seed %d", gen.Seed), nil
+				case 1:
+					seed, err := strconv.ParseInt(args[0], 10, 64)
+					if err != nil {
+						return "", fmt.Errorf("not a seed: %s", args[0])
+					}
+					gen.WithSeed(seed)
+					depth := g.FloorManager.GetCurrentDepth()
+					if !g.teleportToDepth(depth) {
+						return "", errors.New("regenerate failed")
+					}
+					return fmt.Sprintf("Seed %d, depth %d", seed, depth), nil
+				}
+				return "", console.ErrUsage
+			},
+		},
+		{
+			Name:  "regen",
+			Usage: "regen",
+			Help:  "regenerate the current floor",
+			Run: noArgs(func() (string, error) {
+				depth := g.FloorManager.GetCurrentDepth()
+				if !g.teleportToDepth(depth) {
+					return "", errors.New("regenerate failed")
+				}
+				return fmt.Sprintf("Regenerated depth %d", depth), nil
+			}),
+		},
+		{
+			Name:  "corrupt",
+			Usage: "corrupt [LEVEL]",
+			Help:  "show corruption, or set it to LEVEL (0-1)",
+			Run: func(args []string) (string, error) {
+				if len(args) > 1 {
+					return "", console.ErrUsage
+				}
+				if len(args) == 1 {
+					level, err := console.Float(args[0])
+					if err != nil {
+						return "", err
+					}
+					g.CorruptState.SetLevel(level)
+					g.Corruption = g.CorruptState.GetLevel()
+				}
+				return fmt.Sprintf("Corruption %.0f%% (bias %+.0f%%)", g.CorruptState.GetLevel()*100, g.CorruptState.GetBias()*100), nil
+			},
+		},
+		{
+			Name:  "fov",
+			Usage: "fov [DEGREES]",
+			Help:  fmt.Sprintf("show or set the field of view (%d-%d)", cheatMinFOVDeg, cheatMaxFOVDeg),
+			Run: g.raycasterSetting(func(r *engine.Raycaster) *float64 { return &r.FOV }, func(v float64) float64 {
+				return math.Max(cheatMinFOVDeg, math.Min(cheatMaxFOVDeg, v)) * math.Pi / 180
+			}, func(v float64) string {
+				return fmt.Sprintf("FOV %.0f°", v*180/math.Pi)
+			}),
+		},
+		{
+			Name:  "dist",
+			Usage: "dist [CELLS]",
+			Help:  fmt.Sprintf("show or set the render distance (%d-%d)", cheatMinDist, cheatMaxDist),
+			Run: g.raycasterSetting(func(r *engine.Raycaster) *float64 { return &r.MaxDist }, func(v float64) float64 {
+				return math.Max(cheatMinDist, math.Min(cheatMaxDist, v))
+			}, func(v float64) string {
+				return fmt.Sprintf("Render distance %.0f", v)
+			}),
+		},
+		{
+			Name:     "spawn",
+			Usage:    "spawn watcher",
+			Help:     "add an entity to this floor",
+			Complete: argChoices("watcher"),
+			Run: oneArg(func(arg string) (string, error) {
+				if arg != "watcher" || g.Floor == nil || g.Floor.Watchers == nil {
+					return "", console.ErrUsage
+				}
+				g.Floor.Watchers.Spawn()
+				return fmt.Sprintf("Watchers on this floor: %d", len(g.Floor.Watchers.Watchers)), nil
+			}),
+		},
+		{
+			Name:  "reveal",
+			Usage: "reveal",
+			Help:  "mark the stairs as seen and show where they are",
+			Run: noArgs(func() (string, error) {
+				g.stairsSeen = true
+				stairs := g.Floor.StairsPos
+				return fmt.Sprintf("Stairs at (%d, %d)", stairs.X, stairs.Y), nil
+			}),
+		},
+		{
+			Name:       "dump",
+			Usage:      "dump [FILE]",
+			Help:       "write the floor as text (default " + cheatDumpPath + ")",
+			SideEffect: true,
+			Run: func(args []string) (string, error) {
+				path := cheatDumpPath
+				switch len(args) {
+				case 0:
+				case 1:
+					path = args[0]
+				default:
+					return "", console.ErrUsage
+				}
+				if err := os.WriteFile(path, []byte(dumpMap(g.GameMap, g.Player)), 0o644); err != nil {
+					return "", err
+				}
+				return "Map written: " + path, nil
+			},
+		},
+		{
+			Name:     "map",
+			Usage:    "map [on|off|" + strings.Join(miniMapModeNames[:], "|") + "]",
+			Help:     "toggle the mini-map or pick its mode",
+			Complete: argChoices(append([]string{"on", "off"}, miniMapModeNames[:]...)...),
+			Run: func(args []string) (string, error) {
+				if len(args) == 1 {
+					if mode, err := parseMiniMapMode(args[0]); err == nil {
+						g.ShowMiniMap, g.MiniMapMode = true, mode
+						return "Mini-map " + mode.String(), nil
+					}
+				}
+				on, err := console.Switch(args, g.ShowMiniMap)
+				if err != nil {
+					return "", err
+				}
+				g.ShowMiniMap = on
+				return "Mini-map " + onOff(on), nil
+			},
+		},
+		{
+			Name:     "noclip",
+			Usage:    "noclip [on|off]",
+			Help:     "walk through walls",
+			Complete: argChoices("on", "off"),
+			Run: func(args []string) (string, error) {
+				on, err := console.Switch(args, g.Player.Noclip)
+				if err != nil {
+					return "", err
+				}
+				g.Player.Noclip = on
+				return "noclip " + onOff(on), nil
+			},
+		},
+		{
+			Name:     "freecam",
+			Usage:    "freecam [on|off]",
+			Help:     "fly the view away from the player (WS ZX AD, [ ] FOV, - = distance)",
+			Complete: argChoices("on", "off"),
+			Run: func(args []string) (string, error) {
+				on, err := console.Switch(args, g.freeCam != nil)
+				if err != nil {
+					return "", err
+				}
+				g.setFreeCam(on)
+				return "freecam " + onOff(on), nil
+			},
+		},
+		g.switchCommand("godview", "draw the floor from above with rays and entities", &g.godView),
+		g.switchCommand("watchers", "show Watchers", &g.ShowWatchers),
+		g.switchCommand("perf", "show the perf overlay", &g.ShowPerf),
+		g.switchCommand("ansi", "also write an ANSI copy of snapshots", &g.SnapshotANSI),
+		{
+			Name:       "snapshot",
+			Usage:      "snapshot",
+			Help:       "save the current frame as text",
+			SideEffect: true,
+			Run: noArgs(func() (string, error) {
+				path, err := g.captureSnapshot()
+				if err != nil {
+					return "", err
+				}
+				return "Snapshot saved: " + filepath.Base(path), nil
+			}),
+		},
+		{
+			Name:       "record",
+			Usage:      "record",
+			Help:       "start or stop an asciicast recording",
+			SideEffect: true,
+			Run: noArgs(func() (string, error) {
+				return g.toggleCastRecording(), nil
+			}),
+		},
+	}
+}
+
+// switchCommand is an on/off command for flag.
+func (g *Game) switchCommand(name, help string, flag *bool) *console.Command {
+	return &console.Command{
+		Name:     name,
+		Usage:    name + " [on|off]",
+		Help:     help,
+		Complete: argChoices("on", "off"),
+		Run: func(args []string) (string, error) {
+			on, err := console.Switch(args, *flag)
+			if err != nil {
+				return "", err
+			}
+			*flag = on
+			return name + " " + onOff(on), nil
+		},
+	}
+}
+
+// raycasterSetting is a command showing or setting one raycaster field,
+// clamped and converted from the typed number by set.
+func (g *Game) raycasterSetting(field func(*engine.Raycaster) *float64, set func(float64) float64, show func(float64) string) func([]string) (string, error) {
+	return func(args []string) (string, error) {
+		if g.Raycaster == nil {
+			return "", errors.New("no view")
+		}
+		v := field(g.Raycaster)
+		switch len(args) {
+		case 0:
+		case 1:
+			n, err := console.Float(args[0])
+			if err != nil {
+				return "", err
+			}
+			*v = set(n)
+		default:
+			return "", console.ErrUsage
+		}
+		return show(*v), nil
+	}
+}
+
+func noArgs(run func() (string, error)) func([]string) (string, error) {
+	return func(args []string) (string, error) {
+		if len(args) != 0 {
+			return "", console.ErrUsage
+		}
+		return run()
+	}
+}
+
+func oneArg(run func(string) (string, error)) func([]string) (string, error) {
+	return func(args []string) (string, error) {
+		if len(args) != 1 {
+			return "", console.ErrUsage
+		}
+		return run(args[0])
+	}
+}
+
+// argChoices completes the first argument from choices.
+func argChoices(choices ...string) func(int) []string {
+	return func(i int) []string {
+		if i == 0 {
+			return choices
+		}
+		return nil
+	}
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// dumpMap renders the whole floor with mini-map glyphs, one row per line,
+// with the player's arrow.
+func dumpMap(gameMap *engine.GameMap, player *engine.Player) string {
+	if gameMap == nil {
+		return ""
+	}
+	px, py := playerCell(player)
+	var b strings.Builder
+	for y := 0; y < gameMap.Height; y++ {
+		for x := 0; x < gameMap.Width; x++ {
+			r := miniMapGlyph(gameMap, x, y)
+			if player != nil && x == px && y == py {
+				r = playerArrow(player.Angle)
+			}
+			b.WriteRune(r)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func (g *Game) teleportToDepth(depth int) bool {
+	if g == nil || g.FloorManager == nil {
+		return false
+	}
+	if depth < 1 {
+		depth = 1
+	}
+
+	// The game follows the new floor through its FloorEntered subscription.
+	f := g.FloorManager.TeleportToDepth(depth)
+	return f != nil && f.Map != nil && g.Player != nil && g.Floor == f
+}
+
+// cheatConsolePanel is the open console: a full-width strip over the top of
+// the view, showing as much scrollback as fits.
+func (g *Game) cheatConsolePanel() hudPanel {
+	p := hudPanel{Name: "console", Anchor: anchorTop, Z: hudZModal + 1, Stretch: true}
+	for h := cheatConsoleRows; h >= 2; h /= 2 {
+		p.Sizes = append(p.Sizes, hudSize{W: compassMinWidth, H: h})
+	}
+	p.Draw = func(c hudCanvas, _ int) {
+		fill := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack)
+		lines := g.cheatConsole.Lines(c.rect.H)
+		for y := 0; y < c.rect.H; y++ {
+			for x := 0; x < c.rect.W; x++ {
+				c.set(x, y, ' ', fill)
+			}
+		}
+		// Bottom-align so the input line is always the last row.
+		c = c.sub(0, c.rect.H-len(lines), c.rect.W, len(lines))
+		last := len(lines) - 1
+		for y, line := range lines[:last] {
+			c.drawString(0, y, line, fill.Foreground(tcell.ColorGray))
+		}
+		// Keep the end of a long input in view, then a block cursor.
+		input := []rune(lines[last])
+		if over := len(input) - (c.rect.W - 1); over > 0 {
+			input = input[over:]
+		}
+		c.drawString(0, last, string(input), fill)
+		c.set(len(input), last, ' ', fill.Reverse(true))
+	}
+	return p
+}
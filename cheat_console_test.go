@@ -0,0 +1,292 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"game/engine"
+	"game/events"
+	"game/world"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func newTestGameForCheats(t *testing.T) *Game {
+	t.Helper()
+
+	fm := world.NewFloorManager()
+	fm.Generator.WithSeed(123)
+	floor := fm.GenerateFirstFloor()
+
+	g := &Game{
+		Running:      true,
+		ShowMiniMap:  true,
+		ShowWatchers: true,
+		CorruptState: world.NewCorruption(),
+		FloorManager: fm,
+		Floor:        floor,
+		GameMap:      floor.Map,
+		Player:       engine.NewPlayerAtCell(floor.SpawnPos.X, floor.SpawnPos.Y, 0),
+	}
+	g.SetEvents(events.NewBus())
+	return g
+}
+
+// cheat opens the console if needed, types line and presses Enter, and
+// returns the console's last output line.
+func cheat(t *testing.T, g *Game, line string) string {
+	t.Helper()
+	if !g.cheatConsoleOpen {
+		g.handleCheatEvent(runeKey('c'))
+	}
+	for _, r := range line {
+		g.handleCheatEvent(runeKey(r))
+	}
+	g.handleCheatEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	out := g.cheatConsole.Output
+	return out[len(out)-1]
+}
+
+func TestCheatConsoleToggles(t *testing.T) {
+	g := newTestGameForCheats(t)
+
+	if got := cheat(t, g, "map"); g.ShowMiniMap || got != "Mini-map off" {
+		t.Fatalf("expected map toggled off, got %v %q", g.ShowMiniMap, got)
+	}
+	cheat(t, g, "map braille")
+	if !g.ShowMiniMap || g.MiniMapMode != miniMapBraille {
+		t.Fatalf("expected braille map, got %v %s", g.ShowMiniMap, g.MiniMapMode)
+	}
+	cheat(t, g, "watchers off")
+	cheat(t, g, "perf on")
+	if g.ShowWatchers || !g.ShowPerf {
+		t.Fatalf("expected watchers off and perf on, got %v %v", g.ShowWatchers, g.ShowPerf)
+	}
+	if got := cheat(t, g, "perf maybe"); got != "usage: perf [on|off]" {
+		t.Fatalf("expected usage for a bad argument, got %q", got)
+	}
+}
+
+func TestCheatConsoleTeleportToDepth(t *testing.T) {
+	g := newTestGameForCheats(t)
+
+	if got := cheat(t, g, "tp 5"); got != "Teleported to depth 5" {
+		t.Fatalf("unexpected output %q", got)
+	}
+	if g.Floor == nil || g.Floor.Depth != 5 {
+		t.Fatalf("expected depth 5 after teleport, got %+v", g.Floor)
+	}
+	for line, want := range map[string]string{
+		"tp 0":    "error: invalid depth",
+		"tp deep": "error: not a number: deep",
+		"tp":      "usage: tp DEPTH",
+	} {
+		if got := cheat(t, g, line); got != want {
+			t.Fatalf("%s: expected %q, got %q", line, want, got)
+		}
+	}
+}
+
+func TestCheatConsoleSeedAndRegen(t *testing.T) {
+	g := newTestGameForCheats(t)
+	cheat(t, g, "tp 3")
+
+	if got := cheat(t, g, "seed"); got != "Seed 123" {
+		t.Fatalf("expected the current seed, got %q", got)
+	}
+	cheat(t, g, "seed 1234")
+	if g.FloorManager.Generator.Seed != 1234 || g.Floor.Depth != 3 {
+		t.Fatalf("expected seed 1234 at depth 3, got %d at %d", g.FloorManager.Generator.Seed, g.Floor.Depth)
+	}
+
+	reseeded := g.Floor
+	g.Floor.Watchers.Spawn()
+	cheat(t, g, "regen")
+	if g.Floor == reseeded || g.Floor.Depth != 3 {
+		t.Fatal("expected regen to build a fresh floor at the same depth")
+	}
+	if dumpMap(g.GameMap, nil) != dumpMap(reseeded.Map, nil) {
+		t.Fatal("expected regen with the same seed to rebuild the same layout")
+	}
+}
+
+func TestCheatConsoleSetsCorruption(t *testing.T) {
+	g := newTestGameForCheats(t)
+
+	if got := cheat(t, g, "corrupt 0.8"); !strings.HasPrefix(got, "Corruption 80%") {
+		t.Fatalf("unexpected output %q", got)
+	}
+	if math.Abs(g.CorruptState.GetLevel()-0.8) > 1e-9 || math.Abs(g.Corruption-0.8) > 1e-9 {
+		t.Fatalf("expected corruption 0.8, got %f / %f", g.CorruptState.GetLevel(), g.Corruption)
+	}
+}
+
+func TestCheatConsoleViewSettings(t *testing.T) {
+	g := newTestGameForCheats(t)
+	if got := cheat(t, g, "fov 90"); got != "error: no view" {
+		t.Fatalf("expected an error without a raycaster, got %q", got)
+	}
+
+	g.Raycaster = engine.NewRaycaster(80, 24)
+	cheat(t, g, "fov 90")
+	if got := g.Raycaster.FOV * 180 / math.Pi; math.Abs(got-90) > 1e-9 {
+		t.Fatalf("expected FOV 90°, got %.2f", got)
+	}
+	if got := cheat(t, g, "fov 500"); got != "FOV 170°" {
+		t.Fatalf("expected FOV clamped, got %q", got)
+	}
+	cheat(t, g, "dist 40")
+	if g.Raycaster.MaxDist != 40 {
+		t.Fatalf("expected render distance 40, got %.0f", g.Raycaster.MaxDist)
+	}
+	for _, line := range []string{"dist NaN", "fov Inf", "corrupt NaN"} {
+		if got := cheat(t, g, line); !strings.HasPrefix(got, "error:") {
+			t.Fatalf("%s: expected an error, got %q", line, got)
+		}
+	}
+	if g.Raycaster.MaxDist != 40 || math.IsNaN(g.Raycaster.FOV) || math.IsNaN(g.Corruption) {
+		t.Fatalf("expected non-finite values to leave the view alone, got FOV %v dist %v", g.Raycaster.FOV, g.Raycaster.MaxDist)
+	}
+}
+
+func TestCheatConsoleSpawnAndReveal(t *testing.T) {
+	g := newTestGameForCheats(t)
+	before := len(g.Floor.Watchers.Watchers)
+
+	cheat(t, g, "spawn watcher")
+	if got := len(g.Floor.Watchers.Watchers); got != before+1 {
+		t.Fatalf("expected %d watchers, got %d", before+1, got)
+	}
+	if got := cheat(t, g, "spawn cultist"); got != "usage: spawn watcher" {
+		t.Fatalf("expected usage for an unknown entity, got %q", got)
+	}
+
+	cheat(t, g, "reveal")
+	if !g.stairsSeen {
+		t.Fatal("expected reveal to mark the stairs seen")
+	}
+}
+
+func TestCheatConsoleDumpWritesMap(t *testing.T) {
+	g := newTestGameForCheats(t)
+	path := filepath.Join(t.TempDir(), "floor.txt")
+
+	cheat(t, g, "dump "+path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read dump: %v", err)
+	}
+	rows := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(rows) != g.GameMap.Height || len([]rune(rows[0])) != g.GameMap.Width {
+		t.Fatalf("expected a %dx%d dump, got %d rows", g.GameMap.Width, g.GameMap.Height, len(rows))
+	}
+	spawn := g.Floor.SpawnPos
+	if got := []rune(rows[spawn.Y])[spawn.X]; got != playerArrow(g.Player.Angle) {
+		t.Fatalf("expected the player arrow at spawn, got %q", got)
+	}
+}
+
+func TestCheatConsoleCompletesAndRecalls(t *testing.T) {
+	g := newTestGameForCheats(t)
+	g.openCheatConsole()
+	tab := tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone)
+
+	g.handleCheatEvent(runeKey('w'))
+	g.handleCheatEvent(tab)
+	if got := string(g.cheatConsole.Input); got != "watchers " {
+		t.Fatalf("expected the command completed, got %q", got)
+	}
+	g.handleCheatEvent(runeKey('o'))
+	g.handleCheatEvent(tab)
+	if got := string(g.cheatConsole.Input); got != "watchers o" {
+		t.Fatalf("expected on/off to stay ambiguous, got %q", got)
+	}
+	g.handleCheatEvent(runeKey('f'))
+	g.handleCheatEvent(tab)
+	g.handleCheatEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if g.ShowWatchers {
+		t.Fatal("expected the completed command to turn watchers off")
+	}
+
+	g.handleCheatEvent(tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone))
+	if got := string(g.cheatConsole.Input); got != "watchers off" {
+		t.Fatalf("expected Up to recall the last command, got %q", got)
+	}
+}
+
+func TestCheatConsolePublishesCheatUsed(t *testing.T) {
+	g := newTestGameForCheats(t)
+	var got []events.CheatUsed
+	events.Subscribe(g.Events, func(e events.CheatUsed) { got = append(got, e) })
+
+	cheat(t, g, "tp 4")
+	cheat(t, g, "tp nowhere")
+	cheat(t, g, "help")
+	if len(got) != 1 || got[0] != (events.CheatUsed{Action: "tp", Detail: "4"}) {
+		t.Fatalf("expected one tp cheat, got %+v", got)
+	}
+}
+
+func TestCheatConsoleKeepsKeysFromTheGame(t *testing.T) {
+	g := newTestGameForCheats(t)
+	g.openCheatConsole()
+	start := *g.Player
+
+	for _, r := range "wasdq" {
+		g.processEvent(runeKey(r))
+	}
+	if !g.Running || g.Player.X != start.X || g.Player.Y != start.Y || g.Player.Angle != start.Angle {
+		t.Fatal("expected typing in the console not to move or quit")
+	}
+	if got := string(g.cheatConsole.Input); got != "wasdq" {
+		t.Fatalf("expected the keys typed into the console, got %q", got)
+	}
+}
+
+func TestCheatConsoleEscapeDoesNotQuitGame(t *testing.T) {
+	g := newTestGameForCheats(t)
+	g.openCheatConsole()
+
+	g.processEvent(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+
+	if !g.Running {
+		t.Fatal("expected game to keep running when closing the console")
+	}
+	if g.cheatConsoleOpen {
+		t.Fatal("expected the console to be closed")
+	}
+}
+
+func TestCheatConsolePanelShowsInputAtTop(t *testing.T) {
+	g := newTestGameForCheats(t)
+	g.Width, g.Height = 40, 20
+	cheat(t, g, "tp 2")
+	for _, r := range "he" {
+		g.handleCheatEvent(runeKey(r))
+	}
+
+	l := layoutHUD(g.Width, g.Height, g.hudPanels())
+	p, ok := l.find("console")
+	if !ok {
+		t.Fatal("expected the console panel")
+	}
+	if p.Rect.W != g.Width || p.Rect.H != cheatConsoleRows {
+		t.Fatalf("expected a full-width %d-row console, got %+v", cheatConsoleRows, p.Rect)
+	}
+	if l.Viewport.H+l.Viewport.Y < g.Height-1 {
+		t.Fatalf("expected the console not to shrink the view, got %+v", l.Viewport)
+	}
+
+	screen := newLayoutTestScreen(t, g.Width, g.Height)
+	l.drawHUD(screen, g.Width, g.Height)
+	last := p.Rect.Y + p.Rect.H - 1
+	if got := screenRow(screen, last, g.Width); !strings.HasPrefix(got, "> he") {
+		t.Fatalf("expected the input line, got %q", got)
+	}
+	if got := screenRow(screen, last-1, g.Width); !strings.HasPrefix(got, "Teleported to depth 2") {
+		t.Fatalf("expected the last output above the input, got %q", got)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"math"
+
+	"game/render"
+)
+
+const (
+	// compassSpan is the arc of bearings shown across the strip.
+	compassSpan = math.Pi
+	// compassMinWidth is the narrowest useful strip: the heading and one
+	// neighbouring tick either side.
+	compassMinWidth = 9
+
+	// From compassLieLevel the compass wobbles away from the true heading,
+	// by up to compassMaxLie at compassSpinLevel; from there it spins.
+	compassLieLevel  = 0.65
+	compassSpinLevel = 0.90
+	compassMaxLie    = math.Pi / 2
+	compassWobble    = 40.0 // ticks per radian of wobble phase
+	compassSpinRate  = 0.05 // radians per tick
+)
+
+// compassMarks are the labelled bearings, in the player's angle convention
+// (0 is east, angles grow towards south).
+var compassMarks = []struct {
+	bearing float64
+	r       rune
+}{
+	{0, 'E'},
+	{math.Pi / 4, '+'},
+	{math.Pi / 2, 'S'},
+	{3 * math.Pi / 4, '+'},
+	{math.Pi, 'W'},
+	{5 * math.Pi / 4, '+'},
+	{3 * math.Pi / 2, 'N'},
+	{7 * math.Pi / 4, '+'},
+}
+
+// wrapAngle maps an angle difference into [-π, π).
+func wrapAngle(a float64) float64 {
+	a = math.Mod(a+math.Pi, 2*math.Pi)
+	if a < 0 {
+		a += 2 * math.Pi
+	}
+	return a - math.Pi
+}
+
+// compassHeading is the heading the compass shows for the true angle. Past
+// compassLieLevel corruption it drifts back and forth, and past
+// compassSpinLevel it turns on its own.
+func compassHeading(angle, level float64, ticks int) float64 {
+	switch {
+	case level >= compassSpinLevel:
+		return angle + float64(ticks)*compassSpinRate
+	case level >= compassLieLevel:
+		strength := (level - compassLieLevel) / (compassSpinLevel - compassLieLevel)
+		return angle + math.Sin(float64(ticks)/compassWobble)*strength*compassMaxLie
+	}
+	return angle
+}
+
+// compassColumn is the strip column for bearing, or -1 when it is outside the
+// strip.
+func compassColumn(heading, bearing float64, width int) int {
+	col := width/2 + int(math.Round(wrapAngle(bearing-heading)/compassSpan*float64(width)))
+	if col < 0 || col >= width {
+		return -1
+	}
+	return col
+}
+
+// buildCompass renders a width-wide strip centred on heading, with a
+// marker for the stairs at stairsBearing when showStairs is set.
+func buildCompass(heading float64, width int, stairsBearing float64, showStairs bool) string {
+	if width <= 0 {
+		return ""
+	}
+	strip := make([]rune, width)
+	for i := range strip {
+		strip[i] = '-'
+	}
+	for _, m := range compassMarks {
+		if col := compassColumn(heading, m.bearing, width); col >= 0 {
+			strip[col] = m.r
+		}
+	}
+	if showStairs {
+		if col := compassColumn(heading, stairsBearing, width); col >= 0 {
+			strip[col] = render.StairsChar
+		}
+	}
+	return string(strip)
+}
+
+// stairsBearing is the angle from the player to the centre of the stairs.
+func (g *Game) stairsBearing() float64 {
+	stairs := g.Floor.StairsPos
+	return math.Atan2(float64(stairs.Y)+0.5-g.Player.Y, float64(stairs.X)+0.5-g.Player.X)
+}
+
+// noteStairsSeen remembers once the stairs have been in view on this floor.
+func (g *Game) noteStairsSeen() {
+	if g.stairsSeen || g.Raycaster == nil {
+		return
+	}
+	stairs := g.Floor.StairsPos
+	g.stairsSeen = g.Raycaster.VisibleCells(g.Player, g.GameMap).Contains(stairs.X, stairs.Y)
+}
+
+// compassPanel is the compass strip under the status line.
+func (g *Game) compassPanel() (hudPanel, bool) {
+	if g.Player == nil || g.Floor == nil {
+		return hudPanel{}, false
+	}
+	ticks := 0
+	if g.CorruptState != nil {
+		ticks = g.CorruptState.Ticks
+	}
+	heading := compassHeading(g.Player.Angle, g.Corruption, ticks)
+	bearing := g.stairsBearing()
+	p := hudPanel{
+		Name:    "compass",
+		Anchor:  anchorTop,
+		Z:       hudZStatus,
+		Reserve: true,
+		Stretch: true,
+		Sizes:   []hudSize{{W: compassMinWidth, H: 1}},
+		Draw: func(c hudCanvas, _ int) {
+			strip := []rune(buildCompass(heading, c.rect.W, bearing, g.stairsSeen))
+			for x, r := range strip {
+				style := dimStyle
+				switch r {
+				case 'N', 'E', 'S', 'W':
+					style = hudStyle
+				case render.StairsChar:
+					style = stairsStyle
+				}
+				if x == len(strip)/2 {
+					style = style.Reverse(true)
+				}
+				c.set(x, 0, r, style)
+			}
+		},
+	}
+	return p, true
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"game/engine"
+	"game/world"
+)
+
+func TestBuildCompassCentresHeading(t *testing.T) {
+	cases := []struct {
+		name  string
+		angle float64
+		want  string
+	}{
+		{"east", 0, "N---+---E---+---S"},
+		{"south", math.Pi / 2, "E---+---S---+---W"},
+		{"west", math.Pi, "S---+---W---+---N"},
+		{"north", -math.Pi / 2, "W---+---N---+---E"},
+		{"north-east", -math.Pi / 4, "----N---+---E----"},
+	}
+	for _, c := range cases {
+		got := buildCompass(c.angle, 17, 0, false)
+		// Half-way marks land on both edges; compare the unambiguous middle.
+		if got[1:16] != c.want[1:16] {
+			t.Errorf("%s: compass = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestBuildCompassHandlesArbitraryAngles(t *testing.T) {
+	// A few degrees right of north puts N just left of centre.
+	got := []rune(buildCompass(-math.Pi/2+0.2, 31, 0, false))
+	if i := strings.IndexRune(string(got), 'N'); i >= 15 || i < 12 {
+		t.Fatalf("expected N just left of centre, got %q", string(got))
+	}
+	// Angles past a full turn wrap.
+	if a, b := buildCompass(0.3, 31, 0, false), buildCompass(0.3+4*math.Pi, 31, 0, false); a != b {
+		t.Fatalf("expected wrapped angles to match: %q vs %q", a, b)
+	}
+}
+
+func TestBuildCompassMarksStairsBearing(t *testing.T) {
+	if got := buildCompass(0, 17, math.Pi/4, false); strings.ContainsRune(got, 'v') {
+		t.Fatalf("expected no stairs marker before the stairs are seen, got %q", got)
+	}
+	got := buildCompass(0, 17, math.Pi/4, true)
+	if got[12] != 'v' {
+		t.Fatalf("expected stairs marker 45° right of centre, got %q", got)
+	}
+	if got := buildCompass(0, 17, math.Pi, true); strings.ContainsRune(got, 'v') {
+		t.Fatalf("expected stairs behind the player to be off the strip, got %q", got)
+	}
+}
+
+func TestCompassHeadingLiesUnderCorruption(t *testing.T) {
+	if got := compassHeading(1, compassLieLevel-0.01, 500); got != 1 {
+		t.Fatalf("expected an honest compass below %.2f corruption, got %.3f", compassLieLevel, got)
+	}
+
+	lie := compassHeading(1, 0.8, 60)
+	if lie == 1 || math.Abs(lie-1) > compassMaxLie {
+		t.Fatalf("expected a bounded lie at 80%% corruption, got %.3f", lie)
+	}
+	if compassHeading(1, 0.8, 60) != lie {
+		t.Fatal("expected the lie to be deterministic")
+	}
+
+	a, b := compassHeading(1, 0.95, 10), compassHeading(1, 0.95, 20)
+	if math.Abs(wrapAngle(b-a)-10*compassSpinRate) > 1e-9 {
+		t.Fatalf("expected the compass to spin at high corruption, got %.3f then %.3f", a, b)
+	}
+}
+
+func TestNoteStairsSeen(t *testing.T) {
+	m := newMiniMapTestMap() // stairs at (3, 2)
+	g := &Game{
+		Floor:     &world.Floor{Map: m, StairsPos: world.Point{X: 3, Y: 2}},
+		GameMap:   m,
+		Raycaster: engine.NewRaycaster(40, 12),
+		Player:    engine.NewPlayerAtCell(1, 2, math.Pi),
+	}
+	g.noteStairsSeen()
+	if g.stairsSeen {
+		t.Fatal("expected the stairs unseen while facing away")
+	}
+	g.Player.Angle = 0
+	g.noteStairsSeen()
+	if !g.stairsSeen {
+		t.Fatal("expected the stairs seen once faced")
+	}
+	g.Player.Angle = math.Pi
+	g.noteStairsSeen()
+	if !g.stairsSeen {
+		t.Fatal("expected the stairs to stay seen after turning away")
+	}
+}
+
+func TestStairsSeenResetsPerFloor(t *testing.T) {
+	g := newTestGameForCheats(t)
+	g.stairsSeen = true
+	g.teleportToDepth(2)
+	if g.stairsSeen {
+		t.Fatal("expected a new floor to forget the stairs")
+	}
+}
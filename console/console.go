@@ -0,0 +1,336 @@
+// Package console is a one-line command prompt for the HUD: typed commands
+// with arguments, a history, tab completion against registered commands and
+// a scrollback of their output. Like package menu it knows nothing about
+// drawing; Lines returns the text to show.
+package console
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	// MaxOutput is how many scrollback lines are kept.
+	MaxOutput = 100
+	// MaxHistory is how many entered lines are kept for Up/Down.
+	MaxHistory = 50
+	// Prompt starts the input line and echoed commands.
+	Prompt = "> "
+)
+
+// ErrUsage is returned by a command's Run when its arguments are wrong; the
+// console prints the command's usage line.
+var ErrUsage = errors.New("usage")
+
+// Result tells the caller what a key did.
+type Result int
+
+const (
+	// Ignored means the key meant nothing to the console.
+	Ignored Result = iota
+	// Handled means the console used the key.
+	Handled
+	// Closed means Esc was pressed to dismiss the console.
+	Closed
+)
+
+// Command is one registered command.
+type Command struct {
+	Name string
+	// Usage is the synopsis, e.g. "tp DEPTH".
+	Usage string
+	Help  string
+	// Complete, if set, lists candidates for argument i (from 0).
+	Complete func(i int) []string
+	// Run does the command. Its output, if any, goes to the scrollback; an
+	// error is printed instead.
+	Run func(args []string) (string, error)
+	// SideEffect marks commands that reach outside the session, such as
+	// writing files. Hosts re-running logged input should skip them.
+	SideEffect bool
+}
+
+// Console is the prompt state. The zero value is not usable; call New.
+type Console struct {
+	// Input is the line being typed.
+	Input  []rune
+	Output []string
+
+	commands []*Command // sorted by name
+	history  []string
+	histPos  int    // index into history while browsing; len(history) when not
+	draft    []rune // the typed line put aside while browsing history
+}
+
+// New returns a console with the built-in help and clear commands.
+func New() *Console {
+	c := &Console{}
+	c.Register(&Command{
+		Name:  "help",
+		Usage: "help [COMMAND]",
+		Help:  "list commands, or describe one",
+		Complete: func(i int) []string {
+			if i == 0 {
+				return c.Names()
+			}
+			return nil
+		},
+		Run: c.help,
+	}, &Command{
+		Name:  "clear",
+		Usage: "clear",
+		Help:  "clear the scrollback",
+		Run: func([]string) (string, error) {
+			c.Output = nil
+			return "", nil
+		},
+	})
+	return c
+}
+
+// Register adds commands, replacing any with the same name.
+func (c *Console) Register(cmds ...*Command) {
+	for _, cmd := range cmds {
+		i := sort.Search(len(c.commands), func(i int) bool { return c.commands[i].Name >= cmd.Name })
+		if i < len(c.commands) && c.commands[i].Name == cmd.Name {
+			c.commands[i] = cmd
+			continue
+		}
+		c.commands = append(c.commands, nil)
+		copy(c.commands[i+1:], c.commands[i:])
+		c.commands[i] = cmd
+	}
+}
+
+// Lookup returns the named command, or nil.
+func (c *Console) Lookup(name string) *Command {
+	i := sort.Search(len(c.commands), func(i int) bool { return c.commands[i].Name >= name })
+	if i < len(c.commands) && c.commands[i].Name == name {
+		return c.commands[i]
+	}
+	return nil
+}
+
+// Names lists the registered commands in order.
+func (c *Console) Names() []string {
+	names := make([]string, len(c.commands))
+	for i, cmd := range c.commands {
+		names[i] = cmd.Name
+	}
+	return names
+}
+
+// Reset clears the input line, ready to open the console again. History and
+// scrollback are kept.
+func (c *Console) Reset() {
+	c.Input = c.Input[:0]
+	c.draft = nil
+	c.histPos = len(c.history)
+}
+
+// HandleKey edits the input line: Enter runs it, Tab completes, Up/Down walk
+// the history, Backspace and Ctrl-U delete, and Esc closes.
+func (c *Console) HandleKey(ev *tcell.EventKey) Result {
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		return Closed
+	case tcell.KeyEnter:
+		line := string(c.Input)
+		c.Reset()
+		c.Exec(line)
+	case tcell.KeyTab:
+		c.Complete()
+	case tcell.KeyUp:
+		c.browse(-1)
+	case tcell.KeyDown:
+		c.browse(1)
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(c.Input) > 0 {
+			c.Input = c.Input[:len(c.Input)-1]
+		}
+	case tcell.KeyCtrlU:
+		c.Input = c.Input[:0]
+	case tcell.KeyRune:
+		if r := ev.Rune(); unicode.IsPrint(r) {
+			c.Input = append(c.Input, r)
+		}
+	default:
+		return Ignored
+	}
+	return Handled
+}
+
+// Exec runs line as if typed, echoing it and its result to the scrollback.
+func (c *Console) Exec(line string) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return
+	}
+	line = strings.Join(fields, " ")
+	if n := len(c.history); n == 0 || c.history[n-1] != line {
+		c.history = append(c.history, line)
+		if len(c.history) > MaxHistory {
+			c.history = c.history[len(c.history)-MaxHistory:]
+		}
+	}
+	c.histPos = len(c.history)
+	c.Print(Prompt + line)
+
+	cmd := c.Lookup(fields[0])
+	if cmd == nil {
+		c.Print("unknown command: " + fields[0] + " (try help)")
+		return
+	}
+	out, err := cmd.Run(fields[1:])
+	switch {
+	case errors.Is(err, ErrUsage):
+		c.Print("usage: " + cmd.Usage)
+	case err != nil:
+		c.Print("error: " + err.Error())
+	case out != "":
+		c.Print(strings.Split(out, "\n")...)
+	}
+}
+
+// Print appends lines to the scrollback.
+func (c *Console) Print(lines ...string) {
+	c.Output = append(c.Output, lines...)
+	if len(c.Output) > MaxOutput {
+		c.Output = c.Output[len(c.Output)-MaxOutput:]
+	}
+}
+
+// Complete completes the word being typed: command names for the first
+// word, the command's Complete for the rest. A single match is filled in;
+// several are filled to their common prefix and, if that adds nothing,
+// printed.
+func (c *Console) Complete() {
+	line := string(c.Input)
+	fields := strings.Fields(line)
+	word := ""
+	if len(fields) > 0 && !strings.HasSuffix(line, " ") {
+		word = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+
+	var candidates []string
+	if len(fields) == 0 {
+		candidates = c.Names()
+	} else if cmd := c.Lookup(fields[0]); cmd != nil && cmd.Complete != nil {
+		candidates = cmd.Complete(len(fields) - 1)
+	}
+	var matches []string
+	for _, s := range candidates {
+		if strings.HasPrefix(s, word) {
+			matches = append(matches, s)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return
+	case 1:
+		c.Input = append(c.Input, []rune(matches[0][len(word):]+" ")...)
+		return
+	}
+	if prefix := commonPrefix(matches); len(prefix) > len(word) {
+		c.Input = append(c.Input, []rune(prefix[len(word):])...)
+		return
+	}
+	c.Print(strings.Join(matches, "  "))
+}
+
+func (c *Console) browse(delta int) {
+	pos := c.histPos + delta
+	if pos < 0 || pos > len(c.history) {
+		return
+	}
+	if c.histPos == len(c.history) {
+		c.draft = append(c.draft[:0], c.Input...)
+	}
+	c.histPos = pos
+	if pos == len(c.history) {
+		c.Input = append(c.Input[:0], c.draft...)
+		return
+	}
+	c.Input = []rune(c.history[pos])
+}
+
+func (c *Console) help(args []string) (string, error) {
+	switch len(args) {
+	case 0:
+		return "commands: " + strings.Join(c.Names(), " "), nil
+	case 1:
+		cmd := c.Lookup(args[0])
+		if cmd == nil {
+			return "", errors.New("no command " + args[0])
+		}
+		return cmd.Usage + " - " + cmd.Help, nil
+	}
+	return "", ErrUsage
+}
+
+// Lines is the last n-1 scrollback lines followed by the input line.
+func (c *Console) Lines(n int) []string {
+	if n < 1 {
+		return nil
+	}
+	out := c.Output
+	if len(out) > n-1 {
+		out = out[len(out)-(n-1):]
+	}
+	lines := append([]string(nil), out...)
+	return append(lines, Prompt+string(c.Input))
+}
+
+// Int parses a command argument as an integer.
+func Int(arg string) (int, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, errors.New("not a number: " + arg)
+	}
+	return n, nil
+}
+
+// Float parses a command argument as a finite number; NaN and infinities
+// would slip past the callers' range clamps.
+func Float(arg string) (float64, error) {
+	f, err := strconv.ParseFloat(arg, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, errors.New("not a number: " + arg)
+	}
+	return f, nil
+}
+
+// Switch parses an optional on/off argument: none flips cur.
+func Switch(args []string, cur bool) (bool, error) {
+	if len(args) == 0 {
+		return !cur, nil
+	}
+	if len(args) > 1 {
+		return false, ErrUsage
+	}
+	switch args[0] {
+	case "on", "1", "true":
+		return true, nil
+	case "off", "0", "false":
+		return false, nil
+	}
+	return false, ErrUsage
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,193 @@
+package console
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func key(k tcell.Key) *tcell.EventKey {
+	return tcell.NewEventKey(k, 0, tcell.ModNone)
+}
+
+func typeLine(c *Console, s string) {
+	for _, r := range s {
+		c.HandleKey(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+}
+
+func newTestConsole(ran *[]string) *Console {
+	c := New()
+	c.Register(&Command{
+		Name:     "tp",
+		Usage:    "tp DEPTH",
+		Help:     "teleport",
+		Complete: func(i int) []string { return []string{"10", "20", "200"} },
+		Run: func(args []string) (string, error) {
+			if len(args) != 1 {
+				return "", ErrUsage
+			}
+			if _, err := Int(args[0]); err != nil {
+				return "", err
+			}
+			*ran = append(*ran, args[0])
+			return "went to " + args[0] + "\nok", nil
+		},
+	}, &Command{Name: "toggle", Usage: "toggle", Run: func([]string) (string, error) { return "", nil }})
+	return c
+}
+
+func TestConsoleRunsCommandsAndReportsErrors(t *testing.T) {
+	var ran []string
+	c := newTestConsole(&ran)
+
+	typeLine(c, "  tp   7 ")
+	c.HandleKey(key(tcell.KeyEnter))
+	typeLine(c, "tp")
+	c.HandleKey(key(tcell.KeyEnter))
+	typeLine(c, "tp x")
+	c.HandleKey(key(tcell.KeyEnter))
+	typeLine(c, "fly")
+	c.HandleKey(key(tcell.KeyEnter))
+	c.HandleKey(key(tcell.KeyEnter))
+
+	want := []string{
+		"> tp 7", "went to 7", "ok",
+		"> tp", "usage: tp DEPTH",
+		"> tp x", "error: not a number: x",
+		"> fly", "unknown command: fly (try help)",
+	}
+	if !reflect.DeepEqual(c.Output, want) {
+		t.Fatalf("output = %q, want %q", c.Output, want)
+	}
+	if !reflect.DeepEqual(ran, []string{"7"}) || len(c.Input) != 0 {
+		t.Fatalf("expected one run and a cleared input, got %v %q", ran, string(c.Input))
+	}
+}
+
+func TestConsoleHelpAndClear(t *testing.T) {
+	var ran []string
+	c := newTestConsole(&ran)
+
+	c.Exec("help")
+	c.Exec("help tp")
+	want := []string{"> help", "commands: clear help toggle tp", "> help tp", "tp DEPTH - teleport"}
+	if !reflect.DeepEqual(c.Output, want) {
+		t.Fatalf("output = %q, want %q", c.Output, want)
+	}
+	c.Exec("clear")
+	if len(c.Output) != 0 {
+		t.Fatalf("expected clear to empty the scrollback, got %q", c.Output)
+	}
+}
+
+func TestConsoleCompletes(t *testing.T) {
+	var ran []string
+	c := newTestConsole(&ran)
+
+	typeLine(c, "t")
+	c.HandleKey(key(tcell.KeyTab))
+	if got := string(c.Input); got != "t" {
+		t.Fatalf("expected tp/toggle to stay ambiguous, got %q", got)
+	}
+	if got := c.Output[len(c.Output)-1]; got != "toggle  tp" {
+		t.Fatalf("expected the candidates printed, got %q", got)
+	}
+
+	typeLine(c, "p")
+	c.HandleKey(key(tcell.KeyTab))
+	if got := string(c.Input); got != "tp " {
+		t.Fatalf("expected tp completed, got %q", got)
+	}
+	typeLine(c, "2")
+	c.HandleKey(key(tcell.KeyTab))
+	if got := string(c.Input); got != "tp 20" {
+		t.Fatalf("expected the common prefix of 20 and 200, got %q", got)
+	}
+}
+
+func TestConsoleHistory(t *testing.T) {
+	var ran []string
+	c := newTestConsole(&ran)
+	c.Exec("tp 1")
+	c.Exec("tp 2")
+	c.Exec("tp 2")
+
+	typeLine(c, "dra")
+	c.HandleKey(key(tcell.KeyUp))
+	if got := string(c.Input); got != "tp 2" {
+		t.Fatalf("expected the last command, got %q", got)
+	}
+	c.HandleKey(key(tcell.KeyUp))
+	c.HandleKey(key(tcell.KeyUp))
+	if got := string(c.Input); got != "tp 1" {
+		t.Fatalf("expected repeats collapsed and the oldest kept, got %q", got)
+	}
+	c.HandleKey(key(tcell.KeyDown))
+	c.HandleKey(key(tcell.KeyDown))
+	if got := string(c.Input); got != "dra" {
+		t.Fatalf("expected the draft back, got %q", got)
+	}
+
+	c.HandleKey(key(tcell.KeyBackspace2))
+	c.HandleKey(key(tcell.KeyCtrlU))
+	if len(c.Input) != 0 {
+		t.Fatalf("expected Ctrl-U to clear the line, got %q", string(c.Input))
+	}
+	if got := c.HandleKey(key(tcell.KeyEscape)); got != Closed {
+		t.Fatalf("expected Esc to close, got %v", got)
+	}
+}
+
+func TestConsoleLinesAndLimits(t *testing.T) {
+	c := New()
+	for i := 0; i < MaxOutput+5; i++ {
+		c.Print("line")
+	}
+	if len(c.Output) != MaxOutput {
+		t.Fatalf("expected scrollback capped at %d, got %d", MaxOutput, len(c.Output))
+	}
+
+	c.Output = []string{"a", "b", "c"}
+	typeLine(c, "x")
+	if got, want := c.Lines(3), []string{"b", "c", "> x"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("lines = %q, want %q", got, want)
+	}
+	if got, want := c.Lines(5), []string{"a", "b", "c", "> x"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("lines = %q, want %q", got, want)
+	}
+}
+
+func TestSwitch(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		cur  bool
+		want bool
+		err  bool
+	}{
+		{nil, true, false, false},
+		{[]string{"on"}, false, true, false},
+		{[]string{"off"}, true, false, false},
+		{[]string{"maybe"}, true, false, true},
+		{[]string{"on", "off"}, true, false, true},
+	} {
+		got, err := Switch(tc.args, tc.cur)
+		if (err != nil) != tc.err || (err == nil && got != tc.want) {
+			t.Fatalf("Switch(%v, %v) = %v, %v", tc.args, tc.cur, got, err)
+		}
+	}
+}
+
+func TestFloatRejectsNonFinite(t *testing.T) {
+	for _, arg := range []string{"0.5", "-3", "1e2"} {
+		if _, err := Float(arg); err != nil {
+			t.Fatalf("Float(%q): %v", arg, err)
+		}
+	}
+	for _, arg := range []string{"", "x", "NaN", "nan", "Inf", "-Inf", "+infinity", "1e400"} {
+		if f, err := Float(arg); err == nil {
+			t.Fatalf("Float(%q) = %v, expected an error", arg, f)
+		}
+	}
+}
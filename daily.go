@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"game/world"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Daily runs share a seed per UTC date and a fixed floor size, so everyone
+// plays the same floors.
+const (
+	dailyFloorW = world.DefaultMapWidth
+	dailyFloorH = world.DefaultMapHeight
+
+	dailyDateLayout = "2006-01-02"
+	// dailyVerifyLookback is how many days back a replay's seed is matched
+	// against daily seeds when nothing names its date.
+	dailyVerifyLookback = 366
+)
+
+// dailyDate is the UTC date string for t.
+func dailyDate(t time.Time) string {
+	return t.UTC().Format(dailyDateLayout)
+}
+
+// dailySeed derives the FloorManager seed for a date string.
+func dailySeed(date string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("abyss-daily:" + date))
+	return int64(h.Sum64())
+}
+
+// dailyDateForSeed finds the date within dailyVerifyLookback days of today
+// whose daily seed is seed.
+func dailyDateForSeed(seed int64, today time.Time) (string, bool) {
+	for i := 0; i <= dailyVerifyLookback; i++ {
+		date := dailyDate(today.AddDate(0, 0, -i))
+		if seed == dailySeed(date) {
+			return date, true
+		}
+	}
+	return "", false
+}
+
+func dailyReplayFilename(date string, ts time.Time) string {
+	return fmt.Sprintf("daily-%s-%s.replay", date, ts.UTC().Format("150405"))
+}
+
+// dailyResult is the shareable outcome of a daily run. Check binds the other
+// fields to the exact input log, so a result can only be reproduced by
+// replaying that log.
+type dailyResult struct {
+	Date  string
+	Depth int
+	Ticks int
+	Seed  string // short hash of the day's seed
+	Check string
+}
+
+func newDailyResult(date string, rp *Replay, depth int) (dailyResult, error) {
+	var log bytes.Buffer
+	if err := encodeReplay(&log, rp); err != nil {
+		return dailyResult{}, err
+	}
+	seed := sha256.Sum256([]byte(strconv.FormatInt(rp.Seed, 10)))
+	r := dailyResult{
+		Date:  date,
+		Depth: depth,
+		Ticks: rp.Frames,
+		Seed:  hex.EncodeToString(seed[:4]),
+	}
+	logSum := sha256.Sum256(log.Bytes())
+	check := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d|%d|%x", r.Date, r.Seed, r.Depth, r.Ticks, logSum)))
+	r.Check = hex.EncodeToString(check[:4])
+	return r, nil
+}
+
+// String formats r as a single line to paste, e.g.
+// "abyss-daily 2026-10-19 depth=12 time=71.93s ticks=4316 seed=3f9a12bc check=7d21aa90".
+func (r dailyResult) String() string {
+	secs := float64(r.Ticks) / ticksPerSecond
+	return fmt.Sprintf("abyss-daily %s depth=%d time=%.2fs ticks=%d seed=%s check=%s", r.Date, r.Depth, secs, r.Ticks, r.Seed, r.Check)
+}
+
+// parseDailyResult reads a line produced by dailyResult.String. The time
+// field is informational; ticks is authoritative.
+func parseDailyResult(s string) (dailyResult, error) {
+	fields := strings.Fields(strings.TrimSpace(s))
+	if len(fields) != 7 || fields[0] != "abyss-daily" {
+		return dailyResult{}, errors.New("not an abyss-daily result")
+	}
+	r := dailyResult{Date: fields[1]}
+	if _, err := time.Parse(dailyDateLayout, r.Date); err != nil {
+		return dailyResult{}, fmt.Errorf("bad date %q", r.Date)
+	}
+	for _, f := range fields[2:] {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok {
+			return dailyResult{}, fmt.Errorf("bad field %q", f)
+		}
+		var err error
+		switch key {
+		case "depth":
+			r.Depth, err = strconv.Atoi(value)
+		case "ticks":
+			r.Ticks, err = strconv.Atoi(value)
+		case "seed":
+			r.Seed = value
+		case "check":
+			r.Check = value
+		case "time":
+		default:
+			err = fmt.Errorf("unknown field")
+		}
+		if err != nil {
+			return dailyResult{}, fmt.Errorf("bad field %q: %v", f, err)
+		}
+	}
+	return r, nil
+}
+
+// simulateReplay runs rp to completion on an off-screen game with daily rules
+// and returns the final game state.
+func simulateReplay(rp *Replay) (*Game, error) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		return nil, err
+	}
+	defer screen.Fini()
+	screen.SetSize(120, 40)
+
+	g := NewGameWithSeed(screen, rp.FloorW, rp.FloorH, rp.Seed)
+	g.daily = true
+	player := newReplayPlayer(rp, 1)
+	for !player.done {
+		player.advance(g)
+	}
+	return g, nil
+}
+
+// dailyResultFor simulates rp and builds its result for date.
+func dailyResultFor(date string, rp *Replay) (dailyResult, error) {
+	if rp.Seed != dailySeed(date) {
+		return dailyResult{}, fmt.Errorf("replay is not the %s daily", date)
+	}
+	if rp.FloorW != dailyFloorW || rp.FloorH != dailyFloorH {
+		return dailyResult{}, fmt.Errorf("replay floor size %dx%d is not the daily %dx%d", rp.FloorW, rp.FloorH, dailyFloorW, dailyFloorH)
+	}
+	g, err := simulateReplay(rp)
+	if err != nil {
+		return dailyResult{}, err
+	}
+	return newDailyResult(date, rp, g.Stats.Deepest)
+}
+
+// verifyDaily re-simulates rp and checks it against claim. With no claim it
+// looks for the replay's date among recent days and returns its result.
+func verifyDaily(rp *Replay, claim string, today time.Time) (dailyResult, error) {
+	if claim == "" {
+		date, ok := dailyDateForSeed(rp.Seed, today)
+		if !ok {
+			return dailyResult{}, errors.New("replay is not a recent daily run")
+		}
+		return dailyResultFor(date, rp)
+	}
+
+	want, err := parseDailyResult(claim)
+	if err != nil {
+		return dailyResult{}, err
+	}
+	got, err := dailyResultFor(want.Date, rp)
+	if err != nil {
+		return dailyResult{}, err
+	}
+	if got != want {
+		return got, fmt.Errorf("claim does not match the replay (simulated: %s)", got)
+	}
+	return got, nil
+}
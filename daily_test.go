@@ -0,0 +1,124 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestDailySeedIsStablePerUTCDate(t *testing.T) {
+	morning := time.Date(2026, 3, 1, 0, 30, 0, 0, time.UTC)
+	evening := time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)
+	if dailyDate(morning) != "2026-03-01" || dailyDate(evening) != dailyDate(morning) {
+		t.Fatalf("unexpected dates %s %s", dailyDate(morning), dailyDate(evening))
+	}
+	west := time.Date(2026, 2, 28, 20, 0, 0, 0, time.FixedZone("UTC-5", -5*3600))
+	if dailyDate(west) != "2026-03-01" {
+		t.Fatalf("expected the UTC date, got %s", dailyDate(west))
+	}
+	if dailySeed("2026-03-01") != dailySeed("2026-03-01") || dailySeed("2026-03-01") == dailySeed("2026-03-02") {
+		t.Fatal("expected one stable seed per date")
+	}
+
+	date, ok := dailyDateForSeed(dailySeed("2026-02-20"), morning)
+	if !ok || date != "2026-02-20" {
+		t.Fatalf("expected to find 2026-02-20, got %q %v", date, ok)
+	}
+	if _, ok := dailyDateForSeed(12345, morning); ok {
+		t.Fatal("expected an arbitrary seed not to be a daily")
+	}
+}
+
+func TestDailyResultRoundTrip(t *testing.T) {
+	r := dailyResult{Date: "2026-03-01", Depth: 12, Ticks: 4316, Seed: "3f9a12bc", Check: "7d21aa90"}
+	s := r.String()
+	if !strings.Contains(s, "time=71.93s") {
+		t.Fatalf("expected time derived from ticks, got %q", s)
+	}
+	got, err := parseDailyResult(s)
+	if err != nil || got != r {
+		t.Fatalf("round trip failed: %+v %v", got, err)
+	}
+	for _, bad := range []string{"", "abyss-daily 2026-03-01", "abyss-daily tomorrow depth=1 time=1s ticks=1 seed=a check=b", "abyss-daily 2026-03-01 depth=x time=1s ticks=1 seed=a check=b"} {
+		if _, err := parseDailyResult(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+// playDaily runs a headless daily game for the given number of frames,
+// cycling through keys, and returns its recording.
+func playDaily(t *testing.T, date string, frames int, keys string) (*Game, *Replay) {
+	t.Helper()
+	seed := dailySeed(date)
+	g := newHeadlessGame(t, dailyFloorW, dailyFloorH, seed)
+	g.daily = true
+	g.recorder = newReplayRecorder(seed, dailyFloorW, dailyFloorH)
+	for frame := 0; frame < frames; frame++ {
+		if frame%3 == 0 {
+			r := rune(keys[(frame/3)%len(keys)])
+			g.processEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+		}
+		g.update()
+	}
+	return g, g.recorder.finish(g.Frame)
+}
+
+func TestDailyVerifyConfirmsHonestResult(t *testing.T) {
+	const date = "2026-03-01"
+	live, rp := playDaily(t, date, 300, "wwdwwawcwst")
+	if live.cheatConsoleOpen {
+		t.Fatal("expected cheats disabled in daily mode")
+	}
+
+	result, err := dailyResultFor(date, rp)
+	if err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	if result.Depth != live.Stats.Deepest || result.Ticks != live.Frame {
+		t.Fatalf("simulated result %+v disagrees with the live run (depth %d, ticks %d)", result, live.Stats.Deepest, live.Frame)
+	}
+
+	if got, err := verifyDaily(rp, result.String(), time.Now()); err != nil || got != result {
+		t.Fatalf("expected claim to verify, got %+v %v", got, err)
+	}
+	today, _ := time.Parse(dailyDateLayout, date)
+	if got, err := verifyDaily(rp, "", today.Add(36*time.Hour)); err != nil || got != result {
+		t.Fatalf("expected verify without a claim to find the date, got %+v %v", got, err)
+	}
+
+	forged := result
+	forged.Depth += 5
+	if _, err := verifyDaily(rp, forged.String(), time.Now()); err == nil {
+		t.Fatal("expected a forged depth to fail verification")
+	}
+	wrongDay := result
+	wrongDay.Date = "2026-03-02"
+	if _, err := verifyDaily(rp, wrongDay.String(), time.Now()); err == nil {
+		t.Fatal("expected a claim for another day to fail")
+	}
+	other := *rp
+	other.Events = other.Events[:len(other.Events)-1]
+	if _, err := verifyDaily(&other, result.String(), time.Now()); err == nil {
+		t.Fatal("expected an edited input log to fail verification")
+	}
+}
+
+func TestReplaySimulationIsDeterministic(t *testing.T) {
+	_, rp := playDaily(t, "2026-05-05", 600, "wwwdwwwwawwsdwww")
+	a, err := simulateReplay(rp)
+	if err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	b, err := simulateReplay(rp)
+	if err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	if a.Floor.Depth != b.Floor.Depth || a.Frame != b.Frame || a.Corruption != b.Corruption ||
+		a.Player.X != b.Player.X || a.Player.Y != b.Player.Y || a.Player.Angle != b.Player.Angle || a.Player.Steps != b.Player.Steps {
+		t.Fatalf("simulations diverged: depth %d/%d frame %d/%d pos %.1f,%.1f/%.1f,%.1f",
+			a.Floor.Depth, b.Floor.Depth, a.Frame, b.Frame, a.Player.X, a.Player.Y, b.Player.X, b.Player.Y)
+	}
+}
@@ -0,0 +1,75 @@
+package engine
+
+import "github.com/gdamore/tcell/v2"
+
+// FrameCell is a single character cell in a Framebuffer.
+type FrameCell struct {
+	Ch    rune
+	Style tcell.Style
+}
+
+// Framebuffer is an off-screen grid of cells that can be written from
+// multiple goroutines (one per column range) and blitted to a tcell screen.
+type Framebuffer struct {
+	Width  int
+	Height int
+	Cells  []FrameCell
+}
+
+// NewFramebuffer allocates a framebuffer of the given size.
+func NewFramebuffer(width, height int) *Framebuffer {
+	fb := &Framebuffer{}
+	fb.Resize(width, height)
+	return fb
+}
+
+// Resize changes the framebuffer dimensions, reusing the backing storage when possible.
+func (fb *Framebuffer) Resize(width, height int) {
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+	n := width * height
+	if cap(fb.Cells) < n {
+		fb.Cells = make([]FrameCell, n)
+	} else {
+		fb.Cells = fb.Cells[:n]
+	}
+	fb.Width = width
+	fb.Height = height
+}
+
+// Set stores a cell; out-of-bounds writes are ignored.
+func (fb *Framebuffer) Set(x, y int, ch rune, style tcell.Style) {
+	if x < 0 || x >= fb.Width || y < 0 || y >= fb.Height {
+		return
+	}
+	fb.Cells[y*fb.Width+x] = FrameCell{Ch: ch, Style: style}
+}
+
+// Get returns the cell at (x, y), or the zero cell if out of bounds.
+func (fb *Framebuffer) Get(x, y int) FrameCell {
+	if x < 0 || x >= fb.Width || y < 0 || y >= fb.Height {
+		return FrameCell{}
+	}
+	return fb.Cells[y*fb.Width+x]
+}
+
+// Blit copies every cell to the screen. It must be called from the goroutine
+// that owns the screen.
+func (fb *Framebuffer) Blit(screen tcell.Screen) {
+	fb.BlitAt(screen, 0, 0)
+}
+
+// BlitAt copies every cell to the screen with the framebuffer's origin at
+// (originX, originY).
+func (fb *Framebuffer) BlitAt(screen tcell.Screen, originX, originY int) {
+	for y := 0; y < fb.Height; y++ {
+		row := fb.Cells[y*fb.Width : (y+1)*fb.Width]
+		for x, c := range row {
+			screen.SetContent(originX+x, originY+y, c.Ch, nil, c.Style)
+		}
+	}
+}
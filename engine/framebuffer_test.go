@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestFramebufferSetGetIgnoresOutOfBounds(t *testing.T) {
+	fb := NewFramebuffer(4, 3)
+	style := tcell.StyleDefault.Foreground(tcell.ColorRed)
+
+	fb.Set(1, 2, 'x', style)
+	fb.Set(-1, 0, 'y', style)
+	fb.Set(4, 0, 'y', style)
+
+	if got := fb.Get(1, 2); got.Ch != 'x' || got.Style != style {
+		t.Fatalf("unexpected cell %+v", got)
+	}
+	if got := fb.Get(9, 9); got != (FrameCell{}) {
+		t.Fatalf("expected zero cell out of bounds, got %+v", got)
+	}
+}
+
+func TestFramebufferResizeReusesStorage(t *testing.T) {
+	fb := NewFramebuffer(10, 10)
+	backing := &fb.Cells[0]
+
+	fb.Resize(5, 4)
+	if fb.Width != 5 || fb.Height != 4 || len(fb.Cells) != 20 {
+		t.Fatalf("unexpected size %dx%d (%d cells)", fb.Width, fb.Height, len(fb.Cells))
+	}
+	if &fb.Cells[0] != backing {
+		t.Fatal("expected shrinking resize to reuse storage")
+	}
+}
+
+func TestFramebufferBlit(t *testing.T) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("init screen: %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(3, 2)
+
+	fb := NewFramebuffer(3, 2)
+	fb.Set(2, 1, '#', tcell.StyleDefault)
+	fb.Blit(screen)
+
+	if ch, _, _, _ := screen.GetContent(2, 1); ch != '#' {
+		t.Fatalf("expected '#', got %q", ch)
+	}
+}
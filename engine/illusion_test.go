@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIllusionCellsSplitSightAndMovement(t *testing.T) {
+	m := newCorridorMap()
+	m.Cells[2][4] = CellFakeWall
+	m.Cells[1][3] = CellFakeOpen
+
+	if m.BlocksMovement(4, 2) || !m.BlocksSight(4, 2) {
+		t.Fatal("expected a fake wall to block sight only")
+	}
+	if !m.BlocksMovement(3, 1) || m.BlocksSight(3, 1) {
+		t.Fatal("expected a fake opening to block movement only")
+	}
+	if !m.IsWall(3, 1) || m.IsWall(4, 2) {
+		t.Fatal("expected IsWall to follow movement")
+	}
+}
+
+func TestRaysAndStepsTreatIllusionsDifferently(t *testing.T) {
+	m := newCorridorMap()
+	m.Cells[2][4] = CellFakeWall
+	r := NewRaycaster(80, 24)
+
+	p := NewPlayerAtCell(2, 2, 0)
+	if got := r.castRayHits(p, m, 0).WallDist; math.Abs(got-1.5) > 1e-9 {
+		t.Fatalf("expected the ray to stop at the fake wall (1.5), got %f", got)
+	}
+	p.MoveForward(m)
+	p.MoveForward(m)
+	if p.X != 4.5 {
+		t.Fatalf("expected to walk into the fake wall, got x=%f", p.X)
+	}
+
+	// A fake opening in the corridor looks clear but stops the player.
+	m.Cells[2][6] = CellFakeOpen
+	if got := r.castRayHits(p, m, 0).WallDist; math.Abs(got-3.5) > 1e-9 {
+		t.Fatalf("expected the ray to pass the fake opening to the end wall (3.5), got %f", got)
+	}
+	p.MoveForward(m)
+	p.MoveForward(m)
+	if p.X != 5.5 {
+		t.Fatalf("expected the fake opening to block at x=5.5, got %f", p.X)
+	}
+}
@@ -5,6 +5,12 @@ const (
 	CellEmpty  = 0 // walkable space
 	CellWall   = 1 // solid wall
 	CellStairs = 2 // stairs down (for later)
+	CellNote   = 3 // readable lore note (walkable pickup)
+	CellPortal = 4 // solid cell whose linked faces lead elsewhere (see Portals)
+
+	// Illusions: cells that look like one thing and behave like another.
+	CellFakeWall = 5 // renders as a wall, walkable
+	CellFakeOpen = 6 // renders as open floor, solid
 )
 
 // GameMap represents a 2D grid-based level
@@ -12,6 +18,9 @@ type GameMap struct {
 	Width  int
 	Height int
 	Cells  [][]int
+
+	// Portals links faces of CellPortal cells to other cells.
+	Portals map[PortalFace]Portal
 }
 
 // NewTestMap creates a hardcoded 16x16 test map for raycaster development
@@ -91,7 +100,27 @@ func (m *GameMap) GetCell(x, y int) int {
 	return m.Cells[y][x]
 }
 
-// IsWall returns true if the cell at (x, y) is a wall
+// IsWall returns true if the cell at (x, y) is solid to movement: a wall, a
+// portal or a fake opening. Portal faces are only passable through
+// FollowPortals.
 func (m *GameMap) IsWall(x, y int) bool {
-	return m.GetCell(x, y) == CellWall
+	return m.BlocksMovement(x, y)
+}
+
+// BlocksMovement reports whether the player can't walk into (x, y).
+func (m *GameMap) BlocksMovement(x, y int) bool {
+	switch m.GetCell(x, y) {
+	case CellWall, CellPortal, CellFakeOpen:
+		return true
+	}
+	return false
+}
+
+// BlocksSight reports whether rays stop at (x, y): real walls and illusory ones.
+func (m *GameMap) BlocksSight(x, y int) bool {
+	switch m.GetCell(x, y) {
+	case CellWall, CellPortal, CellFakeWall:
+		return true
+	}
+	return false
 }
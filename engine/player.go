@@ -1,11 +1,21 @@
 package engine
 
-import "math"
+import (
+	"math"
+
+	"game/audio"
+)
 
 // Player represents the player's position and view direction
 type Player struct {
 	X, Y  float64 // position in map coordinates
 	Angle float64 // view direction in radians (0 = east, π/2 = south)
+	Steps int     // successful moves, including ones through portals
+	// Noclip lets steps pass through walls (but not off the map).
+	Noclip bool
+
+	// Audio receives footstep and turn events; nil is silent.
+	Audio *audio.Bus
 }
 
 // NewPlayer creates a player at the given position facing the given angle
@@ -39,11 +49,13 @@ const turnAngle = math.Pi / 2
 // RotateLeft turns the player 90 degrees counter-clockwise.
 func (p *Player) RotateLeft() {
 	p.Angle = normalizeAngle(p.Angle - turnAngle)
+	p.Audio.Emit(audio.Turn)
 }
 
 // RotateRight turns the player 90 degrees clockwise.
 func (p *Player) RotateRight() {
 	p.Angle = normalizeAngle(p.Angle + turnAngle)
+	p.Audio.Emit(audio.Turn)
 }
 
 // MoveForward moves the player 1 cell in the direction they're facing if walkable.
@@ -64,31 +76,28 @@ func (p *Player) step(gameMap *GameMap, step int) {
 	gridX := int(math.Floor(p.X))
 	gridY := int(math.Floor(p.Y))
 
-	dx, dy := cardinalStep(p.Angle)
-	newX := gridX + dx*step
-	newY := gridY + dy*step
-
-	if gameMap.GetCell(newX, newY) == CellWall {
-		return
+	dir := DirFromAngle(p.Angle)
+	if step < 0 {
+		dir = dir.Opposite()
+	}
+	dx, dy := dir.Delta()
+
+	// Portals may carry the step elsewhere and turn the player with it.
+	newX, newY, exit, ok := gameMap.FollowPortals(gridX+dx, gridY+dy, dir)
+	if !ok {
+		if !p.Noclip || !gameMap.IsValid(gridX+dx, gridY+dy) {
+			return
+		}
+		newX, newY, exit = gridX+dx, gridY+dy, dir
 	}
 
 	p.X = float64(newX) + 0.5
 	p.Y = float64(newY) + 0.5
-}
-
-func cardinalStep(angle float64) (int, int) {
-	angle = normalizeAngle(angle)
-	dir := int(math.Round(angle/turnAngle)) % 4
-	switch dir {
-	case 0: // east
-		return 1, 0
-	case 1: // south
-		return 0, 1
-	case 2: // west
-		return -1, 0
-	default: // north
-		return 0, -1
+	if turns := dir.TurnsTo(exit); turns != 0 {
+		p.Angle = normalizeAngle(p.Angle + float64(turns)*turnAngle)
 	}
+	p.Steps++
+	p.Audio.Emit(audio.Footstep)
 }
 
 func normalizeAngle(angle float64) float64 {
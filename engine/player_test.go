@@ -3,6 +3,8 @@ package engine
 import (
 	"math"
 	"testing"
+
+	"game/audio"
 )
 
 func TestNewPlayerAtCell(t *testing.T) {
@@ -90,3 +92,44 @@ func TestPlayerMoveForwardSnapsAngleAndNormalizes(t *testing.T) {
 		t.Errorf("expected move north to (8.5,5.5), got (%f,%f)", p.X, p.Y)
 	}
 }
+
+func TestPlayerEmitsFootstepsAndTurns(t *testing.T) {
+	rec := &audio.Recorder{}
+	m := NewTestMap()
+	p := NewPlayerAtCell(1, 1, math.Pi) // west, facing the outer wall
+	p.Audio = audio.NewBus(rec)
+
+	p.MoveForward(m) // blocked
+	if rec.Count(audio.Footstep) != 0 || p.Steps != 0 {
+		t.Fatal("expected no footstep when the step is blocked")
+	}
+	p.MoveBackward(m)
+	if p.Steps != 1 {
+		t.Fatalf("expected 1 step counted, got %d", p.Steps)
+	}
+	p.RotateLeft()
+	p.RotateRight()
+	want := []audio.Event{audio.Footstep, audio.Turn, audio.Turn}
+	if got := rec.Events(); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestPlayerNoclipWalksThroughWallsButNotOffTheMap(t *testing.T) {
+	m := NewTestMap()
+
+	// (1,1) faces the perimeter wall at (0,1) to the west.
+	p := NewPlayerAtCell(1, 1, math.Pi)
+	p.Noclip = true
+	p.MoveForward(m)
+	if p.X != 0.5 || p.Y != 1.5 {
+		t.Fatalf("expected noclip into the wall at (0.5,1.5), got (%f,%f)", p.X, p.Y)
+	}
+	p.MoveForward(m)
+	if p.X != 0.5 || p.Y != 1.5 {
+		t.Fatalf("expected the map edge to stop noclip, got (%f,%f)", p.X, p.Y)
+	}
+	if p.Steps != 1 {
+		t.Fatalf("expected 1 step, got %d", p.Steps)
+	}
+}
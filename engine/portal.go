@@ -0,0 +1,118 @@
+package engine
+
+import "math"
+
+// Dir is a cardinal direction, ordered clockwise from east to match Angle
+// (0 = east, π/2 = south).
+type Dir int
+
+const (
+	DirEast Dir = iota
+	DirSouth
+	DirWest
+	DirNorth
+)
+
+// maxPortalHops bounds how many portals a ray or step may chain through,
+// so linked portals can't loop forever.
+const maxPortalHops = 8
+
+// Delta returns the grid step for d.
+func (d Dir) Delta() (int, int) {
+	switch d.Rotate(0) {
+	case DirEast:
+		return 1, 0
+	case DirSouth:
+		return 0, 1
+	case DirWest:
+		return -1, 0
+	default:
+		return 0, -1
+	}
+}
+
+// Opposite returns the direction facing away from d.
+func (d Dir) Opposite() Dir {
+	return d.Rotate(2)
+}
+
+// Rotate turns d clockwise by quarter turns (negative turns go counter-clockwise).
+func (d Dir) Rotate(turns int) Dir {
+	return Dir(((int(d)+turns)%4 + 4) % 4)
+}
+
+// TurnsTo returns the clockwise quarter turns from d to other, in [0, 3].
+func (d Dir) TurnsTo(other Dir) int {
+	return ((int(other)-int(d))%4 + 4) % 4
+}
+
+// Angle returns d as a view angle in radians.
+func (d Dir) Angle() float64 {
+	return float64(d.Rotate(0)) * turnAngle
+}
+
+// DirFromAngle snaps angle to the nearest cardinal direction.
+func DirFromAngle(angle float64) Dir {
+	return Dir(int(math.Round(normalizeAngle(angle)/turnAngle)) % 4)
+}
+
+// PortalFace identifies one face of a CellPortal cell. Face is the side of the
+// cell, so a ray travelling east enters through the DirWest face.
+type PortalFace struct {
+	X, Y int
+	Face Dir
+}
+
+// Portal is where a linked face leads: stepping into the portal cell through
+// the face puts you in cell (ToX, ToY) travelling Exit.
+type Portal struct {
+	ToX, ToY int
+	Exit     Dir
+}
+
+// AddPortal turns (x, y) into a portal cell and links its face to dest.
+func (m *GameMap) AddPortal(x, y int, face Dir, dest Portal) {
+	if !m.IsValid(x, y) {
+		return
+	}
+	if m.Portals == nil {
+		m.Portals = make(map[PortalFace]Portal)
+	}
+	m.Cells[y][x] = CellPortal
+	m.Portals[PortalFace{X: x, Y: y, Face: face}] = dest
+}
+
+// PortalAt returns the link for entering (x, y) while travelling dir.
+func (m *GameMap) PortalAt(x, y int, dir Dir) (Portal, bool) {
+	if m.GetCell(x, y) != CellPortal {
+		return Portal{}, false
+	}
+	p, ok := m.Portals[PortalFace{X: x, Y: y, Face: dir.Opposite()}]
+	return p, ok
+}
+
+// FollowPortals resolves stepping into (x, y) while travelling dir. Portal
+// cells are followed (up to maxPortalHops) and the final cell and direction
+// are returned; ok is false when the step ends on something solid.
+func (m *GameMap) FollowPortals(x, y int, dir Dir) (int, int, Dir, bool) {
+	for hops := 0; m.GetCell(x, y) == CellPortal; hops++ {
+		link, found := m.PortalAt(x, y, dir)
+		if !found || hops >= maxPortalHops {
+			return x, y, dir, false
+		}
+		x, y, dir = link.ToX, link.ToY, link.Exit
+	}
+	if m.IsWall(x, y) {
+		return x, y, dir, false
+	}
+	return x, y, dir, true
+}
+
+// rotateQuarter rotates (x, y) clockwise (screen coordinates, +y down) by
+// quarter turns.
+func rotateQuarter(x, y float64, turns int) (float64, float64) {
+	for i := 0; i < ((turns%4)+4)%4; i++ {
+		x, y = -y, x
+	}
+	return x, y
+}
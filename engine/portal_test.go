@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+// newCorridorMap returns a 9x5 map with an open row at y=2 from x=1..7.
+func newCorridorMap() *GameMap {
+	m := &GameMap{Width: 9, Height: 5, Cells: make([][]int, 5)}
+	for y := range m.Cells {
+		m.Cells[y] = make([]int, 9)
+		for x := range m.Cells[y] {
+			if y != 2 || x == 0 || x == 8 {
+				m.Cells[y][x] = CellWall
+			}
+		}
+	}
+	return m
+}
+
+func TestDirHelpers(t *testing.T) {
+	if DirEast.Opposite() != DirWest || DirNorth.Opposite() != DirSouth {
+		t.Fatal("unexpected opposites")
+	}
+	if DirNorth.Rotate(1) != DirEast || DirEast.Rotate(-1) != DirNorth {
+		t.Fatal("unexpected rotation")
+	}
+	if DirEast.TurnsTo(DirNorth) != 3 || DirWest.TurnsTo(DirWest) != 0 {
+		t.Fatal("unexpected turn count")
+	}
+	for d := DirEast; d <= DirNorth; d++ {
+		if DirFromAngle(d.Angle()) != d {
+			t.Fatalf("angle round trip failed for %d", d)
+		}
+	}
+	if x, y := rotateQuarter(1, 0, 1); math.Abs(x) > 1e-9 || math.Abs(y-1) > 1e-9 {
+		t.Fatalf("expected east rotated clockwise to point south, got (%f,%f)", x, y)
+	}
+}
+
+func TestFollowPortals(t *testing.T) {
+	m := newCorridorMap()
+	// Walking east into (4,1)... is a wall; make (4,2) a portal back to (1,2) facing north.
+	m.AddPortal(4, 2, DirWest, Portal{ToX: 1, ToY: 2, Exit: DirNorth})
+
+	x, y, dir, ok := m.FollowPortals(4, 2, DirEast)
+	if !ok || x != 1 || y != 2 || dir != DirNorth {
+		t.Fatalf("expected to arrive at (1,2) facing north, got (%d,%d) %d ok=%v", x, y, dir, ok)
+	}
+	if _, _, _, ok := m.FollowPortals(4, 2, DirWest); ok {
+		t.Fatal("expected unlinked face to block")
+	}
+	if !m.IsWall(4, 2) {
+		t.Fatal("expected portal cells to count as solid")
+	}
+
+	// Two portals linked into each other must stop at the hop limit.
+	m.AddPortal(6, 2, DirWest, Portal{ToX: 7, ToY: 2, Exit: DirWest})
+	m.AddPortal(7, 2, DirEast, Portal{ToX: 6, ToY: 2, Exit: DirEast})
+	if _, _, _, ok := m.FollowPortals(6, 2, DirEast); ok {
+		t.Fatal("expected a portal loop to be cut off")
+	}
+}
+
+func TestPlayerStepsThroughPortalAndTurns(t *testing.T) {
+	m := newCorridorMap()
+	m.AddPortal(4, 2, DirWest, Portal{ToX: 6, ToY: 2, Exit: DirSouth})
+
+	p := NewPlayerAtCell(3, 2, 0) // east
+	p.MoveForward(m)
+	if p.X != 6.5 || p.Y != 2.5 {
+		t.Fatalf("expected to step through to (6.5,2.5), got (%f,%f)", p.X, p.Y)
+	}
+	if DirFromAngle(p.Angle) != DirSouth {
+		t.Fatalf("expected to be turned south, got angle %f", p.Angle)
+	}
+
+	// Backing into the portal's linked face works the same way.
+	q := NewPlayerAtCell(3, 2, math.Pi) // west, backing east
+	q.MoveBackward(m)
+	if q.X != 6.5 || q.Y != 2.5 || DirFromAngle(q.Angle) != DirNorth {
+		t.Fatalf("expected to back through facing north, got (%f,%f) angle %f", q.X, q.Y, q.Angle)
+	}
+}
+
+func TestRayContinuesThroughPortal(t *testing.T) {
+	m := newCorridorMap()
+	r := NewRaycaster(80, 24)
+	p := NewPlayerAtCell(2, 2, 0)
+
+	plain := r.castRayHits(p, m, 0).WallDist
+	if math.Abs(plain-5.5) > 1e-9 {
+		t.Fatalf("expected 5.5 to the east wall, got %f", plain)
+	}
+
+	// A portal at (4,2) sends the ray back to the west end of the corridor,
+	// still travelling east: the corridor is longer on the inside.
+	m.AddPortal(4, 2, DirWest, Portal{ToX: 1, ToY: 2, Exit: DirEast})
+	through := r.castRayHits(p, m, 0)
+	got := through.WallDist
+	if got <= plain {
+		t.Fatalf("expected a longer view through the portal, got %f (plain %f)", got, plain)
+	}
+	if math.Abs(through.PortalDist-1.5) > 1e-9 {
+		t.Fatalf("expected the portal 1.5 away, got %f", through.PortalDist)
+	}
+	// The corridor loops until the ray runs out, somewhere inside it.
+	if through.WallDist != r.MaxDist || through.HitX < 1 || through.HitX > 4 || math.Abs(through.HitY-2.5) > 1e-9 {
+		t.Fatalf("expected the ray to run out in the corridor, got %f at (%f,%f)", through.WallDist, through.HitX, through.HitY)
+	}
+
+	// Turned south, the ray crosses (1,2) from its north face into the
+	// corridor wall: 1.5 to the portal plus 1.0 across the cell.
+	m.AddPortal(4, 2, DirWest, Portal{ToX: 1, ToY: 2, Exit: DirSouth})
+	south := r.castRayHits(p, m, 0)
+	if math.Abs(south.WallDist-2.5) > 1e-9 {
+		t.Fatalf("expected the ray to stop at the corridor wall (2.5), got %f", south.WallDist)
+	}
+	if math.Abs(south.HitX-1.5) > 1e-9 || math.Abs(south.HitY-3) > 1e-9 {
+		t.Fatalf("expected the hit on the corridor's south wall at (1.5,3), got (%f,%f)", south.HitX, south.HitY)
+	}
+}
+
+func TestRayPortalLoopIsBounded(t *testing.T) {
+	m := newCorridorMap()
+	m.AddPortal(4, 2, DirWest, Portal{ToX: 3, ToY: 2, Exit: DirEast})
+	r := NewRaycaster(80, 24)
+	r.MaxDist = 1000
+	hit := r.castRayHits(NewPlayerAtCell(2, 2, 0), m, 0)
+	if math.IsInf(hit.WallDist, 0) || hit.WallDist <= 0 {
+		t.Fatalf("expected a finite wall distance, got %f", hit.WallDist)
+	}
+}
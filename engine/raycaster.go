@@ -2,6 +2,8 @@ package engine
 
 import (
 	"math"
+	"runtime"
+	"sync"
 
 	"game/entities"
 	"game/render"
@@ -11,16 +13,45 @@ import (
 const (
 	stairsMinSpriteHeight = 1
 	stairsMaxSpriteHeight = 9
+	noteMaxSpriteHeight   = 3
 	DefaultFOV            = math.Pi / 3
 	DefaultMaxDist        = 16.0
+
+	// parallelMinColumns is the narrowest screen that is worth splitting across workers.
+	parallelMinColumns = 64
 )
 
+// RenderStats counts the work done by the most recent render call.
+type RenderStats struct {
+	RaysCast     int
+	CellsWritten int
+}
+
 // Raycaster handles the 3D raycasting rendering
 type Raycaster struct {
 	ScreenWidth  int
 	ScreenHeight int
 	FOV          float64 // field of view in radians
 	MaxDist      float64 // maximum render distance
+
+	// OriginX and OriginY place the view on the screen, so HUD rows can sit
+	// outside it.
+	OriginX int
+	OriginY int
+
+	// Workers is the number of goroutines used to cast columns; 0 picks
+	// GOMAXPROCS for wide screens and 1 forces the serial path.
+	Workers int
+
+	// Shading and Palette style the walls, floor and ceiling; nil uses the
+	// render package defaults.
+	Shading *render.Shading
+	Palette *render.Palette
+
+	// Stats is reset at the start of every RenderWithEffects call.
+	Stats RenderStats
+
+	fb *Framebuffer
 }
 
 // NewRaycaster creates a raycaster with the given screen dimensions
@@ -39,101 +70,264 @@ func (r *Raycaster) Render(screen tcell.Screen, player *Player, gameMap *GameMap
 }
 
 // RenderWithEffects draws the 3D view to the screen, applying deterministic corruption effects.
+//
+// Columns are cast and shaded in parallel into a framebuffer, then blitted to
+// the screen on the calling goroutine (tcell is not safe for concurrent SetContent).
 func (r *Raycaster) RenderWithEffects(screen tcell.Screen, player *Player, gameMap *GameMap, effects render.EffectsContext, watchers *entities.WatcherManager) {
-	wallStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite)
-	ceilingStyle := tcell.StyleDefault.Foreground(tcell.ColorDarkBlue)
-	floorStyle := tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
-	stairsStyle := tcell.StyleDefault.Foreground(tcell.ColorYellow)
-	watcherStyle := tcell.StyleDefault.Foreground(tcell.ColorDarkMagenta)
+	workers := r.workerCount()
+	if workers <= 1 {
+		r.renderSerial(screen, player, gameMap, effects, watchers)
+		return
+	}
+	r.renderParallel(screen, player, gameMap, effects, watchers, workers)
+}
+
+// columnStyles are the base styles shared by every column of a frame.
+type columnStyles struct {
+	wall    tcell.Style
+	ceiling tcell.Style
+	floor   tcell.Style
+	stairs  tcell.Style
+	note    tcell.Style
+	watcher tcell.Style
+}
+
+func defaultColumnStyles() columnStyles {
+	return paletteColumnStyles(render.DefaultPalette)
+}
+
+func paletteColumnStyles(p render.Palette) columnStyles {
+	return columnStyles{
+		wall:    tcell.StyleDefault.Foreground(p.Wall),
+		ceiling: tcell.StyleDefault.Foreground(p.Ceiling),
+		floor:   tcell.StyleDefault.Foreground(p.Floor),
+		stairs:  tcell.StyleDefault.Foreground(tcell.ColorYellow),
+		note:    tcell.StyleDefault.Foreground(tcell.ColorTeal),
+		watcher: tcell.StyleDefault.Foreground(tcell.ColorDarkMagenta),
+	}
+}
+
+// columnJob is the read-only state every column render needs for one frame.
+type columnJob struct {
+	player  *Player
+	gameMap *GameMap
+	effects render.EffectsContext
+	sprites []entities.WatcherSprite
+	styles  columnStyles
+}
 
-	var watcherSprites []entities.WatcherSprite
+// cellSetter receives the cells produced for a column.
+type cellSetter func(x, y int, ch rune, style tcell.Style)
+
+func (r *Raycaster) newColumnJob(player *Player, gameMap *GameMap, effects render.EffectsContext, watchers *entities.WatcherManager) columnJob {
+	job := columnJob{
+		player:  player,
+		gameMap: gameMap,
+		effects: effects,
+		styles:  defaultColumnStyles(),
+	}
+	if r.Palette != nil {
+		job.styles = paletteColumnStyles(*r.Palette)
+	}
 	if watchers != nil {
-		watcherSprites = watchers.Sprites(r.ScreenWidth, r.ScreenHeight)
+		job.sprites = watchers.Sprites(r.ScreenWidth, r.ScreenHeight)
 	}
+	return job
+}
 
-	// Cast a ray for each column
+// renderSerial draws every column directly to the screen on the calling goroutine.
+func (r *Raycaster) renderSerial(screen tcell.Screen, player *Player, gameMap *GameMap, effects render.EffectsContext, watchers *entities.WatcherManager) {
+	r.Stats = RenderStats{}
+	job := r.newColumnJob(player, gameMap, effects, watchers)
+	set := func(x, y int, ch rune, style tcell.Style) {
+		screen.SetContent(r.OriginX+x, r.OriginY+y, ch, nil, style)
+	}
 	for x := 0; x < r.ScreenWidth; x++ {
-		// Calculate ray angle for this column
-		// Map x from [0, width) to [-FOV/2, FOV/2]
-		rayOffset := (float64(x)/float64(r.ScreenWidth) - 0.5) * r.FOV
-		rayAngle := player.Angle + rayOffset
+		r.Stats.CellsWritten += r.renderColumn(job, x, set)
+		r.Stats.RaysCast++
+	}
+}
+
+// renderParallel splits the columns across workers writing into the framebuffer,
+// then blits the framebuffer to the screen.
+func (r *Raycaster) renderParallel(screen tcell.Screen, player *Player, gameMap *GameMap, effects render.EffectsContext, watchers *entities.WatcherManager, workers int) {
+	r.Stats = RenderStats{}
+	job := r.newColumnJob(player, gameMap, effects, watchers)
+	fb := r.framebuffer()
+
+	if workers > r.ScreenWidth {
+		workers = r.ScreenWidth
+	}
+	cells := make([]int, workers)
+	chunk := (r.ScreenWidth + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		startX := w * chunk
+		endX := startX + chunk
+		if endX > r.ScreenWidth {
+			endX = r.ScreenWidth
+		}
+		if startX >= endX {
+			continue
+		}
+		wg.Add(1)
+		go func(w, startX, endX int) {
+			defer wg.Done()
+			for x := startX; x < endX; x++ {
+				cells[w] += r.renderColumn(job, x, fb.Set)
+			}
+		}(w, startX, endX)
+	}
+	wg.Wait()
+
+	fb.BlitAt(screen, r.OriginX, r.OriginY)
+	r.Stats.RaysCast = r.ScreenWidth
+	for _, n := range cells {
+		r.Stats.CellsWritten += n
+	}
+}
+
+func (r *Raycaster) workerCount() int {
+	if r.Workers > 0 {
+		return r.Workers
+	}
+	if r.ScreenWidth < parallelMinColumns {
+		return 1
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+func (r *Raycaster) framebuffer() *Framebuffer {
+	if r.fb == nil {
+		r.fb = NewFramebuffer(r.ScreenWidth, r.ScreenHeight)
+	} else {
+		r.fb.Resize(r.ScreenWidth, r.ScreenHeight)
+	}
+	return r.fb
+}
+
+// renderColumn casts and shades a single screen column, returning the number of cells written.
+func (r *Raycaster) renderColumn(job columnJob, x int, set cellSetter) int {
+	player := job.player
+	effects := job.effects
+	written := 0
 
-		wallDist, stairsDist := r.castRayWithStairs(player, gameMap, rayAngle)
+	rayOffset := r.columnOffset(x, r.ScreenWidth)
+	rayAngle := player.Angle + rayOffset
 
-		// Fix fish-eye: use perpendicular distance
-		perpDist := wallDist * math.Cos(rayOffset)
+	hit := r.castRayHits(player, job.gameMap, rayAngle)
+	wallDist := hit.WallDist
 
-		// Calculate wall height on screen
-		var wallHeight int
-		if perpDist > 0 {
-			wallHeight = int(float64(r.ScreenHeight) / perpDist)
+	// Fix fish-eye: use perpendicular distance
+	perpDist := wallDist * math.Cos(rayOffset)
+
+	// Calculate wall height on screen
+	var wallHeight int
+	if perpDist > 0 {
+		wallHeight = int(float64(r.ScreenHeight) / perpDist)
+	} else {
+		wallHeight = r.ScreenHeight
+	}
+
+	// Calculate draw start and end
+	drawStart := (r.ScreenHeight - wallHeight) / 2
+	drawEnd := drawStart + wallHeight
+
+	// Clamp to screen bounds
+	if drawStart < 0 {
+		drawStart = 0
+	}
+	if drawEnd > r.ScreenHeight {
+		drawEnd = r.ScreenHeight
+	}
+
+	// Get wall shading character based on distance
+	wallChar := r.Shading.Wall(perpDist, r.MaxDist)
+
+	// Draw column
+	for y := 0; y < r.ScreenHeight; y++ {
+		if y < drawStart {
+			// Ceiling
+			set(x, y, render.CeilingChar, job.styles.ceiling)
+		} else if y < drawEnd {
+			// Wall
+			ch := render.ApplyCharGlitchAt(wallChar, effects, x, y)
+			st := render.ApplyColorBleedAt(job.styles.wall, effects, x, y)
+			set(x, y, ch, st)
 		} else {
-			wallHeight = r.ScreenHeight
+			// Floor
+			rowFromCenter := y - r.ScreenHeight/2
+			floorChar := r.Shading.Floor(rowFromCenter, r.ScreenHeight/2)
+			set(x, y, floorChar, job.styles.floor)
 		}
+	}
+	written += r.ScreenHeight
 
-		// Calculate draw start and end
-		drawStart := (r.ScreenHeight - wallHeight) / 2
-		drawEnd := drawStart + wallHeight
+	// Draw the farther floor sprite first so the nearer one stays on top.
+	if hit.NoteDist > hit.StairsDist {
+		written += r.drawFloorSprite(job, x, hit.NoteDist, wallDist, rayOffset, render.NoteChar, job.styles.note, noteSpriteHeight, set)
+		written += r.drawFloorSprite(job, x, hit.StairsDist, wallDist, rayOffset, render.StairsChar, job.styles.stairs, stairsSpriteHeight, set)
+	} else {
+		written += r.drawFloorSprite(job, x, hit.StairsDist, wallDist, rayOffset, render.StairsChar, job.styles.stairs, stairsSpriteHeight, set)
+		written += r.drawFloorSprite(job, x, hit.NoteDist, wallDist, rayOffset, render.NoteChar, job.styles.note, noteSpriteHeight, set)
+	}
 
-		// Clamp to screen bounds
-		if drawStart < 0 {
-			drawStart = 0
+	for _, sprite := range job.sprites {
+		if sprite.Column != x {
+			continue
 		}
-		if drawEnd > r.ScreenHeight {
-			drawEnd = r.ScreenHeight
+		for y := sprite.StartY; y < sprite.EndY; y++ {
+			st := render.ApplyColorBleedAt(job.styles.watcher, effects, x, y)
+			set(x, y, sprite.Char, st)
 		}
+		written += sprite.EndY - sprite.StartY
+	}
 
-		// Get wall shading character based on distance
-		wallChar := render.GetShade(perpDist, r.MaxDist)
-
-		// Draw column
-		for y := 0; y < r.ScreenHeight; y++ {
-			if y < drawStart {
-				// Ceiling
-				screen.SetContent(x, y, render.CeilingChar, nil, ceilingStyle)
-			} else if y < drawEnd {
-				// Wall
-				ch := render.ApplyCharGlitchAt(wallChar, effects, x, y)
-				st := render.ApplyColorBleedAt(wallStyle, effects, x, y)
-				screen.SetContent(x, y, ch, nil, st)
-			} else {
-				// Floor
-				rowFromCenter := y - r.ScreenHeight/2
-				floorChar := render.GetFloorShade(rowFromCenter, r.ScreenHeight/2)
-				screen.SetContent(x, y, floorChar, nil, floorStyle)
-			}
-		}
+	return written
+}
 
-		if stairsDist < wallDist && stairsDist < r.MaxDist {
-			perpStairsDist := stairsDist * math.Cos(rayOffset)
-			stairsHeight := stairsSpriteHeight(r.ScreenHeight, perpStairsDist)
-			stairsY := r.ScreenHeight / 2
-			startY := stairsY - stairsHeight/2
-			endY := startY + stairsHeight
-			if startY < 0 {
-				startY = 0
-			}
-			if endY > r.ScreenHeight {
-				endY = r.ScreenHeight
-			}
-			for y := startY; y < endY; y++ {
-				st := render.ApplyColorBleedAt(stairsStyle, effects, x, y)
-				screen.SetContent(x, y, render.StairsChar, nil, st)
-			}
-		}
+// drawFloorSprite draws a centered billboard for a floor tile at dist along the
+// ray, if it lies in front of the wall. It returns the number of cells written.
+func (r *Raycaster) drawFloorSprite(job columnJob, x int, dist, wallDist, rayOffset float64, ch rune, style tcell.Style, height func(int, float64) int, set cellSetter) int {
+	if dist >= wallDist || dist >= r.MaxDist {
+		return 0
+	}
+	perpDist := dist * math.Cos(rayOffset)
+	spriteHeight := height(r.ScreenHeight, perpDist)
+	centerY := r.ScreenHeight / 2
+	startY := centerY - spriteHeight/2
+	endY := startY + spriteHeight
+	if startY < 0 {
+		startY = 0
+	}
+	if endY > r.ScreenHeight {
+		endY = r.ScreenHeight
+	}
+	for y := startY; y < endY; y++ {
+		st := render.ApplyColorBleedAt(style, job.effects, x, y)
+		set(x, y, ch, st)
+	}
+	return endY - startY
+}
 
-		if len(watcherSprites) > 0 {
-			for _, sprite := range watcherSprites {
-				if sprite.Column != x {
-					continue
-				}
-				for y := sprite.StartY; y < sprite.EndY; y++ {
-					st := render.ApplyColorBleedAt(watcherStyle, effects, x, y)
-					screen.SetContent(x, y, sprite.Char, nil, st)
-				}
-			}
-		}
+// columnOffset is the angle from the view direction of column x's ray,
+// mapping x from [0, columns) to [-FOV/2, FOV/2).
+func (r *Raycaster) columnOffset(x, columns int) float64 {
+	return (float64(x)/float64(columns) - 0.5) * r.FOV
+}
+
+// CastRays casts the rays a view columns wide would, from player, and returns
+// their hits. It is for debug views; rendering casts its own.
+func (r *Raycaster) CastRays(player *Player, gameMap *GameMap, columns int) []RayHit {
+	if player == nil || gameMap == nil || columns <= 0 {
+		return nil
+	}
+	hits := make([]RayHit, columns)
+	for x := range hits {
+		hits[x] = r.castRayHits(player, gameMap, player.Angle+r.columnOffset(x, columns))
 	}
+	return hits
 }
 
 // castRay uses DDA algorithm to find wall distance
@@ -143,102 +337,217 @@ func (r *Raycaster) castRay(player *Player, gameMap *GameMap, rayAngle float64)
 }
 
 func stairsSpriteHeight(screenHeight int, perpDist float64) int {
+	return floorSpriteHeight(screenHeight, perpDist, stairsMaxSpriteHeight)
+}
+
+func noteSpriteHeight(screenHeight int, perpDist float64) int {
+	return floorSpriteHeight(screenHeight, perpDist, noteMaxSpriteHeight)
+}
+
+func floorSpriteHeight(screenHeight int, perpDist float64, maxHeight int) int {
 	if perpDist <= 0 {
-		return stairsMaxSpriteHeight
+		return maxHeight
 	}
 	h := int(float64(screenHeight) / (perpDist * 2.0))
 	if h < stairsMinSpriteHeight {
 		return stairsMinSpriteHeight
 	}
-	if h > stairsMaxSpriteHeight {
-		return stairsMaxSpriteHeight
+	if h > maxHeight {
+		return maxHeight
 	}
 	return h
 }
 
+// RayHit is the result of casting a single ray.
+type RayHit struct {
+	Angle      float64
+	WallDist   float64
+	StairsDist float64 // +Inf when no stairs lie before the wall
+	NoteDist   float64 // +Inf when no note lies before the wall
+	// PortalDist is where the ray entered its first portal; +Inf when none.
+	PortalDist float64
+	// HitX and HitY are where the ray stopped, on the far side of any
+	// portals: on a wall face, or at MaxDist.
+	HitX, HitY float64
+}
+
 // castRayWithStairs uses DDA algorithm to find the wall distance while also tracking
 // the nearest stairs tile encountered before the wall hit.
 func (r *Raycaster) castRayWithStairs(player *Player, gameMap *GameMap, rayAngle float64) (wallDist, stairsDist float64) {
-	// Ray direction
+	hit := r.castRayHits(player, gameMap, rayAngle)
+	return hit.WallDist, hit.StairsDist
+}
+
+// castRayHits uses DDA algorithm to find the wall distance while also tracking
+// the nearest stairs and note tiles encountered before the wall hit.
+//
+// A ray entering a linked portal face continues from the matching point on
+// the destination cell, rotated with the link, for up to maxPortalHops hops.
+func (r *Raycaster) castRayHits(player *Player, gameMap *GameMap, rayAngle float64) RayHit {
+	return r.traceRay(player, gameMap, rayAngle, nil)
+}
+
+// traceRay is castRayHits, calling visit (if non-nil) for every cell the ray
+// enters, including the wall cell that stops it.
+//
+// Off the map counts as wall, except for rays cast from off the map (a free
+// camera): those cross the void until they reach the map or MaxDist.
+func (r *Raycaster) traceRay(player *Player, gameMap *GameMap, rayAngle float64, visit func(x, y int)) RayHit {
+	// Ray origin and direction for the current segment.
+	originX, originY := player.X, player.Y
 	rayDirX := math.Cos(rayAngle)
 	rayDirY := math.Sin(rayAngle)
 
 	// Current map cell
-	mapX := int(player.X)
-	mapY := int(player.Y)
-
-	// Length of ray from one x or y-side to next x or y-side
-	deltaDistX := math.Abs(1 / rayDirX)
-	deltaDistY := math.Abs(1 / rayDirY)
-
-	// Direction to step in x or y (+1 or -1)
-	var stepX, stepY int
-	// Distance to next x or y gridline
-	var sideDistX, sideDistY float64
-
-	// Calculate step and initial sideDist
-	if rayDirX < 0 {
-		stepX = -1
-		sideDistX = (player.X - float64(mapX)) * deltaDistX
-	} else {
-		stepX = 1
-		sideDistX = (float64(mapX) + 1.0 - player.X) * deltaDistX
+	mapX := int(math.Floor(player.X))
+	mapY := int(math.Floor(player.Y))
+	void := !gameMap.IsValid(mapX, mapY)
+
+	// Distance travelled before the current segment (through portals).
+	base := 0.0
+	hops := 0
+	hit := RayHit{Angle: rayAngle, StairsDist: math.Inf(1), NoteDist: math.Inf(1), PortalDist: math.Inf(1)}
+	// stop records where the ray ends, segDist along the current segment.
+	stop := func(dist, segDist float64) RayHit {
+		hit.WallDist = dist
+		hit.HitX = originX + rayDirX*segDist
+		hit.HitY = originY + rayDirY*segDist
+		return hit
 	}
-
-	if rayDirY < 0 {
-		stepY = -1
-		sideDistY = (player.Y - float64(mapY)) * deltaDistY
-	} else {
-		stepY = 1
-		sideDistY = (float64(mapY) + 1.0 - player.Y) * deltaDistY
+	if visit != nil {
+		visit(mapX, mapY)
 	}
 
-	// Perform DDA
-	var side int // 0 for x-side, 1 for y-side
-	hit := false
-	stairsDist = math.Inf(1)
+segments:
+	for {
+		// Length of ray from one x or y-side to next x or y-side
+		deltaDistX := math.Abs(1 / rayDirX)
+		deltaDistY := math.Abs(1 / rayDirY)
+
+		// Direction to step in x or y (+1 or -1)
+		var stepX, stepY int
+		// Distance to next x or y gridline
+		var sideDistX, sideDistY float64
+
+		// Calculate step and initial sideDist
+		if rayDirX < 0 {
+			stepX = -1
+			sideDistX = (originX - float64(mapX)) * deltaDistX
+		} else {
+			stepX = 1
+			sideDistX = (float64(mapX) + 1.0 - originX) * deltaDistX
+		}
 
-	for !hit {
-		// Jump to next map square
-		if sideDistX < sideDistY {
-			sideDistX += deltaDistX
-			mapX += stepX
-			side = 0
+		if rayDirY < 0 {
+			stepY = -1
+			sideDistY = (originY - float64(mapY)) * deltaDistY
 		} else {
-			sideDistY += deltaDistY
-			mapY += stepY
-			side = 1
+			stepY = 1
+			sideDistY = (float64(mapY) + 1.0 - originY) * deltaDistY
 		}
 
-		if gameMap.GetCell(mapX, mapY) == CellStairs {
-			dist := sideDistX - deltaDistX
+		// Perform DDA
+		var side int // 0 for x-side, 1 for y-side
+
+		for {
+			// Jump to next map square
+			if sideDistX < sideDistY {
+				sideDistX += deltaDistX
+				mapX += stepX
+				side = 0
+			} else {
+				sideDistY += deltaDistY
+				mapY += stepY
+				side = 1
+			}
+
+			// Distance to the edge of the cell just entered.
+			segDist := sideDistX - deltaDistX
 			if side == 1 {
-				dist = sideDistY - deltaDistY
+				segDist = sideDistY - deltaDistY
 			}
-			if dist < stairsDist {
-				stairsDist = dist
+			dist := base + segDist
+			if visit != nil {
+				visit(mapX, mapY)
 			}
-		}
 
-		// Check if ray hit a wall
-		if gameMap.IsWall(mapX, mapY) {
-			hit = true
-		}
+			if void && !gameMap.IsValid(mapX, mapY) {
+				if dist >= r.MaxDist {
+					return stop(r.MaxDist, r.MaxDist-base)
+				}
+				continue
+			}
+
+			switch gameMap.GetCell(mapX, mapY) {
+			case CellStairs:
+				if dist < hit.StairsDist {
+					hit.StairsDist = dist
+				}
+			case CellNote:
+				if dist < hit.NoteDist {
+					hit.NoteDist = dist
+				}
+			case CellPortal:
+				travel := stepDir(side, stepX, stepY)
+				link, ok := gameMap.PortalAt(mapX, mapY, travel)
+				if !ok || hops >= maxPortalHops || dist >= r.MaxDist {
+					return stop(dist, segDist)
+				}
+				hit.PortalDist = math.Min(hit.PortalDist, dist)
+
+				// Carry the hit point across in the portal cell's frame.
+				turns := travel.TurnsTo(link.Exit)
+				relX := originX + rayDirX*segDist - (float64(mapX) + 0.5)
+				relY := originY + rayDirY*segDist - (float64(mapY) + 0.5)
+				relX, relY = rotateQuarter(relX, relY, turns)
+				rayDirX, rayDirY = rotateQuarter(rayDirX, rayDirY, turns)
+				originX = float64(link.ToX) + 0.5 + relX
+				originY = float64(link.ToY) + 0.5 + relY
+				mapX, mapY = link.ToX, link.ToY
+				base = dist
+				hops++
+				if visit != nil {
+					visit(mapX, mapY)
+				}
 
-		// Safety: limit ray distance
-		if sideDistX > r.MaxDist && sideDistY > r.MaxDist {
-			return r.MaxDist, stairsDist
+				// The destination cell itself is entered at dist.
+				switch gameMap.GetCell(mapX, mapY) {
+				case CellStairs:
+					hit.StairsDist = math.Min(hit.StairsDist, dist)
+				case CellNote:
+					hit.NoteDist = math.Min(hit.NoteDist, dist)
+				}
+				if gameMap.BlocksSight(mapX, mapY) {
+					return stop(dist, 0)
+				}
+				continue segments
+			}
+
+			// Check if ray hit a wall (illusory walls included)
+			if gameMap.BlocksSight(mapX, mapY) {
+				return stop(dist, segDist)
+			}
+
+			// Safety: limit ray distance
+			if base+sideDistX > r.MaxDist && base+sideDistY > r.MaxDist {
+				return stop(r.MaxDist, r.MaxDist-base)
+			}
 		}
 	}
+}
 
-	// Calculate distance to wall
+// stepDir is the direction of the DDA step that just entered a cell.
+func stepDir(side, stepX, stepY int) Dir {
 	if side == 0 {
-		wallDist = sideDistX - deltaDistX
-	} else {
-		wallDist = sideDistY - deltaDistY
+		if stepX > 0 {
+			return DirEast
+		}
+		return DirWest
 	}
-
-	return wallDist, stairsDist
+	if stepY > 0 {
+		return DirSouth
+	}
+	return DirNorth
 }
 
 // SetScreenSize updates the screen dimensions
@@ -246,3 +555,9 @@ func (r *Raycaster) SetScreenSize(width, height int) {
 	r.ScreenWidth = width
 	r.ScreenHeight = height
 }
+
+// SetViewport draws the view into the width x height area at (x, y).
+func (r *Raycaster) SetViewport(x, y, width, height int) {
+	r.OriginX, r.OriginY = x, y
+	r.SetScreenSize(width, height)
+}
@@ -3,6 +3,11 @@ package engine
 import (
 	"math"
 	"testing"
+
+	"game/entities"
+	"game/render"
+
+	"github.com/gdamore/tcell/v2"
 )
 
 func TestRaycasterCastRay(t *testing.T) {
@@ -108,3 +113,203 @@ func TestPlayerDirection(t *testing.T) {
 		t.Errorf("DirY for angle π/2 should be 1, got %f", p2.DirY())
 	}
 }
+
+func TestRenderWithEffectsRecordsStats(t *testing.T) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("init screen: %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(40, 12)
+
+	r := NewRaycaster(40, 12)
+	p := NewPlayerAtCell(8, 6, 0)
+	r.RenderWithEffects(screen, p, NewTestMap(), render.EffectsContext{}, nil)
+
+	if r.Stats.RaysCast != 40 {
+		t.Fatalf("expected 40 rays, got %d", r.Stats.RaysCast)
+	}
+	if r.Stats.CellsWritten < 40*12 {
+		t.Fatalf("expected at least %d cells written, got %d", 40*12, r.Stats.CellsWritten)
+	}
+}
+
+func benchmarkRenderWithEffects(b *testing.B, width, height int) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		b.Fatalf("init screen: %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(width, height)
+
+	r := NewRaycaster(width, height)
+	m := NewTestMap()
+	p := NewPlayerAtCell(8, 6, 0)
+	watchers := entities.NewWatcherManager(30, 1, DefaultFOV)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		effects := render.NewEffectsContext(30, 1.0, i)
+		r.RenderWithEffects(screen, p, m, effects, watchers)
+	}
+}
+
+func BenchmarkRenderWithEffects120x40(b *testing.B) {
+	benchmarkRenderWithEffects(b, 120, 40)
+}
+
+func BenchmarkRenderWithEffects400x120(b *testing.B) {
+	benchmarkRenderWithEffects(b, 400, 120)
+}
+
+func BenchmarkCastRayWithStairs(b *testing.B) {
+	r := NewRaycaster(120, 40)
+	m := NewTestMap()
+	p := NewPlayerAtCell(8, 6, 0)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		offset := (float64(i%120)/120 - 0.5) * r.FOV
+		r.castRayWithStairs(p, m, p.Angle+offset)
+	}
+}
+
+func renderToSimulation(t *testing.T, r *Raycaster, p *Player, m *GameMap, effects render.EffectsContext, watchers *entities.WatcherManager) tcell.SimulationScreen {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("init screen: %v", err)
+	}
+	screen.SetSize(r.ScreenWidth, r.ScreenHeight)
+	r.RenderWithEffects(screen, p, m, effects, watchers)
+	return screen
+}
+
+func TestRenderParallelMatchesSerial(t *testing.T) {
+	const width, height = 131, 40
+	m := NewTestMap()
+	watchers := entities.NewWatcherManager(40, 5, DefaultFOV)
+	watchers.Update()
+
+	poses := []*Player{
+		NewPlayerAtCell(8, 6, 0),
+		NewPlayerAtCell(2, 2, math.Pi/2),
+		NewPlayerAtCell(12, 13, -math.Pi/2),
+	}
+	for i, p := range poses {
+		effects := render.NewEffectsContext(40, 1.0, 100+i)
+
+		serial := NewRaycaster(width, height)
+		serial.Workers = 1
+		parallel := NewRaycaster(width, height)
+		parallel.Workers = 7
+
+		a := renderToSimulation(t, serial, p, m, effects, watchers)
+		b := renderToSimulation(t, parallel, p, m, effects, watchers)
+
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				ca, _, sa, _ := a.GetContent(x, y)
+				cb, _, sb, _ := b.GetContent(x, y)
+				if ca != cb || sa != sb {
+					t.Fatalf("pose %d: cell (%d,%d) differs: serial %q %v, parallel %q %v", i, x, y, ca, sa, cb, sb)
+				}
+			}
+		}
+		if serial.Stats != parallel.Stats {
+			t.Fatalf("pose %d: stats differ: serial %+v, parallel %+v", i, serial.Stats, parallel.Stats)
+		}
+		a.Fini()
+		b.Fini()
+	}
+}
+
+func TestRenderStaysInsideViewport(t *testing.T) {
+	for _, workers := range []int{1, 3} {
+		screen := tcell.NewSimulationScreen("UTF-8")
+		if err := screen.Init(); err != nil {
+			t.Fatalf("init screen: %v", err)
+		}
+		screen.SetSize(80, 12)
+		for y := 0; y < 12; y++ {
+			for x := 0; x < 80; x++ {
+				screen.SetContent(x, y, '~', nil, tcell.StyleDefault)
+			}
+		}
+
+		r := NewRaycaster(80, 12)
+		r.Workers = workers
+		r.SetViewport(4, 1, 70, 9)
+		r.RenderWithEffects(screen, NewPlayerAtCell(8, 6, 0), NewTestMap(), render.EffectsContext{}, nil)
+
+		for y := 0; y < 12; y++ {
+			for x := 0; x < 80; x++ {
+				ch, _, _, _ := screen.GetContent(x, y)
+				inside := x >= 4 && x < 74 && y >= 1 && y < 10
+				if inside == (ch == '~') {
+					t.Fatalf("workers=%d: cell (%d,%d) = %q, inside viewport: %v", workers, x, y, ch, inside)
+				}
+			}
+		}
+		screen.Fini()
+	}
+}
+
+func TestRaycasterCastsFromOffTheMap(t *testing.T) {
+	r := NewRaycaster(120, 40)
+	m := NewTestMap()
+
+	// Three cells west of the map, looking east along row 8: the ray crosses
+	// the void and stops at the perimeter wall in column 0.
+	p := NewPlayer(-2.5, 8.5, 0)
+	if dist := r.castRay(p, m, 0); math.Abs(dist-2.5) > 1e-9 {
+		t.Fatalf("expected the perimeter 2.5 away, got %f", dist)
+	}
+
+	// Looking away from the map, nothing is hit before MaxDist.
+	if dist := r.castRay(p, m, math.Pi); dist != r.MaxDist {
+		t.Fatalf("expected MaxDist looking into the void, got %f", dist)
+	}
+
+	// Inside the map, the edge still counts as wall.
+	inside := NewPlayer(8.5, 8.5, 0)
+	if dist := r.castRay(inside, m, 0); dist >= r.MaxDist {
+		t.Fatalf("expected a wall inside the map, got %f", dist)
+	}
+}
+
+func TestCastRaysReportsHitPoints(t *testing.T) {
+	r := NewRaycaster(120, 40)
+	m := NewTestMap()
+	p := NewPlayer(12.5, 2.5, 0)
+
+	hits := r.CastRays(p, m, 4)
+	if len(hits) != 4 {
+		t.Fatalf("expected 4 rays, got %d", len(hits))
+	}
+	for x, hit := range hits {
+		if want := p.Angle + r.columnOffset(x, 4); math.Abs(hit.Angle-want) > 1e-9 {
+			t.Fatalf("ray %d: expected angle %f, got %f", x, want, hit.Angle)
+		}
+		// Without portals the hit lies WallDist along the ray.
+		if d := math.Hypot(hit.HitX-p.X, hit.HitY-p.Y); math.Abs(d-hit.WallDist) > 1e-9 || !math.IsInf(hit.PortalDist, 1) {
+			t.Fatalf("ray %d: hit (%f,%f) is %f away, want %f", x, hit.HitX, hit.HitY, d, hit.WallDist)
+		}
+	}
+
+	// Column 2 of 4 looks straight east at the perimeter wall's face.
+	if mid := hits[2]; math.Abs(mid.HitX-15) > 1e-9 || math.Abs(mid.HitY-2.5) > 1e-9 {
+		t.Fatalf("expected the centre ray on the east wall at (15,2.5), got (%f,%f)", mid.HitX, mid.HitY)
+	}
+
+	// A ray into the void stops at MaxDist.
+	void := r.castRayHits(NewPlayer(-2.5, 8.5, 0), m, math.Pi)
+	if math.Abs(void.HitX-(-2.5-r.MaxDist)) > 1e-9 || math.Abs(void.HitY-8.5) > 1e-9 {
+		t.Fatalf("expected the cutoff MaxDist west, got (%f,%f)", void.HitX, void.HitY)
+	}
+
+	if r.CastRays(p, m, 0) != nil || r.CastRays(nil, m, 4) != nil {
+		t.Fatal("expected no rays without columns or a player")
+	}
+}
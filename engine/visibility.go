@@ -0,0 +1,87 @@
+package engine
+
+import "math"
+
+// VisibilityRays is the number of rays VisibleCells casts across the FOV. It
+// is fixed, not tied to the screen width, so the same position sees the same
+// cells whatever the terminal size (replays depend on this).
+const VisibilityRays = 96
+
+// Visibility is the set of map cells seen from a position.
+type Visibility struct {
+	width, height int
+	cells         []bool
+}
+
+// Contains reports whether cell (x, y) is visible.
+func (v *Visibility) Contains(x, y int) bool {
+	if v == nil || x < 0 || y < 0 || x >= v.width || y >= v.height {
+		return false
+	}
+	return v.cells[y*v.width+x]
+}
+
+// Count returns the number of visible cells.
+func (v *Visibility) Count() int {
+	if v == nil {
+		return 0
+	}
+	n := 0
+	for _, c := range v.cells {
+		if c {
+			n++
+		}
+	}
+	return n
+}
+
+// VisibleCells returns every cell the player can currently see: the player's
+// own cell plus each cell a ray enters (through portals too) up to and
+// including the wall it stops at. Like the ray count, the FOV and distance are
+// the defaults rather than r's display settings.
+func (r *Raycaster) VisibleCells(player *Player, gameMap *GameMap) *Visibility {
+	return visibleCells(player, gameMap, DefaultFOV, DefaultMaxDist, VisibilityRays)
+}
+
+// ViewCells is VisibleCells with r's own FOV and render distance: every cell
+// the 3D view can show from viewer. Rays are as dense as VisibleCells', not
+// one per column, so the result doesn't depend on the screen width.
+func (r *Raycaster) ViewCells(viewer *Player, gameMap *GameMap) *Visibility {
+	rays := int(math.Ceil(VisibilityRays * r.FOV / DefaultFOV))
+	if rays < 1 {
+		rays = 1
+	}
+	return visibleCells(viewer, gameMap, r.FOV, r.MaxDist, rays)
+}
+
+// Add marks every cell of o visible in v too. Both must be for the same map.
+func (v *Visibility) Add(o *Visibility) {
+	if v == nil || o == nil || len(o.cells) != len(v.cells) {
+		return
+	}
+	for i, c := range o.cells {
+		v.cells[i] = v.cells[i] || c
+	}
+}
+
+func visibleCells(player *Player, gameMap *GameMap, fov, maxDist float64, rays int) *Visibility {
+	if player == nil || gameMap == nil {
+		return nil
+	}
+	v := &Visibility{
+		width:  gameMap.Width,
+		height: gameMap.Height,
+		cells:  make([]bool, gameMap.Width*gameMap.Height),
+	}
+	mark := func(x, y int) {
+		if gameMap.IsValid(x, y) {
+			v.cells[y*v.width+x] = true
+		}
+	}
+	tracer := Raycaster{FOV: fov, MaxDist: maxDist}
+	for i := 0; i < rays; i++ {
+		rayOffset := ((float64(i)+0.5)/float64(rays) - 0.5) * fov
+		tracer.traceRay(player, gameMap, player.Angle+rayOffset, mark)
+	}
+	return v
+}
@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVisibleCellsFollowView(t *testing.T) {
+	m := NewTestMap()
+	r := NewRaycaster(120, 40)
+	p := NewPlayerAtCell(8, 6, 0) // east
+
+	v := r.VisibleCells(p, m)
+	if !v.Contains(8, 6) {
+		t.Fatal("expected the player's own cell to be visible")
+	}
+	if !v.Contains(10, 6) || !v.Contains(15, 6) {
+		t.Fatal("expected cells ahead and the wall they end at to be visible")
+	}
+	if v.Contains(6, 6) {
+		t.Fatal("expected cells behind the player to be hidden")
+	}
+	if v.Contains(-1, 0) || v.Contains(100, 100) {
+		t.Fatal("expected out-of-bounds cells to be hidden")
+	}
+
+	p.Angle = math.Pi // turn around
+	if back := r.VisibleCells(p, m); !back.Contains(6, 6) || back.Contains(10, 6) {
+		t.Fatal("expected visibility to follow the view direction")
+	}
+}
+
+func TestVisibleCellsIndependentOfScreenWidth(t *testing.T) {
+	m := NewTestMap()
+	p := NewPlayerAtCell(4, 10, -math.Pi/4)
+	narrow := NewRaycaster(20, 10).VisibleCells(p, m)
+	wide := NewRaycaster(400, 120).VisibleCells(p, m)
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			if narrow.Contains(x, y) != wide.Contains(x, y) {
+				t.Fatalf("cell (%d,%d) visibility depends on screen width", x, y)
+			}
+		}
+	}
+	if narrow.Count() == 0 {
+		t.Fatal("expected some visible cells")
+	}
+}
+
+func TestVisibleCellsIndependentOfViewSettings(t *testing.T) {
+	m := NewTestMap()
+	p := NewPlayerAtCell(4, 10, -math.Pi/4)
+	want := NewRaycaster(80, 24).VisibleCells(p, m)
+
+	r := NewRaycaster(80, 24)
+	r.FOV, r.MaxDist = math.Pi/2, 6
+	got := r.VisibleCells(p, m)
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			if got.Contains(x, y) != want.Contains(x, y) {
+				t.Fatalf("cell (%d,%d) visibility depends on the FOV setting", x, y)
+			}
+		}
+	}
+}
+
+func TestVisibleCellsSeeThroughPortals(t *testing.T) {
+	m := newCorridorMap()
+	m.AddPortal(4, 2, DirWest, Portal{ToX: 6, ToY: 2, Exit: DirEast})
+	v := NewRaycaster(80, 24).VisibleCells(NewPlayerAtCell(2, 2, 0), m)
+	if !v.Contains(7, 2) {
+		t.Fatal("expected cells past the portal exit to be visible")
+	}
+	if v.Contains(5, 2) {
+		t.Fatal("expected the cell hidden behind the portal to stay unseen")
+	}
+}
+
+func TestViewCellsFollowViewSettings(t *testing.T) {
+	m := NewTestMap()
+	p := NewPlayerAtCell(12, 2, 0) // east
+	r := NewRaycaster(80, 24)
+
+	// (13,5) is outside the default 60° FOV but inside a 170° one.
+	if r.ViewCells(p, m).Contains(13, 5) {
+		t.Fatal("expected (13,5) outside the default view")
+	}
+	r.FOV = 170 * math.Pi / 180
+	wide := r.ViewCells(p, m)
+	if !wide.Contains(13, 5) {
+		t.Fatal("expected (13,5) inside a 170° view")
+	}
+
+	v := r.VisibleCells(p, m)
+	before := v.Count()
+	v.Add(wide)
+	if v.Count() <= before || !v.Contains(13, 5) || !v.Contains(15, 2) {
+		t.Fatalf("expected Add to merge the wide view, got %d cells (was %d)", v.Count(), before)
+	}
+}
@@ -29,12 +29,8 @@ const (
 
 	watcherVisibleChance = 0.70
 	watcherGlitchChance  = 0.30
-
-	watcherChar = 'W'
 )
 
-var watcherGlitchChars = []rune{'#', '%', '&', '@', 'X'}
-
 const (
 	defaultFOV = math.Pi / 3
 )
@@ -48,6 +44,12 @@ type Watcher struct {
 	Seed     uint64
 }
 
+// Offset is the Watcher's bearing from the view direction, negative to the
+// left. It sits Distance away along that bearing from whoever is looking.
+func (w Watcher) Offset() float64 {
+	return float64(w.Side) * w.Angle
+}
+
 // WatcherSprite describes how to draw a Watcher for the current frame.
 type WatcherSprite struct {
 	Column int
@@ -3,6 +3,9 @@ package entities
 import (
 	"math"
 	"math/rand"
+
+	"game/events"
+	"game/theme"
 )
 
 const (
@@ -19,10 +22,27 @@ type WatcherManager struct {
 	Depth    int
 	FOV      float64
 	Ticks    int
+
+	// Theme supplies the watcher glyphs. Nil uses the default pack at Depth.
+	Theme *theme.Tables
+	// Events, when set, receives WatcherSeen the first time each Watcher
+	// becomes visible.
+	Events *events.Bus
+	// Hidden is set while Watchers aren't drawn; a hidden Watcher is never
+	// reported as seen.
+	Hidden bool
+
+	seen []bool
 }
 
 // NewWatcherManager creates Watchers for the given depth.
 func NewWatcherManager(depth int, seed int64, fov float64) *WatcherManager {
+	return NewWatcherManagerScaled(depth, seed, fov, 1)
+}
+
+// NewWatcherManagerScaled is NewWatcherManager with the per-depth Watcher
+// count multiplied by density (rounded; 0 disables Watchers).
+func NewWatcherManagerScaled(depth int, seed int64, fov float64, density float64) *WatcherManager {
 	wm := &WatcherManager{Depth: depth, FOV: fov}
 	if depth < WatcherStartDepth {
 		return wm
@@ -33,7 +53,7 @@ func NewWatcherManager(depth int, seed int64, fov float64) *WatcherManager {
 	}
 
 	rng := rand.New(rand.NewSource(seed + int64(depth)*watcherSeedDepthMultiplier))
-	count := watcherCountForDepth(depth, rng)
+	count := int(math.Round(float64(watcherCountForDepth(depth, rng)) * density))
 	if count <= 0 {
 		return wm
 	}
@@ -47,6 +67,23 @@ func NewWatcherManager(depth int, seed int64, fov float64) *WatcherManager {
 	return wm
 }
 
+// Spawn adds one more Watcher, placed from the manager's tick and count so
+// replays spawn the same one.
+func (wm *WatcherManager) Spawn() Watcher {
+	fov := wm.FOV
+	if fov <= 0 {
+		fov = defaultFOV
+	}
+	seed := mix64(uint64(wm.Ticks)<<16 ^ uint64(len(wm.Watchers)))
+	minEdge, maxEdge := edgeOffsetRange(fov)
+	w := newWatcher(rand.New(rand.NewSource(int64(seed))), minEdge, maxEdge)
+	wm.Watchers = append(wm.Watchers, w)
+	if wm.seen != nil {
+		wm.seen = append(wm.seen, false)
+	}
+	return w
+}
+
 // Update advances Watcher drift and animation ticks.
 func (wm *WatcherManager) Update() {
 	if wm == nil {
@@ -56,6 +93,15 @@ func (wm *WatcherManager) Update() {
 	if len(wm.Watchers) == 0 {
 		return
 	}
+	if len(wm.seen) != len(wm.Watchers) {
+		wm.seen = make([]bool, len(wm.Watchers))
+	}
+	for i, w := range wm.Watchers {
+		if !wm.Hidden && wm.isVisible(w, i) && !wm.seen[i] {
+			wm.seen[i] = true
+			wm.Events.Publish(events.WatcherSeen{Depth: wm.Depth, Index: i})
+		}
+	}
 	fov := wm.FOV
 	if fov <= 0 {
 		fov = defaultFOV
@@ -89,6 +135,29 @@ func (wm *WatcherManager) VisibleCount() int {
 	return count
 }
 
+// Visible reports whether Watcher i is visible this frame.
+func (wm *WatcherManager) Visible(i int) bool {
+	if wm == nil || i < 0 || i >= len(wm.Watchers) {
+		return false
+	}
+	return wm.isVisible(wm.Watchers[i], i)
+}
+
+// SeenCount returns how many distinct Watchers have been visible on any
+// tick since the manager was created.
+func (wm *WatcherManager) SeenCount() int {
+	if wm == nil {
+		return 0
+	}
+	count := 0
+	for _, s := range wm.seen {
+		if s {
+			count++
+		}
+	}
+	return count
+}
+
 // CorruptionDelta returns the corruption increment for visible Watchers this frame.
 func (wm *WatcherManager) CorruptionDelta() float64 {
 	return float64(wm.VisibleCount()) * WatcherCorruptionRate
@@ -179,8 +248,7 @@ func watcherColumn(w Watcher, fov float64, screenW int) int {
 	if fov <= 0 {
 		fov = defaultFOV
 	}
-	offset := float64(w.Side) * w.Angle
-	norm := (offset / fov) + 0.5
+	norm := (w.Offset() / fov) + 0.5
 	norm = clampFloat(norm, 0, 1)
 	col := int(math.Round(norm * float64(screenW-1)))
 	if col < 0 {
@@ -196,12 +264,24 @@ func (wm *WatcherManager) isVisible(w Watcher, index int) bool {
 	return chance01(watcherNoise(w, wm.Ticks, index, noiseSaltVisibility), watcherVisibleChance)
 }
 
+func (wm *WatcherManager) tables() *theme.Tables {
+	if wm.Theme != nil {
+		return wm.Theme
+	}
+	return theme.DefaultTables(wm.Depth)
+}
+
 func (wm *WatcherManager) glyphFor(w Watcher, index int) rune {
-	if chance01(watcherNoise(w, wm.Ticks, index, noiseSaltGlitch), watcherGlitchChance) && len(watcherGlitchChars) > 0 {
-		idx := pickIndex(watcherNoise(w, wm.Ticks, index, noiseSaltGlyph), len(watcherGlitchChars))
-		return watcherGlitchChars[idx]
+	t := wm.tables()
+	if chance01(watcherNoise(w, wm.Ticks, index, noiseSaltGlitch), watcherGlitchChance) {
+		if g, ok := theme.Pick(t.WatcherGlitchChars, watcherNoise(w, wm.Ticks, index, noiseSaltGlyph)); ok {
+			return g
+		}
 	}
-	return watcherChar
+	if t.WatcherChar == 0 {
+		return 'W'
+	}
+	return t.WatcherChar
 }
 
 func watcherNoise(w Watcher, ticks int, index int, salt uint64) uint64 {
@@ -223,13 +303,6 @@ func chance01(n uint64, p float64) bool {
 	return f < p
 }
 
-func pickIndex(n uint64, size int) int {
-	if size <= 0 {
-		return 0
-	}
-	return int(n % uint64(size))
-}
-
 // mix64 is a SplitMix64-style mixer.
 func mix64(x uint64) uint64 {
 	x += 0x9E3779B97F4A7C15
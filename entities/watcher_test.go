@@ -3,6 +3,9 @@ package entities
 import (
 	"math"
 	"testing"
+
+	"game/events"
+	"game/theme"
 )
 
 func TestWatcherManagerCountsByDepth(t *testing.T) {
@@ -94,3 +97,138 @@ func TestWatcherSpritesMatchVisibleCount(t *testing.T) {
 		t.Fatalf("expected %d sprites, got %d", visible, len(sprites))
 	}
 }
+
+func TestWatcherSeenCountGrowsMonotonically(t *testing.T) {
+	wm := NewWatcherManager(40, 7, math.Pi/3)
+	if wm.SeenCount() != 0 {
+		t.Fatal("expected no sightings before the first update")
+	}
+	prev := 0
+	for i := 0; i < 50; i++ {
+		wm.Update()
+		seen := wm.SeenCount()
+		if seen < prev || seen > len(wm.Watchers) {
+			t.Fatalf("tick %d: seen count %d (prev %d, total %d)", i, seen, prev, len(wm.Watchers))
+		}
+		if seen < wm.VisibleCount() {
+			t.Fatalf("tick %d: seen %d < visible %d", i, seen, wm.VisibleCount())
+		}
+		prev = seen
+	}
+	if prev == 0 {
+		t.Fatal("expected some watchers to have been seen")
+	}
+}
+
+func TestWatcherSeenPublishedOncePerWatcher(t *testing.T) {
+	wm := NewWatcherManager(40, 7, math.Pi/3)
+	wm.Events = events.NewBus()
+	counts := map[int]int{}
+	events.Subscribe(wm.Events, func(e events.WatcherSeen) {
+		if e.Depth != 40 {
+			t.Fatalf("expected depth 40, got %d", e.Depth)
+		}
+		counts[e.Index]++
+	})
+	for i := 0; i < 200; i++ {
+		wm.Update()
+	}
+	if len(counts) != wm.SeenCount() || len(counts) == 0 {
+		t.Fatalf("expected one event per seen watcher (%d), got %v", wm.SeenCount(), counts)
+	}
+	for i, n := range counts {
+		if n != 1 {
+			t.Fatalf("watcher %d published %d times", i, n)
+		}
+	}
+}
+
+func TestHiddenWatchersAreNotSeen(t *testing.T) {
+	wm := NewWatcherManager(40, 7, math.Pi/3)
+	wm.Events = events.NewBus()
+	published := 0
+	events.Subscribe(wm.Events, func(events.WatcherSeen) { published++ })
+	wm.Hidden = true
+	for i := 0; i < 200; i++ {
+		wm.Update()
+	}
+	if published != 0 || wm.SeenCount() != 0 {
+		t.Fatalf("expected hidden watchers to go unseen, got %d events and %d seen", published, wm.SeenCount())
+	}
+	wm.Hidden = false
+	for i := 0; i < 200; i++ {
+		wm.Update()
+	}
+	if published == 0 || published != wm.SeenCount() {
+		t.Fatalf("expected watchers seen once shown, got %d events and %d seen", published, wm.SeenCount())
+	}
+}
+
+func TestWatcherGlyphsFollowTheme(t *testing.T) {
+	wm := NewWatcherManager(40, 7, math.Pi/3)
+	wm.Theme = &theme.Tables{
+		WatcherChar:        'M',
+		WatcherGlitchChars: []theme.Weighted[rune]{{Value: '*', Weight: 1}},
+	}
+	for i := 0; i < 30; i++ {
+		wm.Update()
+		for _, s := range wm.Sprites(120, 40) {
+			if s.Char != 'M' && s.Char != '*' {
+				t.Fatalf("expected themed glyph, got %q", s.Char)
+			}
+		}
+	}
+}
+
+func TestWatcherManagerScaledDensity(t *testing.T) {
+	base := NewWatcherManager(40, 5, math.Pi/3)
+	if got := NewWatcherManagerScaled(40, 5, math.Pi/3, 1); len(got.Watchers) != len(base.Watchers) {
+		t.Fatalf("expected density 1 to match default count %d, got %d", len(base.Watchers), len(got.Watchers))
+	}
+	if got := NewWatcherManagerScaled(40, 5, math.Pi/3, 2); len(got.Watchers) != 2*len(base.Watchers) {
+		t.Fatalf("expected doubled count %d, got %d", 2*len(base.Watchers), len(got.Watchers))
+	}
+	if got := NewWatcherManagerScaled(40, 5, math.Pi/3, 0); len(got.Watchers) != 0 {
+		t.Fatalf("expected zero density to disable watchers, got %d", len(got.Watchers))
+	}
+}
+
+func TestWatcherSpawnAddsWatcherInEdgeBand(t *testing.T) {
+	wm := NewWatcherManager(1, 5, math.Pi/3)
+	minEdge, maxEdge := edgeOffsetRange(math.Pi / 3)
+	for i := 0; i < 3; i++ {
+		w := wm.Spawn()
+		if w.Angle < minEdge || w.Angle > maxEdge {
+			t.Fatalf("spawned watcher angle %f outside [%f, %f]", w.Angle, minEdge, maxEdge)
+		}
+		wm.Update()
+	}
+	if len(wm.Watchers) != 3 {
+		t.Fatalf("expected 3 watchers, got %d", len(wm.Watchers))
+	}
+	if wm.SeenCount() > 3 {
+		t.Fatalf("seen count %d exceeds watchers", wm.SeenCount())
+	}
+}
+
+func TestWatcherVisibleMatchesVisibleCount(t *testing.T) {
+	wm := NewWatcherManager(20, 99, math.Pi/3)
+	for i := 0; i < 30; i++ {
+		wm.Update()
+		count := 0
+		for j, w := range wm.Watchers {
+			if wm.Visible(j) {
+				count++
+			}
+			if got := w.Offset(); math.Abs(got) != w.Angle || (got < 0) != (w.Side < 0) {
+				t.Fatalf("watcher %d: offset %f for side %d angle %f", j, got, w.Side, w.Angle)
+			}
+		}
+		if count != wm.VisibleCount() {
+			t.Fatalf("tick %d: Visible counts %d, VisibleCount %d", i, count, wm.VisibleCount())
+		}
+	}
+	if wm.Visible(-1) || wm.Visible(len(wm.Watchers)) {
+		t.Fatal("expected out-of-range watchers to be invisible")
+	}
+}
@@ -0,0 +1,102 @@
+// Package events is a small synchronous, typed event bus that lets game
+// systems react to each other without calling one another directly.
+package events
+
+// Event is anything that can be published on a Bus.
+type Event interface {
+	// Name is a short stable identifier, e.g. for logs.
+	Name() string
+}
+
+// FloorEntered is published after a floor becomes current, whether by
+// descending, teleporting or starting a run.
+type FloorEntered struct {
+	Depth int
+	// From is the previous depth, 0 for the first floor of a run.
+	From  int
+	Biome string
+	Seed  int64
+}
+
+// PlayerMoved is published when the player's cell changes.
+type PlayerMoved struct {
+	FromX, FromY int
+	X, Y         int
+}
+
+// CorruptionThresholdCrossed is published when the corruption level rises
+// through one of the cue thresholds.
+type CorruptionThresholdCrossed struct {
+	Threshold float64
+	Level     float64
+	Depth     int
+}
+
+// WatcherSeen is published the first time a Watcher becomes visible.
+type WatcherSeen struct {
+	Depth int
+	Index int
+}
+
+// SnapshotTaken is published after a frame snapshot is written.
+type SnapshotTaken struct {
+	Path string
+}
+
+// CheatUsed is published when a cheat menu action is taken.
+type CheatUsed struct {
+	Action string
+	// Detail qualifies the action, e.g. the teleport depth.
+	Detail string
+}
+
+func (FloorEntered) Name() string               { return "floor_entered" }
+func (PlayerMoved) Name() string                { return "player_moved" }
+func (CorruptionThresholdCrossed) Name() string { return "corruption_threshold" }
+func (WatcherSeen) Name() string                { return "watcher_seen" }
+func (SnapshotTaken) Name() string              { return "snapshot_taken" }
+func (CheatUsed) Name() string                  { return "cheat_used" }
+
+// Bus delivers published events to subscribers synchronously, in
+// subscription order. A nil *Bus drops everything.
+type Bus struct {
+	handlers []func(Event)
+}
+
+// NewBus returns an empty bus.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers fn for every event of type T published on b.
+func Subscribe[T Event](b *Bus, fn func(T)) {
+	if b == nil || fn == nil {
+		return
+	}
+	b.handlers = append(b.handlers, func(e Event) {
+		if ev, ok := e.(T); ok {
+			fn(ev)
+		}
+	})
+}
+
+// SubscribeAll registers fn for every event published on b.
+func (b *Bus) SubscribeAll(fn func(Event)) {
+	if b == nil || fn == nil {
+		return
+	}
+	b.handlers = append(b.handlers, fn)
+}
+
+// Publish calls every matching subscriber before returning. Handlers may
+// publish further events; those are delivered immediately, depth first.
+func (b *Bus) Publish(e Event) {
+	if b == nil || e == nil {
+		return
+	}
+	// Handlers subscribed during delivery only see later events.
+	handlers := b.handlers
+	for _, h := range handlers {
+		h(e)
+	}
+}
@@ -0,0 +1,58 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSubscribeReceivesOnlyItsType(t *testing.T) {
+	b := NewBus()
+	var floors []int
+	var moves int
+	Subscribe(b, func(e FloorEntered) { floors = append(floors, e.Depth) })
+	Subscribe(b, func(PlayerMoved) { moves++ })
+
+	b.Publish(FloorEntered{Depth: 2})
+	b.Publish(PlayerMoved{X: 1, Y: 1})
+	b.Publish(SnapshotTaken{Path: "x"})
+	b.Publish(FloorEntered{Depth: 3})
+
+	if !reflect.DeepEqual(floors, []int{2, 3}) || moves != 1 {
+		t.Fatalf("unexpected deliveries floors=%v moves=%d", floors, moves)
+	}
+}
+
+func TestPublishIsSynchronousAndOrdered(t *testing.T) {
+	b := NewBus()
+	var got []string
+	b.SubscribeAll(func(e Event) { got = append(got, "all:"+e.Name()) })
+	Subscribe(b, func(PlayerMoved) {
+		got = append(got, "moved")
+		b.Publish(FloorEntered{Depth: 2}) // nested publish is delivered at once
+	})
+	Subscribe(b, func(FloorEntered) { got = append(got, "floor") })
+
+	b.Publish(PlayerMoved{})
+	want := []string{"all:player_moved", "moved", "all:floor_entered", "floor"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestNilBusIsSilent(t *testing.T) {
+	var b *Bus
+	Subscribe(b, func(FloorEntered) { t.Fatal("unexpected delivery") })
+	b.SubscribeAll(func(Event) { t.Fatal("unexpected delivery") })
+	b.Publish(FloorEntered{})
+}
+
+func TestEventNamesAreDistinct(t *testing.T) {
+	all := []Event{FloorEntered{}, PlayerMoved{}, CorruptionThresholdCrossed{}, WatcherSeen{}, SnapshotTaken{}, CheatUsed{}}
+	seen := map[string]bool{}
+	for _, e := range all {
+		if e.Name() == "" || seen[e.Name()] {
+			t.Fatalf("bad or duplicate name %q", e.Name())
+		}
+		seen[e.Name()] = true
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"unicode"
+
+	"game/engine"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	freeCamStep     = 0.25         // cells per key press
+	freeCamTurn     = math.Pi / 36 // 5° per key press
+	freeCamFOVStep  = 5            // degrees
+	freeCamDistStep = 2
+)
+
+// setFreeCam detaches the view from the player (on) or reattaches it. The
+// camera starts where the player stands; the player stays put while it moves.
+func (g *Game) setFreeCam(on bool) {
+	switch {
+	case !on:
+		g.freeCam = nil
+	case g.freeCam == nil && g.Player != nil:
+		g.freeCam = engine.NewPlayer(g.Player.X, g.Player.Y, g.Player.Angle)
+	}
+}
+
+// viewer is where the 3D view is drawn from: the free camera when it is on.
+func (g *Game) viewer() *engine.Player {
+	if g.freeCam != nil {
+		return g.freeCam
+	}
+	return g.Player
+}
+
+// handleFreeCamEvent flies the free camera: W/S move, Z/X strafe and A/D turn
+// by small steps at any angle, unblocked by walls or the map edge; [ ] change
+// the FOV and - = the render distance. Other keys fall through.
+func (g *Game) handleFreeCamEvent(ev *tcell.EventKey) bool {
+	if g.freeCam == nil || ev.Key() != tcell.KeyRune {
+		return false
+	}
+	c := g.freeCam
+	switch unicode.ToLower(ev.Rune()) {
+	case 'w':
+		moveFreeCam(c, 0, freeCamStep)
+	case 's':
+		moveFreeCam(c, 0, -freeCamStep)
+	case 'z':
+		moveFreeCam(c, -math.Pi/2, freeCamStep)
+	case 'x':
+		moveFreeCam(c, math.Pi/2, freeCamStep)
+	case 'a':
+		c.Angle = wrapAngle(c.Angle - freeCamTurn)
+	case 'd':
+		c.Angle = wrapAngle(c.Angle + freeCamTurn)
+	case '[':
+		g.adjustFreeCamFOV(-freeCamFOVStep)
+	case ']':
+		g.adjustFreeCamFOV(freeCamFOVStep)
+	case '-', '_':
+		g.adjustFreeCamDist(-freeCamDistStep)
+	case '=', '+':
+		g.adjustFreeCamDist(freeCamDistStep)
+	default:
+		return false
+	}
+	return true
+}
+
+// moveFreeCam moves c dist cells along its facing turned by offset.
+func moveFreeCam(c *engine.Player, offset, dist float64) {
+	c.X += math.Cos(c.Angle+offset) * dist
+	c.Y += math.Sin(c.Angle+offset) * dist
+}
+
+func (g *Game) adjustFreeCamFOV(deltaDeg float64) {
+	if g.Raycaster == nil {
+		return
+	}
+	deg := math.Round(g.Raycaster.FOV*180/math.Pi) + deltaDeg
+	g.Raycaster.FOV = math.Max(cheatMinFOVDeg, math.Min(cheatMaxFOVDeg, deg)) * math.Pi / 180
+}
+
+func (g *Game) adjustFreeCamDist(delta float64) {
+	if g.Raycaster == nil {
+		return
+	}
+	g.Raycaster.MaxDist = math.Max(cheatMinDist, math.Min(cheatMaxDist, g.Raycaster.MaxDist+delta))
+}
+
+// freeCamPanel is the camera readout above the controls.
+func (g *Game) freeCamPanel() hudPanel {
+	c := g.freeCam
+	pos := fmt.Sprintf("%.2f,%.2f %.0f°", c.X, c.Y, math.Mod(c.Angle*180/math.Pi+360, 360))
+	view := ""
+	if g.Raycaster != nil {
+		view = fmt.Sprintf(" FOV %.0f° DIST %.0f", g.Raycaster.FOV*180/math.Pi, g.Raycaster.MaxDist)
+	}
+	return textPanel("freecam", anchorBottom, hudZOverlay, stairsStyle,
+		" FREE CAM "+pos+view+" | WS:Move ZX:Strafe AD:Turn []:FOV -=:Dist ",
+		" FREE CAM "+pos+view+" ",
+		" CAM "+pos+" ")
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"game/engine"
+)
+
+func TestFreeCamFliesWithoutMovingThePlayer(t *testing.T) {
+	g := newHeadlessGame(t, 16, 16, 3)
+	start := *g.Player
+	cheat(t, g, "freecam")
+	g.closeCheatConsole()
+
+	for _, r := range "wwwwdd" {
+		g.processEvent(runeKey(r))
+	}
+	if g.Player.X != start.X || g.Player.Y != start.Y || g.Player.Angle != start.Angle || g.Player.Steps != 0 {
+		t.Fatalf("expected the player to stay put, got %+v", *g.Player)
+	}
+	c := g.freeCam
+	if math.Abs(c.X-start.X) > 1e-9 || math.Abs(c.Y-(start.Y-1)) > 1e-9 {
+		t.Fatalf("expected the camera one cell north, got (%f, %f)", c.X, c.Y)
+	}
+	if want := wrapAngle(start.Angle + 2*freeCamTurn); math.Abs(c.Angle-want) > 1e-9 {
+		t.Fatalf("expected the camera turned 10°, got %f want %f", c.Angle, want)
+	}
+
+	// Walls and the map edge don't stop it.
+	for i := 0; i < 100; i++ {
+		g.processEvent(runeKey('w'))
+	}
+	if g.GameMap.IsValid(int(math.Floor(c.X)), int(math.Floor(c.Y))) {
+		t.Fatalf("expected the camera off the map, got (%f, %f)", c.X, c.Y)
+	}
+	g.render()
+
+	cheat(t, g, "freecam off")
+	if g.freeCam != nil || g.viewer() != g.Player {
+		t.Fatal("expected the view back on the player")
+	}
+}
+
+func TestFreeCamRendersFromTheCamera(t *testing.T) {
+	g := newHeadlessGame(t, 16, 16, 3)
+	g.render()
+	before := strings.Join(captureScreenLines(g.Screen, g.Width, g.Height), "\n")
+
+	g.setFreeCam(true)
+	g.freeCam.X, g.freeCam.Y, g.freeCam.Angle = -3, 8, 0
+	g.render()
+	outside := captureScreenLines(g.Screen, g.Width, g.Height)
+	if strings.Join(outside, "\n") == before {
+		t.Fatal("expected a different frame from the camera")
+	}
+	if !strings.Contains(strings.Join(outside, "\n"), "FREE CAM -3.00,8.00 0°") {
+		t.Fatalf("expected the camera readout, got\n%s", strings.Join(outside, "\n"))
+	}
+}
+
+func TestFreeCamAdjustsView(t *testing.T) {
+	g := newHeadlessGame(t, 16, 16, 3)
+	g.setFreeCam(true)
+
+	g.processEvent(runeKey(']'))
+	g.processEvent(runeKey('='))
+	if got := g.Raycaster.FOV * 180 / math.Pi; math.Abs(got-65) > 1e-9 {
+		t.Fatalf("expected FOV 65°, got %.2f", got)
+	}
+	if g.Raycaster.MaxDist != engine.DefaultMaxDist+freeCamDistStep {
+		t.Fatalf("expected render distance %v, got %v", engine.DefaultMaxDist+freeCamDistStep, g.Raycaster.MaxDist)
+	}
+	for i := 0; i < 40; i++ {
+		g.processEvent(runeKey('['))
+	}
+	if got := g.Raycaster.FOV * 180 / math.Pi; math.Abs(got-cheatMinFOVDeg) > 1e-9 {
+		t.Fatalf("expected FOV clamped to %d°, got %.2f", cheatMinFOVDeg, got)
+	}
+}
+
+func TestNoclipCheatWalksThroughWalls(t *testing.T) {
+	g := newTestGameForCheats(t)
+	m := g.GameMap
+
+	// Face a wall next to the player, if any.
+	x, y := playerCell(g.Player)
+	for _, d := range []engine.Dir{engine.DirNorth, engine.DirEast, engine.DirSouth, engine.DirWest} {
+		dx, dy := d.Delta()
+		if m.GetCell(x+dx, y+dy) == engine.CellWall && m.IsValid(x+dx, y+dy) {
+			g.Player.Angle = d.Angle()
+			break
+		}
+	}
+	dx, dy := engine.DirFromAngle(g.Player.Angle).Delta()
+	if m.GetCell(x+dx, y+dy) != engine.CellWall {
+		t.Fatal("expected a wall next to spawn")
+	}
+
+	g.processEvent(runeKey('w'))
+	if cx, cy := playerCell(g.Player); cx != x || cy != y {
+		t.Fatal("expected the wall to block without noclip")
+	}
+	cheat(t, g, "noclip")
+	g.closeCheatConsole()
+	g.processEvent(runeKey('w'))
+	if cx, cy := playerCell(g.Player); cx != x+dx || cy != y+dy {
+		t.Fatalf("expected noclip into the wall at (%d, %d), got (%d, %d)", x+dx, y+dy, cx, cy)
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"math"
+
+	"game/engine"
+	"game/render"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	godViewHitChar    = '*'
+	godViewCutoffChar = 'x' // a ray that reached MaxDist without hitting anything
+	godViewCamChar    = '@'
+)
+
+var (
+	godViewConeStyle    = tcell.StyleDefault.Foreground(tcell.ColorAqua).Background(tcell.ColorNavy)
+	godViewHitStyle     = tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack)
+	godViewWatcherStyle = tcell.StyleDefault.Foreground(tcell.ColorRed).Background(tcell.ColorBlack)
+)
+
+// godViewScale maps map coordinates onto the viewport. A cell is twice as
+// wide as it is tall so it looks square in the terminal.
+type godViewScale struct {
+	offX, offY int
+	zx, zy     float64 // columns and rows per map cell
+}
+
+// newGodViewScale fits a mapW x mapH map into a w x h viewport, centred.
+func newGodViewScale(w, h, mapW, mapH int) godViewScale {
+	zy := math.Min(float64(h)/float64(mapH), float64(w)/float64(2*mapW))
+	zx := 2 * zy
+	return godViewScale{
+		offX: (w - int(float64(mapW)*zx)) / 2,
+		offY: (h - int(float64(mapH)*zy)) / 2,
+		zx:   zx,
+		zy:   zy,
+	}
+}
+
+// toScreen is the viewport cell covering map point (x, y).
+func (s godViewScale) toScreen(x, y float64) (int, int) {
+	return s.offX + int(math.Floor(x*s.zx)), s.offY + int(math.Floor(y*s.zy))
+}
+
+// toMap is the map point under the centre of viewport cell (sx, sy).
+func (s godViewScale) toMap(sx, sy int) (float64, float64) {
+	return (float64(sx-s.offX) + 0.5) / s.zx, (float64(sy-s.offY) + 0.5) / s.zy
+}
+
+// drawGodView draws the floor from above into vp in place of the 3D view:
+// the map, the viewer's FOV cone as far as each ray reaches, the rays' hit
+// points, the stairs, the Watchers and the player (and free camera).
+func (g *Game) drawGodView(vp hudRect) {
+	if g.GameMap == nil || g.Player == nil || g.Raycaster == nil || vp.W <= 0 || vp.H <= 0 {
+		return
+	}
+	c := hudCanvas{screen: g.Screen, rect: vp, screenW: g.Width, screenH: g.Height}
+	m := g.GameMap
+	s := newGodViewScale(vp.W, vp.H, m.Width, m.Height)
+	viewer := g.viewer()
+	fov := g.Raycaster.FOV
+	hits := g.Raycaster.CastRays(viewer, m, vp.W)
+
+	for sy := 0; sy < vp.H; sy++ {
+		for sx := 0; sx < vp.W; sx++ {
+			x, y := s.toMap(sx, sy)
+			ch := miniMapGlyph(m, int(math.Floor(x)), int(math.Floor(y)))
+			style := dimStyle
+			switch ch {
+			case '#':
+				style = hudStyle
+			case render.StairsChar:
+				style = stairsStyle
+			case render.NoteChar, render.PortalChar:
+				style = noteStyle
+			}
+			if inGodViewCone(viewer, fov, hits, x, y) {
+				style = godViewConeStyle
+			}
+			c.set(sx, sy, ch, style)
+		}
+	}
+
+	for _, hit := range hits {
+		ch := godViewHitChar
+		if hit.WallDist >= g.Raycaster.MaxDist {
+			ch = godViewCutoffChar
+		}
+		// Hits lie on a face; step past it so the mark lands on the wall
+		// rather than the cell in front. After a portal the ray has turned,
+		// so this can pick the wrong side of that face.
+		sx, sy := s.toScreen(hit.HitX+math.Cos(hit.Angle)*1e-6, hit.HitY+math.Sin(hit.Angle)*1e-6)
+		c.set(sx, sy, ch, godViewHitStyle)
+	}
+
+	if g.Floor != nil {
+		stairs := g.Floor.StairsPos
+		sx, sy := s.toScreen(float64(stairs.X)+0.5, float64(stairs.Y)+0.5)
+		c.set(sx, sy, render.StairsChar, stairsStyle)
+
+		if wm := g.Floor.Watchers; g.ShowWatchers && wm != nil {
+			for i, w := range wm.Watchers {
+				a := viewer.Angle + w.Offset()
+				sx, sy := s.toScreen(viewer.X+math.Cos(a)*w.Distance, viewer.Y+math.Sin(a)*w.Distance)
+				style := dimStyle
+				if wm.Visible(i) {
+					style = godViewWatcherStyle
+				}
+				c.set(sx, sy, 'W', style)
+			}
+		}
+	}
+
+	sx, sy := s.toScreen(g.Player.X, g.Player.Y)
+	c.set(sx, sy, playerArrow(g.Player.Angle), playerStyle)
+	if g.freeCam != nil {
+		sx, sy := s.toScreen(g.freeCam.X, g.freeCam.Y)
+		c.set(sx, sy, godViewCamChar, stairsStyle)
+	}
+}
+
+// inGodViewCone reports whether map point (x, y) is inside the viewer's FOV
+// and nearer than where the ray towards it stopped, or entered a portal.
+func inGodViewCone(viewer *engine.Player, fov float64, hits []engine.RayHit, x, y float64) bool {
+	if len(hits) == 0 || fov <= 0 {
+		return false
+	}
+	dx, dy := x-viewer.X, y-viewer.Y
+	offset := wrapAngle(math.Atan2(dy, dx) - viewer.Angle)
+	if math.Abs(offset) > fov/2 {
+		return false
+	}
+	i := int((offset/fov + 0.5) * float64(len(hits)))
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(hits) {
+		i = len(hits) - 1
+	}
+	return math.Hypot(dx, dy) < math.Min(hits[i].WallDist, hits[i].PortalDist)
+}
+
+// godViewPanel is the legend above the controls.
+func (g *Game) godViewPanel() hudPanel {
+	return textPanel("godview", anchorBottom, hudZOverlay, stairsStyle,
+		" GOD VIEW | *:Ray hit x:Cutoff W:Watcher @:Camera ",
+		" GOD VIEW ")
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"game/engine"
+	"game/entities"
+)
+
+func TestGodViewScaleFitsAndCentresTheMap(t *testing.T) {
+	s := newGodViewScale(80, 20, 16, 16)
+	if s.zy != 1.25 || s.zx != 2.5 || s.offX != 20 || s.offY != 0 {
+		t.Fatalf("unexpected scale %+v", s)
+	}
+	if x, y := s.toScreen(0, 0); x != 20 || y != 0 {
+		t.Fatalf("expected the map corner at (20,0), got (%d,%d)", x, y)
+	}
+	if x, y := s.toScreen(15.9, 15.9); x != 59 || y != 19 {
+		t.Fatalf("expected the far corner at (59,19), got (%d,%d)", x, y)
+	}
+	mx, my := s.toMap(s.toScreen(7.5, 3.5))
+	if math.Floor(mx) != 7 || math.Floor(my) != 3 {
+		t.Fatalf("expected a round trip into cell (7,3), got (%f,%f)", mx, my)
+	}
+}
+
+func TestGodViewConeStopsAtWalls(t *testing.T) {
+	r := engine.NewRaycaster(80, 24)
+	m := engine.NewTestMap()
+	p := engine.NewPlayer(12.5, 2.5, 0)
+	hits := r.CastRays(p, m, 8)
+
+	for _, tc := range []struct {
+		x, y float64
+		want bool
+	}{
+		{14.5, 2.5, true},  // ahead
+		{12.5, 5.5, false}, // off to the side
+		{10.5, 2.5, false}, // behind
+		{15.5, 2.5, false}, // past the east wall
+	} {
+		if got := inGodViewCone(p, r.FOV, hits, tc.x, tc.y); got != tc.want {
+			t.Fatalf("(%v,%v): expected %v, got %v", tc.x, tc.y, tc.want, got)
+		}
+	}
+}
+
+func TestGodViewCheatDrawsTheFloorFromAbove(t *testing.T) {
+	g := newHeadlessGame(t, 80, 30, 3)
+	g.render()
+	before := strings.Join(captureScreenLines(g.Screen, g.Width, g.Height), "\n")
+
+	cheat(t, g, "godview")
+	g.closeCheatConsole()
+	if !g.godView {
+		t.Fatal("expected the god view on")
+	}
+	g.Player.Angle = 0
+	g.Floor.Watchers.Watchers = []entities.Watcher{{Angle: 0.2, Distance: 6, Side: -1}}
+	g.render()
+	lines := captureScreenLines(g.Screen, g.Width, g.Height)
+	frame := strings.Join(lines, "\n")
+	if frame == before || !strings.Contains(frame, "GOD VIEW") {
+		t.Fatalf("expected the top-down view and its legend, got\n%s", frame)
+	}
+
+	vp := layoutHUD(g.Width, g.Height, g.hudPanels()).Viewport
+	s := newGodViewScale(vp.W, vp.H, g.GameMap.Width, g.GameMap.Height)
+	at := func(x, y float64) rune {
+		sx, sy := s.toScreen(x, y)
+		return []rune(lines[vp.Y+sy])[vp.X+sx]
+	}
+	if got := at(g.Player.X, g.Player.Y); got != playerArrow(0) {
+		t.Fatalf("expected the player arrow, got %q", got)
+	}
+	a := g.Player.Angle - 0.2
+	if got := at(g.Player.X+math.Cos(a)*6, g.Player.Y+math.Sin(a)*6); got != 'W' {
+		t.Fatalf("expected the Watcher, got %q", got)
+	}
+	if !strings.ContainsRune(frame, godViewHitChar) {
+		t.Fatalf("expected ray hits, got\n%s", frame)
+	}
+
+	cheat(t, g, "godview off")
+	g.closeCheatConsole()
+	g.render()
+	if g.godView || strings.Contains(strings.Join(captureScreenLines(g.Screen, g.Width, g.Height), "\n"), "GOD VIEW") {
+		t.Fatal("expected the 3D view back")
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"game/engine"
+	"game/world"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+var updateGolden = flag.Bool("update", false, "regenerate golden frames in testdata/golden")
+
+const goldenDir = "testdata/golden"
+
+type goldenCase struct {
+	name      string
+	seed      int64
+	floorW    int
+	floorH    int
+	depth     int
+	angle     float64
+	moves     []rune // applied from spawn before rendering
+	bias      float64
+	ticks     int // corruption/effect ticks advanced before rendering
+	noMiniMap bool
+}
+
+var goldenCases = []goldenCase{
+	{name: "depth01-spawn-north", seed: 123, floorW: 20, floorH: 20, depth: 1, angle: -math.Pi / 2},
+	{name: "depth01-walk-east", seed: 123, floorW: 20, floorH: 20, depth: 1, angle: 0, moves: []rune{'w', 'w', 'd', 'w'}},
+	{name: "depth15-watchers", seed: 123, floorW: 20, floorH: 20, depth: 15, angle: -math.Pi / 2, ticks: 3},
+	{name: "depth30-bias-nomap", seed: 77, floorW: 24, floorH: 16, depth: 30, angle: math.Pi, bias: 0.3, ticks: 10, noMiniMap: true},
+	{name: "depth50-full-corruption", seed: 9, floorW: 32, floorH: 32, depth: 50, angle: math.Pi / 2, bias: 0.5, ticks: 90},
+}
+
+const (
+	goldenWidth  = 64
+	goldenHeight = 24
+)
+
+// renderGoldenFrame builds a game for tc and renders one full frame (3D view,
+// effects and HUD) to a simulation screen.
+func renderGoldenFrame(t *testing.T, tc goldenCase) (tcell.SimulationScreen, snapshotMeta) {
+	t.Helper()
+
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("init screen: %v", err)
+	}
+	screen.SetSize(goldenWidth, goldenHeight)
+	t.Cleanup(screen.Fini)
+
+	corruption := world.NewCorruption()
+	corruption.AdjustBias(tc.bias)
+	fm := world.NewFloorManagerWithSize(tc.floorW, tc.floorH)
+	fm.Generator.WithSeed(tc.seed)
+	fm.Corruption = corruption
+	floor := fm.TeleportToDepth(tc.depth)
+
+	g := &Game{
+		Screen:       screen,
+		Running:      true,
+		Width:        goldenWidth,
+		Height:       goldenHeight,
+		CorruptState: corruption,
+		FloorManager: fm,
+		Floor:        floor,
+		GameMap:      floor.Map,
+		Raycaster:    engine.NewRaycaster(goldenWidth, goldenHeight),
+		Player:       engine.NewPlayerAtCell(floor.SpawnPos.X, floor.SpawnPos.Y, tc.angle),
+		ShowMiniMap:  !tc.noMiniMap,
+		ShowWatchers: true,
+	}
+	for _, r := range tc.moves {
+		g.processEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	for i := 0; i < tc.ticks; i++ {
+		g.update()
+	}
+	g.update()
+	g.render()
+
+	meta := snapshotMeta{
+		Depth:      g.Floor.Depth,
+		Corruption: g.CorruptState.GetLevel(),
+		Ticks:      g.CorruptState.Ticks,
+		Width:      goldenWidth,
+		Height:     goldenHeight,
+		Timestamp:  time.Unix(0, 0).UTC(),
+	}
+	return screen, meta
+}
+
+func TestGoldenFrames(t *testing.T) {
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			screen, meta := renderGoldenFrame(t, tc)
+
+			dir := t.TempDir()
+			gotTxt := filepath.Join(dir, tc.name+".txt")
+			if err := writeSnapshotFile(gotTxt, meta, captureScreenLines(screen, goldenWidth, goldenHeight)); err != nil {
+				t.Fatalf("write snapshot: %v", err)
+			}
+			gotANSI := filepath.Join(dir, tc.name+".ans")
+			if err := writeSnapshotANSIFile(gotANSI, meta, captureScreenANSI(screen, goldenWidth, goldenHeight)); err != nil {
+				t.Fatalf("write ansi snapshot: %v", err)
+			}
+
+			compareGolden(t, gotTxt, filepath.Join(goldenDir, tc.name+".txt"))
+			compareGolden(t, gotANSI, filepath.Join(goldenDir, tc.name+".ans"))
+		})
+	}
+}
+
+// compareGolden diffs the freshly written file at gotPath against the golden
+// file, or replaces the golden file when -update is set.
+func compareGolden(t *testing.T, gotPath, goldenPath string) {
+	t.Helper()
+
+	got, err := os.ReadFile(gotPath)
+	if err != nil {
+		t.Fatalf("read %s: %v", gotPath, err)
+	}
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+			t.Fatalf("mkdir golden: %v", err)
+		}
+		if err := os.WriteFile(goldenPath, got, 0o644); err != nil {
+			t.Fatalf("write golden: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("read golden %s (run `go test -run TestGoldenFrames -update` to create it): %v", goldenPath, err)
+	}
+	if bytes.Equal(got, want) {
+		return
+	}
+
+	gotLines := strings.Split(string(got), "\n")
+	wantLines := strings.Split(string(want), "\n")
+	for i := 0; i < len(gotLines) || i < len(wantLines); i++ {
+		var g, w string
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if g != w {
+			t.Fatalf("%s differs from golden at line %d:\n got: %q\nwant: %q\n(run `go test -run TestGoldenFrames -update` if the change is intended)", goldenPath, i+1, g, w)
+		}
+	}
+}
+
+func TestGoldenFramesDeterministic(t *testing.T) {
+	tc := goldenCases[len(goldenCases)-1]
+	a, _ := renderGoldenFrame(t, tc)
+	b, _ := renderGoldenFrame(t, tc)
+
+	la := captureScreenANSI(a, goldenWidth, goldenHeight)
+	lb := captureScreenANSI(b, goldenWidth, goldenHeight)
+	for y := range la {
+		if la[y] != lb[y] {
+			t.Fatalf("line %d differs between identical renders", y)
+		}
+	}
+}
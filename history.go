@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+)
+
+// defaultHistoryPath is where finished runs are appended, one JSON object per
+// line.
+var defaultHistoryPath = filepath.Join(".", "runs", "history.jsonl")
+
+// historyEntry is one finished run in the history file.
+type historyEntry struct {
+	Time           time.Time `json:"time"`
+	Seed           int64     `json:"seed"`
+	Deepest        int       `json:"deepest"`
+	Steps          int       `json:"steps"`
+	DurationMs     int64     `json:"duration_ms"`
+	Floors         int       `json:"floors"`
+	PeakCorruption float64   `json:"peak_corruption"`
+	Exposure       float64   `json:"exposure"`
+	Watchers       int       `json:"watchers"`
+	Notes          int       `json:"notes"`
+	Cheats         int       `json:"cheats"`
+}
+
+func newHistoryEntry(s *RunStats) historyEntry {
+	return historyEntry{
+		Time:           s.End,
+		Seed:           s.Seed,
+		Deepest:        s.Deepest,
+		Steps:          s.Steps,
+		DurationMs:     s.Duration().Milliseconds(),
+		Floors:         len(s.Floors),
+		PeakCorruption: s.PeakCorruption,
+		Exposure:       s.Exposure,
+		Watchers:       s.WatchersSeen,
+		Notes:          s.Notes,
+		Cheats:         s.CheatCount(),
+	}
+}
+
+// appendHistory adds e to the history file at path, creating it if needed.
+func appendHistory(path string, e historyEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readHistory loads every run in the history file. A missing file is an
+// empty history.
+func readHistory(path string) ([]historyEntry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []historyEntry
+	sc := bufio.NewScanner(f)
+	line := 0
+	for sc.Scan() {
+		line++
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var e historyEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, sc.Err()
+}
+
+// bestRun returns the deepest run without cheats, preferring fewer steps on
+// a tie.
+func bestRun(entries []historyEntry) *historyEntry {
+	var best *historyEntry
+	for i := range entries {
+		e := &entries[i]
+		if e.Cheats > 0 {
+			continue
+		}
+		if best == nil || e.Deepest > best.Deepest || (e.Deepest == best.Deepest && e.Steps < best.Steps) {
+			best = e
+		}
+	}
+	return best
+}
+
+// printHistory writes past runs, newest first, marking the best one (-history).
+func printHistory(w io.Writer, entries []historyEntry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No runs recorded yet.")
+		return
+	}
+	best := bestRun(entries)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\tdate\tdeepest\tsteps\ttime\tpeak\twatchers\tnotes\tcheats")
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := &entries[i]
+		mark := ""
+		if e == best {
+			mark = "*"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\t%.0f%%\t%d\t%d\t%d\n",
+			mark,
+			e.Time.Local().Format("2006-01-02 15:04"),
+			e.Deepest,
+			e.Steps,
+			(time.Duration(e.DurationMs) * time.Millisecond).Round(time.Second),
+			e.PeakCorruption*100,
+			e.Watchers,
+			e.Notes,
+			e.Cheats,
+		)
+	}
+	tw.Flush()
+	if best != nil {
+		fmt.Fprintf(w, "* deepest run without cheats: floor %d\n", best.Deepest)
+	}
+}
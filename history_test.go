@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistoryAppendAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs", "history.jsonl")
+	entries, err := readHistory(path)
+	if err != nil || entries != nil {
+		t.Fatalf("expected empty history for a missing file, got %v %v", entries, err)
+	}
+
+	runs := []historyEntry{
+		{Time: time.Unix(1, 0).UTC(), Deepest: 8, Steps: 300},
+		{Time: time.Unix(2, 0).UTC(), Deepest: 20, Steps: 900, Cheats: 3},
+		{Time: time.Unix(3, 0).UTC(), Deepest: 8, Steps: 250},
+	}
+	for _, r := range runs {
+		if err := appendHistory(path, r); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	entries, err = readHistory(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(entries) != len(runs) || entries[1] != runs[1] {
+		t.Fatalf("round trip mismatch: %+v", entries)
+	}
+
+	best := bestRun(entries)
+	if best == nil || best.Steps != 250 {
+		t.Fatalf("expected the shorter clean depth-8 run to be best, got %+v", best)
+	}
+	if bestRun(entries[1:2]) != nil {
+		t.Fatal("expected cheated runs never to be best")
+	}
+}
+
+func TestPrintHistory(t *testing.T) {
+	var out bytes.Buffer
+	printHistory(&out, nil)
+	if !strings.Contains(out.String(), "No runs recorded") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+
+	out.Reset()
+	printHistory(&out, []historyEntry{
+		{Time: time.Unix(1, 0), Deepest: 4, DurationMs: 61000},
+		{Time: time.Unix(2, 0), Deepest: 11, Cheats: 1},
+	})
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header, 2 runs and footer, got:\n%s", out.String())
+	}
+	if !strings.Contains(lines[1], " 11 ") || !strings.HasPrefix(lines[2], "*") || !strings.Contains(lines[2], "1m1s") {
+		t.Fatalf("expected newest first with the clean run marked:\n%s", out.String())
+	}
+	if !strings.Contains(lines[3], "floor 4") {
+		t.Fatalf("unexpected footer %q", lines[3])
+	}
+}
@@ -1,10 +1,16 @@
 package main
 
 import (
+	"fmt"
 	"math"
+	"path/filepath"
 
 	"game/engine"
+	"game/events"
 	"game/render"
+	"game/world"
+
+	"github.com/gdamore/tcell/v2"
 )
 
 const (
@@ -13,8 +19,137 @@ const (
 	miniMapRightMargin    = 1
 	miniMapHorizontalMul  = 2
 	defaultMiniMapRadiusX = defaultMiniMapRadius * miniMapHorizontalMul
+	compactMiniMapRadius  = 3
+	compactMiniMapRadiusX = compactMiniMapRadius * miniMapHorizontalMul
+	hudToastTicks         = 120
+
+	// miniMapMaxWidthFrac keeps the mini-map from covering most of the view.
+	miniMapMaxWidthFrac = 0.5
+)
+
+// HUD panel z-order.
+const (
+	hudZStatus = iota * 10
+	hudZMiniMap
+	hudZOverlay
+	hudZModal
 )
 
+// HUD styles.
+var (
+	hudStyle    = tcell.StyleDefault.Foreground(tcell.ColorGreen).Background(tcell.ColorBlack)
+	playerStyle = tcell.StyleDefault.Foreground(tcell.ColorAqua).Background(tcell.ColorBlack)
+	stairsStyle = tcell.StyleDefault.Foreground(tcell.ColorYellow).Background(tcell.ColorBlack)
+	dimStyle    = tcell.StyleDefault.Foreground(tcell.ColorDarkGray).Background(tcell.ColorBlack)
+	noteStyle   = tcell.StyleDefault.Foreground(tcell.ColorTeal).Background(tcell.ColorBlack)
+)
+
+// hudToast is a short message shown under the status line.
+type hudToast struct {
+	text  string
+	until int // Frame at which the toast disappears
+}
+
+// listenHUD shows toasts for new biomes and saved snapshots.
+func (g *Game) listenHUD(bus *events.Bus) {
+	events.Subscribe(bus, func(e events.FloorEntered) {
+		if e.Biome != "" && (e.From == 0 || world.BiomeForDepth(e.From).Name != e.Biome) {
+			g.showToast(e.Biome)
+		}
+	})
+	events.Subscribe(bus, func(e events.SnapshotTaken) {
+		g.showToast("Snapshot saved: " + filepath.Base(e.Path))
+	})
+}
+
+func (g *Game) showToast(text string) {
+	g.toast = hudToast{text: text, until: g.Frame + hudToastTicks}
+}
+
+// hudPanels lists everything drawn over the 3D view this frame. The status
+// line, controls and compass reserve their rows; the rest overlay the view.
+func (g *Game) hudPanels() []hudPanel {
+	depth := 0
+	biomeName := ""
+	if g.Floor != nil {
+		depth = g.Floor.Depth
+		biomeName = g.Floor.Biome.Name
+	}
+	status := fmt.Sprintf(" Depth: %d | Corruption: %.0f%% ", depth, g.Corruption*100)
+	if biomeName != "" {
+		status = fmt.Sprintf(" Depth: %d | %s | Corruption: %.0f%% ", depth, biomeName, g.Corruption*100)
+	}
+	short := fmt.Sprintf(" D%d %.0f%% ", depth, g.Corruption*100)
+	if g.speedrun != nil {
+		timer := g.speedrun.statusText(g.Frame)
+		status += "| " + timer + " "
+		short += timer + " "
+	}
+	statusPanel := textPanel("status", anchorTop, hudZStatus, hudStyle, status, short)
+	statusPanel.Reserve = true
+
+	var controlsPanel hudPanel
+	if g.replay != nil {
+		controlsPanel = textPanel("controls", anchorBottom, hudZStatus, hudStyle, g.replay.statusLine(g.Frame))
+	} else {
+		controlsPanel = textPanel("controls", anchorBottom, hudZStatus, hudStyle,
+			" W/S: Move | A/D: Turn | J: Journal | C: Cheats | Q: Menu ",
+			" WS AD J C Q ")
+	}
+	controlsPanel.Reserve = true
+
+	panels := []hudPanel{statusPanel, controlsPanel}
+	if compass, ok := g.compassPanel(); ok {
+		panels = append(panels, compass)
+	}
+	if g.Hint != "" {
+		panels = append(panels, textPanel("hint", anchorBottom, hudZOverlay, stairsStyle, " "+g.Hint+" "))
+	}
+	if g.freeCam != nil {
+		panels = append(panels, g.freeCamPanel())
+	}
+	if g.godView {
+		panels = append(panels, g.godViewPanel())
+	}
+	if g.toast.text != "" && g.Frame < g.toast.until {
+		panels = append(panels, textPanel("toast", anchorTopLeft, hudZOverlay, hudStyle, " "+g.toast.text+" "))
+	}
+	if g.ShowPerf {
+		panels = append(panels, g.perfPanel())
+	}
+	if g.ShowMiniMap {
+		if variants := g.miniMapVariants(); len(variants[0]) > 0 {
+			p := linesPanel("minimap", anchorTopRight, hudZMiniMap, miniMapStyle, variants...)
+			p.MaxWidthFrac = miniMapMaxWidthFrac
+			panels = append(panels, p)
+		}
+	}
+	if lines := g.notesLines(); len(lines) > 0 {
+		panels = append(panels, boxPanel("notes", hudZModal, lines))
+	}
+	if g.cheatConsoleOpen {
+		panels = append(panels, g.cheatConsolePanel())
+	}
+	if lines := g.pauseLines(); len(lines) > 0 {
+		panels = append(panels, boxPanel("pause", hudZModal+2, lines))
+	}
+	return panels
+}
+
+func miniMapStyle(r rune) tcell.Style {
+	switch {
+	case r == '#' || isBraille(r):
+		return hudStyle
+	case isPlayerArrow(r):
+		return playerStyle
+	case r == render.StairsChar:
+		return stairsStyle
+	case r == render.NoteChar:
+		return noteStyle
+	}
+	return dimStyle
+}
+
 func absInt(v int) int {
 	if v < 0 {
 		return -v
@@ -41,7 +176,9 @@ func stairsHint(playerCellX, playerCellY, stairsX, stairsY int) string {
 	return ""
 }
 
-func buildMiniMapRect(gameMap *engine.GameMap, playerCellX, playerCellY, stairsX, stairsY, radiusX, radiusY int) []string {
+// buildMiniMapRect renders the cells around the player north-up, one cell per
+// glyph, with an arrow for the player pointing along angle.
+func buildMiniMapRect(gameMap *engine.GameMap, playerCellX, playerCellY int, angle float64, stairsX, stairsY, radiusX, radiusY int) []string {
 	if gameMap == nil || radiusX < 0 || radiusY < 0 {
 		return nil
 	}
@@ -55,24 +192,12 @@ func buildMiniMapRect(gameMap *engine.GameMap, playerCellX, playerCellY, stairsX
 			x := playerCellX + dx
 			y := playerCellY + dy
 
-			ch := ' '
-			if gameMap.IsValid(x, y) {
-				cell := gameMap.GetCell(x, y)
-				switch cell {
-				case engine.CellWall:
-					ch = '#'
-				case engine.CellStairs:
-					ch = render.StairsChar
-				default:
-					ch = '.'
-				}
-			}
-
+			ch := miniMapGlyph(gameMap, x, y)
 			if x == stairsX && y == stairsY {
 				ch = render.StairsChar
 			}
 			if x == playerCellX && y == playerCellY {
-				ch = '@'
+				ch = playerArrow(angle)
 			}
 
 			row = append(row, ch)
@@ -83,8 +208,8 @@ func buildMiniMapRect(gameMap *engine.GameMap, playerCellX, playerCellY, stairsX
 	return lines
 }
 
-func buildMiniMap(gameMap *engine.GameMap, playerCellX, playerCellY, stairsX, stairsY, radius int) []string {
-	return buildMiniMapRect(gameMap, playerCellX, playerCellY, stairsX, stairsY, radius, radius)
+func buildMiniMap(gameMap *engine.GameMap, playerCellX, playerCellY int, angle float64, stairsX, stairsY, radius int) []string {
+	return buildMiniMapRect(gameMap, playerCellX, playerCellY, angle, stairsX, stairsY, radius, radius)
 }
 
 func playerCell(p *engine.Player) (int, int) {
@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"game/engine"
+	"game/events"
 	"game/render"
+	"game/world"
 )
 
 func TestStairsHint(t *testing.T) {
@@ -32,15 +34,16 @@ func TestBuildMiniMapMarksPlayerAndStairs(t *testing.T) {
 		},
 	}
 
-	lines := buildMiniMap(m, 2, 2, 3, 2, 1)
+	lines := buildMiniMap(m, 2, 2, 0, 3, 2, 1)
 	if len(lines) != 3 {
 		t.Fatalf("expected 3 lines, got %d", len(lines))
 	}
-	if lines[1][1] != '@' {
-		t.Fatalf("expected player '@' at center, got %q", lines[1][1])
+	row := []rune(lines[1])
+	if row[1] != '→' {
+		t.Fatalf("expected player arrow '→' at center, got %q", row[1])
 	}
-	if lines[1][2] != byte(render.StairsChar) {
-		t.Fatalf("expected stairs %q at center-right, got %q", render.StairsChar, lines[1][2])
+	if row[2] != render.StairsChar {
+		t.Fatalf("expected stairs %q at center-right, got %q", render.StairsChar, row[2])
 	}
 }
 
@@ -57,7 +60,7 @@ func TestBuildMiniMapRectRespectsDifferentRadii(t *testing.T) {
 		},
 	}
 
-	lines := buildMiniMapRect(m, 2, 2, 3, 2, 2, 1) // 5 wide, 3 tall
+	lines := buildMiniMapRect(m, 2, 2, 0, 3, 2, 2, 1) // 5 wide, 3 tall
 	if len(lines) != 3 {
 		t.Fatalf("expected 3 lines, got %d", len(lines))
 	}
@@ -66,6 +69,18 @@ func TestBuildMiniMapRectRespectsDifferentRadii(t *testing.T) {
 	}
 }
 
+func TestBuildMiniMapLiesAboutIllusions(t *testing.T) {
+	m := &engine.GameMap{
+		Width:  3,
+		Height: 1,
+		Cells:  [][]int{{engine.CellFakeWall, engine.CellEmpty, engine.CellFakeOpen}},
+	}
+	lines := buildMiniMapRect(m, 1, 0, 0, -1, -1, 1, 0)
+	if lines[0] != "#→." {
+		t.Fatalf("expected illusions drawn as they appear, got %q", lines[0])
+	}
+}
+
 func TestMiniMapStartXLeavesRightMargin(t *testing.T) {
 	if got := miniMapStartX(80, 10); got != 69 {
 		t.Fatalf("expected startX 69, got %d", got)
@@ -74,3 +89,32 @@ func TestMiniMapStartXLeavesRightMargin(t *testing.T) {
 		t.Fatalf("expected startX clamped to 0, got %d", got)
 	}
 }
+
+func TestHUDToastsOnBiomeChangeAndSnapshot(t *testing.T) {
+	g := newTestGameForCheats(t)
+
+	g.teleportToDepth(5)
+	if g.toast.text != "" {
+		t.Fatalf("expected no toast inside the same biome, got %q", g.toast.text)
+	}
+
+	g.teleportToDepth(10)
+	if want := world.BiomeForDepth(10).Name; g.toast.text != want {
+		t.Fatalf("expected toast %q, got %q", want, g.toast.text)
+	}
+	g.teleportToDepth(11)
+	if want := world.BiomeForDepth(11).Name; g.toast.text != want {
+		t.Fatalf("expected toast %q, got %q", want, g.toast.text)
+	}
+
+	g.Frame = g.toast.until
+	g.teleportToDepth(12)
+	if g.Frame < g.toast.until {
+		t.Fatal("expected no new toast for a floor in the same biome")
+	}
+
+	g.Events.Publish(events.SnapshotTaken{Path: "snapshots/frame.txt"})
+	if g.toast.text != "Snapshot saved: frame.txt" || g.Frame >= g.toast.until {
+		t.Fatalf("unexpected snapshot toast %+v", g.toast)
+	}
+}
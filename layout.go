@@ -0,0 +1,278 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// hudMinViewportHeight is the fewest rows the 3D view keeps; reserving panels
+// that would squeeze it further are hidden instead.
+const hudMinViewportHeight = 5
+
+// hudAnchor is where a panel sits on screen.
+type hudAnchor int
+
+const (
+	// anchorTop and anchorBottom stack full-width rows from the screen edges.
+	anchorTop hudAnchor = iota
+	anchorBottom
+	// anchorTopLeft and anchorTopRight stack panels in the corners between
+	// the top and bottom rows.
+	anchorTopLeft
+	anchorTopRight
+	// anchorCenter centres a panel on the whole screen.
+	anchorCenter
+)
+
+// hudRect is a screen area in cells.
+type hudRect struct {
+	X, Y, W, H int
+}
+
+func (r hudRect) contains(x, y int) bool {
+	return x >= r.X && x < r.X+r.W && y >= r.Y && y < r.Y+r.H
+}
+
+// hudSize is the width and height of one of a panel's variants.
+type hudSize struct {
+	W, H int
+}
+
+// hudPanel is one HUD element. Sizes lists its variants from preferred to
+// most compact; the layout uses the first that fits and hides the panel when
+// none does, unless Clip is set.
+type hudPanel struct {
+	Name   string
+	Anchor hudAnchor
+	// Z orders drawing: higher panels are drawn over lower ones.
+	Z int
+	// Reserve keeps a top or bottom panel's rows out of the 3D viewport.
+	Reserve bool
+	// Stretch widens a top or bottom panel to the screen width.
+	Stretch bool
+	// MaxWidthFrac caps a corner panel's width as a fraction of the screen
+	// so the view stays visible; 0 means no cap.
+	MaxWidthFrac float64
+	// Clip draws the most compact variant cut to the screen rather than
+	// hiding the panel when nothing fits.
+	Clip  bool
+	Sizes []hudSize
+	// Draw renders variant into c, whose origin is the panel's top-left.
+	Draw func(c hudCanvas, variant int)
+}
+
+// placedPanel is a panel with its chosen variant and screen area.
+type placedPanel struct {
+	Panel   hudPanel
+	Variant int
+	Rect    hudRect
+}
+
+// hudLayout is the result of laying out the HUD on a screen.
+type hudLayout struct {
+	// Viewport is the area the raycaster draws into.
+	Viewport hudRect
+	// Placed holds visible panels in drawing order.
+	Placed []placedPanel
+}
+
+// find returns the placement of the named panel.
+func (l hudLayout) find(name string) (placedPanel, bool) {
+	for _, p := range l.Placed {
+		if p.Panel.Name == name {
+			return p, true
+		}
+	}
+	return placedPanel{}, false
+}
+
+// fit returns the first variant no larger than w x h.
+func (p hudPanel) fit(w, h int) (int, bool) {
+	for i, s := range p.Sizes {
+		if s.W <= w && s.H <= h {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// layoutHUD places panels on a width x height screen. Top and bottom panels
+// stack in the order given, then corner panels fill the space between them,
+// then centred panels are placed over everything.
+func layoutHUD(width, height int, panels []hudPanel) hudLayout {
+	var l hudLayout
+	if width <= 0 || height <= 0 {
+		return l
+	}
+	top, bottom := 0, height
+	reservedTop, reservedBottom := 0, 0
+
+	for _, p := range panels {
+		if p.Anchor != anchorTop && p.Anchor != anchorBottom {
+			continue
+		}
+		v, ok := p.fit(width, bottom-top)
+		if !ok {
+			continue
+		}
+		w, h := p.Sizes[v].W, p.Sizes[v].H
+		if p.Stretch {
+			w = width
+		}
+		if p.Reserve && height-reservedTop-reservedBottom-h < hudMinViewportHeight {
+			continue
+		}
+		var r hudRect
+		if p.Anchor == anchorTop {
+			r = hudRect{X: 0, Y: top, W: w, H: h}
+			top += h
+			if p.Reserve {
+				reservedTop = top
+			}
+		} else {
+			bottom -= h
+			r = hudRect{X: 0, Y: bottom, W: w, H: h}
+			if p.Reserve {
+				reservedBottom = height - bottom
+			}
+		}
+		l.Placed = append(l.Placed, placedPanel{Panel: p, Variant: v, Rect: r})
+	}
+	l.Viewport = hudRect{X: 0, Y: reservedTop, W: width, H: height - reservedTop - reservedBottom}
+
+	leftY, rightY := top, top
+	for _, p := range panels {
+		if p.Anchor != anchorTopLeft && p.Anchor != anchorTopRight {
+			continue
+		}
+		maxW := width
+		if p.MaxWidthFrac > 0 {
+			maxW = int(float64(width) * p.MaxWidthFrac)
+		}
+		y := leftY
+		if p.Anchor == anchorTopRight {
+			y = rightY
+		}
+		v, ok := p.fit(maxW, bottom-y)
+		if !ok {
+			continue
+		}
+		s := p.Sizes[v]
+		r := hudRect{X: 0, Y: y, W: s.W, H: s.H}
+		if p.Anchor == anchorTopRight {
+			r.X = miniMapStartX(width, s.W)
+			rightY += s.H
+		} else {
+			leftY += s.H
+		}
+		l.Placed = append(l.Placed, placedPanel{Panel: p, Variant: v, Rect: r})
+	}
+
+	for _, p := range panels {
+		if p.Anchor != anchorCenter || len(p.Sizes) == 0 {
+			continue
+		}
+		v, ok := p.fit(width, height)
+		if !ok && !p.Clip {
+			continue
+		}
+		if !ok {
+			v = len(p.Sizes) - 1
+		}
+		s := p.Sizes[v]
+		r := hudRect{X: (width - s.W) / 2, Y: (height - s.H) / 2, W: s.W, H: s.H}
+		if r.X < 0 {
+			r.X, r.W = 0, width
+		}
+		if r.Y < 0 {
+			r.Y, r.H = 0, height
+		}
+		l.Placed = append(l.Placed, placedPanel{Panel: p, Variant: v, Rect: r})
+	}
+
+	sort.SliceStable(l.Placed, func(i, j int) bool {
+		return l.Placed[i].Panel.Z < l.Placed[j].Panel.Z
+	})
+	return l
+}
+
+// drawHUD draws every placed panel, clipped to its area and the screen.
+func (l hudLayout) drawHUD(screen tcell.Screen, width, height int) {
+	for _, p := range l.Placed {
+		if p.Panel.Draw == nil {
+			continue
+		}
+		p.Panel.Draw(hudCanvas{screen: screen, rect: p.Rect, screenW: width, screenH: height}, p.Variant)
+	}
+}
+
+// hudCanvas is a panel's drawing surface. Coordinates are relative to the
+// panel and writes outside it are dropped.
+type hudCanvas struct {
+	screen           tcell.Screen
+	rect             hudRect
+	screenW, screenH int
+}
+
+func (c hudCanvas) set(x, y int, r rune, style tcell.Style) {
+	sx, sy := c.rect.X+x, c.rect.Y+y
+	if !c.rect.contains(sx, sy) || sx >= c.screenW || sy >= c.screenH {
+		return
+	}
+	c.screen.SetContent(sx, sy, r, nil, style)
+}
+
+// sub is the part of c at (x, y) of size w x h.
+func (c hudCanvas) sub(x, y, w, h int) hudCanvas {
+	r := hudRect{X: c.rect.X + x, Y: c.rect.Y + y, W: w, H: h}
+	if end := c.rect.X + c.rect.W; r.X+r.W > end {
+		r.W = end - r.X
+	}
+	if end := c.rect.Y + c.rect.H; r.Y+r.H > end {
+		r.H = end - r.Y
+	}
+	return hudCanvas{screen: c.screen, rect: r, screenW: c.screenW, screenH: c.screenH}
+}
+
+func (c hudCanvas) drawString(x, y int, s string, style tcell.Style) {
+	for i, r := range []rune(s) {
+		c.set(x+i, y, r, style)
+	}
+}
+
+// textPanel is a panel showing one line per variant, e.g. a full and a
+// collapsed control list.
+func textPanel(name string, anchor hudAnchor, z int, style tcell.Style, variants ...string) hudPanel {
+	p := hudPanel{Name: name, Anchor: anchor, Z: z}
+	for _, v := range variants {
+		p.Sizes = append(p.Sizes, hudSize{W: len([]rune(v)), H: 1})
+	}
+	p.Draw = func(c hudCanvas, variant int) {
+		c.drawString(0, 0, variants[variant], style)
+	}
+	return p
+}
+
+// linesPanel is a panel showing a block of lines, with style picking each
+// rune's style.
+func linesPanel(name string, anchor hudAnchor, z int, style func(rune) tcell.Style, variants ...[]string) hudPanel {
+	p := hudPanel{Name: name, Anchor: anchor, Z: z}
+	for _, lines := range variants {
+		w := 0
+		for _, line := range lines {
+			if n := len([]rune(line)); n > w {
+				w = n
+			}
+		}
+		p.Sizes = append(p.Sizes, hudSize{W: w, H: len(lines)})
+	}
+	p.Draw = func(c hudCanvas, variant int) {
+		for y, line := range variants[variant] {
+			for x, r := range []rune(line) {
+				c.set(x, y, r, style(r))
+			}
+		}
+	}
+	return p
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"game/engine"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func newLayoutTestScreen(t *testing.T, w, h int) tcell.SimulationScreen {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("init screen: %v", err)
+	}
+	t.Cleanup(screen.Fini)
+	screen.SetSize(w, h)
+	return screen
+}
+
+func screenRow(screen tcell.SimulationScreen, y, w int) string {
+	var b strings.Builder
+	for x := 0; x < w; x++ {
+		ch, _, _, _ := screen.GetContent(x, y)
+		b.WriteRune(ch)
+	}
+	return b.String()
+}
+
+func testLayoutPanels() []hudPanel {
+	status := textPanel("status", anchorTop, hudZStatus, hudStyle, " a status line wider than twenty ", " short ")
+	status.Reserve = true
+	controls := textPanel("controls", anchorBottom, hudZStatus, hudStyle, " W/S: Move | A/D: Turn ", " WS AD ")
+	controls.Reserve = true
+	mini := linesPanel("minimap", anchorTopRight, hudZMiniMap, miniMapStyle,
+		[]string{strings.Repeat("#", 25)}, []string{strings.Repeat("#", 13)})
+	mini.MaxWidthFrac = miniMapMaxWidthFrac
+	return []hudPanel{status, controls, mini}
+}
+
+func TestLayoutHUDReservesRowsFromViewport(t *testing.T) {
+	l := layoutHUD(80, 24, testLayoutPanels())
+	if want := (hudRect{X: 0, Y: 1, W: 80, H: 22}); l.Viewport != want {
+		t.Fatalf("viewport = %+v, want %+v", l.Viewport, want)
+	}
+	if p, ok := l.find("controls"); !ok || p.Rect.Y != 23 || p.Variant != 0 {
+		t.Fatalf("expected full controls on the last row, got %+v (found %v)", p, ok)
+	}
+	if p, ok := l.find("minimap"); !ok || p.Rect.Y != 1 || p.Rect.X != 80-25-miniMapRightMargin {
+		t.Fatalf("expected minimap top-right under the status line, got %+v (found %v)", p, ok)
+	}
+}
+
+func TestLayoutHUDDegradesOnSmallScreens(t *testing.T) {
+	l := layoutHUD(40, 24, testLayoutPanels())
+	if p, _ := l.find("minimap"); p.Variant != 1 {
+		t.Fatalf("expected the compact minimap at width 40, got variant %d", p.Variant)
+	}
+
+	l = layoutHUD(20, 24, testLayoutPanels())
+	if p, _ := l.find("status"); p.Variant != 1 {
+		t.Fatalf("expected the short status at width 20, got variant %d", p.Variant)
+	}
+	if _, ok := l.find("minimap"); ok {
+		t.Fatal("expected the minimap hidden at width 20")
+	}
+	if p, _ := l.find("controls"); p.Variant != 1 {
+		t.Fatalf("expected collapsed controls at width 20, got variant %d", p.Variant)
+	}
+
+	l = layoutHUD(40, hudMinViewportHeight+1, testLayoutPanels())
+	if _, ok := l.find("controls"); ok {
+		t.Fatal("expected controls hidden rather than squeezing the viewport")
+	}
+	if l.Viewport.H != hudMinViewportHeight {
+		t.Fatalf("expected viewport height %d, got %d", hudMinViewportHeight, l.Viewport.H)
+	}
+}
+
+func TestLayoutHUDDrawsByZAndClips(t *testing.T) {
+	screen := newLayoutTestScreen(t, 10, 3)
+	fill := func(name string, z int, r rune) hudPanel {
+		return hudPanel{
+			Name: name, Anchor: anchorCenter, Z: z,
+			Sizes: []hudSize{{W: 4, H: 1}},
+			Draw: func(c hudCanvas, _ int) {
+				// Deliberately overdraw: only the panel's 4x1 area may change.
+				for y := -1; y < 3; y++ {
+					c.drawString(-2, y, strings.Repeat(string(r), 10), tcell.StyleDefault)
+				}
+			},
+		}
+	}
+	// Listed high-z first; drawing order must still follow Z.
+	l := layoutHUD(10, 3, []hudPanel{fill("top", 2, 'B'), fill("bottom", 1, 'A')})
+	l.drawHUD(screen, 10, 3)
+
+	want := []string{"          ", "   BBBB   ", "          "}
+	for y, w := range want {
+		if got := screenRow(screen, y, 10); got != w {
+			t.Fatalf("row %d = %q, want %q", y, got, w)
+		}
+	}
+}
+
+func TestDrawCenteredBoxFallsBackWhenTooBig(t *testing.T) {
+	g := &Game{Width: 12, Height: 4}
+	g.Screen = newLayoutTestScreen(t, 12, 4)
+
+	// Padded needs 14 columns; tight needs 12.
+	if !g.drawCenteredBox([]string{"0123456789"}) {
+		t.Fatal("expected the tight box to fit")
+	}
+	if got := screenRow(g.Screen.(tcell.SimulationScreen), 1, 12); got != "|0123456789|" {
+		t.Fatalf("tight box row = %q", got)
+	}
+
+	// Too wide and too tall: cut to the screen with the border intact.
+	g.Screen.Clear()
+	if !g.drawCenteredBox([]string{"a line that is far too wide", "2", "3", "4"}) {
+		t.Fatal("expected an oversized box to be clipped, not dropped")
+	}
+	s := g.Screen.(tcell.SimulationScreen)
+	if got := screenRow(s, 0, 12); got != "+----------+" {
+		t.Fatalf("top border = %q", got)
+	}
+	if got := screenRow(s, 1, 12); got != "|a line tha|" {
+		t.Fatalf("clipped text row = %q", got)
+	}
+	if got := screenRow(s, 3, 12); got != "+----------+" {
+		t.Fatalf("bottom border = %q", got)
+	}
+}
+
+func TestRenderKeepsHUDRowsOutOfView(t *testing.T) {
+	g := newTestGameForCheats(t)
+	g.Width, g.Height = 30, 12
+	g.Screen = newLayoutTestScreen(t, 30, 12)
+	g.Raycaster = engine.NewRaycaster(30, 12)
+	g.render()
+
+	// Status line and compass above, controls below.
+	if g.Raycaster.OriginY != 2 || g.Raycaster.ScreenHeight != 9 {
+		t.Fatalf("expected the view in rows 2-10, got origin %d height %d", g.Raycaster.OriginY, g.Raycaster.ScreenHeight)
+	}
+	s := g.Screen.(tcell.SimulationScreen)
+	if got := screenRow(s, 11, 30); !strings.HasPrefix(got, " WS AD J C Q ") || strings.TrimSpace(got[13:]) != "" {
+		t.Fatalf("expected collapsed controls alone on the last row, got %q", got)
+	}
+}
@@ -0,0 +1,57 @@
+package lore
+
+import "unicode"
+
+// garbleChars replace letters as corruption eats the text.
+var garbleChars = []rune{'▓', '░', '¤', '§', '∆', '◊', '╳', '#', '?'}
+
+const (
+	// garbleStartLevel is the corruption level below which text stays legible.
+	garbleStartLevel = 0.15
+	// maxGarbleFraction is the share of letters replaced at full corruption.
+	maxGarbleFraction = 0.55
+)
+
+// Garble deterministically replaces a fraction of the letters in text that
+// grows with corruption. The same seed always garbles the same letters, and a
+// letter garbled at one level stays garbled at every higher level.
+func Garble(text string, corruption float64, seed uint64) string {
+	if corruption <= garbleStartLevel {
+		return text
+	}
+	if corruption > 1 {
+		corruption = 1
+	}
+	p := (corruption - garbleStartLevel) / (1 - garbleStartLevel) * maxGarbleFraction
+
+	out := []rune(text)
+	for i, r := range out {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		n := mix64(seed ^ uint64(i)*0x9E3779B185EBCA87)
+		if float64(n>>11)*(1.0/(1<<53)) >= p {
+			continue
+		}
+		out[i] = garbleChars[mix64(n)%uint64(len(garbleChars))]
+	}
+	return string(out)
+}
+
+// SeedFor returns a stable garble seed for a note ID.
+func SeedFor(id string) uint64 {
+	var h uint64 = 0xCBF29CE484222325
+	for i := 0; i < len(id); i++ {
+		h ^= uint64(id[i])
+		h *= 0x100000001B3
+	}
+	return h
+}
+
+// mix64 is a SplitMix64-style mixer.
+func mix64(x uint64) uint64 {
+	x += 0x9E3779B97F4A7C15
+	x = (x ^ (x >> 30)) * 0xBF58476D1CE4E5B9
+	x = (x ^ (x >> 27)) * 0x94D049BB133111EB
+	return x ^ (x >> 31)
+}
@@ -0,0 +1,51 @@
+package lore
+
+import "testing"
+
+const sampleText = "The stairs do not go down. You do."
+
+func TestGarbleLegibleAtLowCorruption(t *testing.T) {
+	if got := Garble(sampleText, 0.1, 1); got != sampleText {
+		t.Fatalf("expected untouched text, got %q", got)
+	}
+}
+
+func TestGarbleDeterministic(t *testing.T) {
+	a := Garble(sampleText, 0.8, 42)
+	b := Garble(sampleText, 0.8, 42)
+	if a != b {
+		t.Fatalf("expected deterministic output, got %q vs %q", a, b)
+	}
+}
+
+func TestGarbleProgressive(t *testing.T) {
+	seed := SeedFor("mirror")
+	prev := []rune(Garble(sampleText, 0.3, seed))
+	prevCount := diffCount(prev)
+	for _, level := range []float64{0.5, 0.75, 1.0} {
+		cur := []rune(Garble(sampleText, level, seed))
+		for i, r := range prev {
+			if r != []rune(sampleText)[i] && cur[i] == []rune(sampleText)[i] {
+				t.Fatalf("letter %d recovered at higher corruption %f", i, level)
+			}
+		}
+		if n := diffCount(cur); n < prevCount {
+			t.Fatalf("expected garbling to grow, got %d < %d at %f", n, prevCount, level)
+		}
+		prev, prevCount = cur, diffCount(cur)
+	}
+	if prevCount == 0 {
+		t.Fatal("expected garbling at full corruption")
+	}
+}
+
+func diffCount(r []rune) int {
+	orig := []rune(sampleText)
+	n := 0
+	for i := range orig {
+		if r[i] != orig[i] {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,145 @@
+package lore
+
+// Note is a readable document left on a floor. Pages are displayed one at a
+// time in the reader overlay.
+type Note struct {
+	ID       string
+	Title    string
+	MinDepth int
+	MaxDepth int // 0 means no upper bound
+	Pages    []string
+}
+
+// InRange reports whether the note may appear at depth.
+func (n Note) InRange(depth int) bool {
+	if depth < n.MinDepth {
+		return false
+	}
+	return n.MaxDepth == 0 || depth <= n.MaxDepth
+}
+
+// corpus is ordered roughly by depth so descending reads like a story.
+var corpus = []Note{
+	{
+		ID:       "surveyor-1",
+		Title:    "Surveyor's Log, Entry 1",
+		MinDepth: 1,
+		MaxDepth: 9,
+		Pages: []string{
+			"Descended the service shaft at 0600. The stairs are older than the building above them. Nobody on the crew will say who cut them.",
+			"Mapped four floors by noon. Each one is laid out differently, yet the draft always leads the same way: down.",
+		},
+	},
+	{
+		ID:       "surveyor-2",
+		Title:    "Surveyor's Log, Entry 2",
+		MinDepth: 3,
+		MaxDepth: 14,
+		Pages: []string{
+			"My maps no longer agree with each other. I walked a corridor twice and counted a different number of paces each time.",
+			"Harlan says the numbers are fine and I am tired. Harlan has not slept since floor six.",
+		},
+	},
+	{
+		ID:       "chalk",
+		Title:    "Chalk on the Wall",
+		MinDepth: 1,
+		MaxDepth: 19,
+		Pages: []string{
+			"FOLLOW THE COLD AIR. DO NOT FOLLOW THE VOICES. THEY ARE NOT THE SAME THING EVEN WHEN THEY SAY THE SAME WORDS.",
+		},
+	},
+	{
+		ID:       "hymn",
+		Title:    "Torn Hymnal Page",
+		MinDepth: 8,
+		MaxDepth: 24,
+		Pages: []string{
+			"...and the congregation went down into the dark that was older than dark, singing, and the singing went on after the singers had stopped...",
+			"In the margin, in pencil: ph'nglui mglw'nafh. Someone has underlined it until the paper tore.",
+		},
+	},
+	{
+		ID:       "harlan",
+		Title:    "Harlan's Letter",
+		MinDepth: 12,
+		MaxDepth: 29,
+		Pages: []string{
+			"If you find this, tell my sister I kept going. Tell her the walls here are soft when nobody is looking at them.",
+			"I turned around once. The way back was not there. I do not think it was ever there. I think it was only polite.",
+		},
+	},
+	{
+		ID:       "watchers",
+		Title:    "On the Ones at the Edges",
+		MinDepth: 15,
+		MaxDepth: 39,
+		Pages: []string{
+			"They stand where the eye stops being reliable. Look straight at them and they are a crack in the plaster, a stain, nothing.",
+			"Do not try to count them. Counting is a kind of looking, and looking is how they get in.",
+		},
+	},
+	{
+		ID:       "geometry",
+		Title:    "Notes on Geometry",
+		MinDepth: 20,
+		MaxDepth: 44,
+		Pages: []string{
+			"A corridor of nine paces contains a corridor of ninety. I have measured both. I have walked both. They are the same corridor.",
+			"Right angles are a courtesy the upper floors extend to visitors. Down here the courtesy has lapsed.",
+		},
+	},
+	{
+		ID:       "surveyor-last",
+		Title:    "Surveyor's Log, Final Entry",
+		MinDepth: 30,
+		Pages: []string{
+			"Stopped mapping. The map was mapping me.",
+			"The draft is warm now. It smells like breath.",
+		},
+	},
+	{
+		ID:       "choir",
+		Title:    "The Choir Below",
+		MinDepth: 35,
+		Pages: []string{
+			"It is not a sound. It is the place where a sound was removed, and the shape of the hole is singing.",
+		},
+	},
+	{
+		ID:       "mirror",
+		Title:    "A Page in Your Handwriting",
+		MinDepth: 45,
+		Pages: []string{
+			"You have read this before. You will read it again. The stairs do not go down. You do.",
+		},
+	},
+}
+
+// All returns every note in the corpus.
+func All() []Note {
+	out := make([]Note, len(corpus))
+	copy(out, corpus)
+	return out
+}
+
+// ForDepth returns the notes that may appear at depth, in corpus order.
+func ForDepth(depth int) []Note {
+	var out []Note
+	for _, n := range corpus {
+		if n.InRange(depth) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// Get looks up a note by ID.
+func Get(id string) (Note, bool) {
+	for _, n := range corpus {
+		if n.ID == id {
+			return n, true
+		}
+	}
+	return Note{}, false
+}
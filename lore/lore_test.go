@@ -0,0 +1,45 @@
+package lore
+
+import "testing"
+
+func TestCorpusNotesAreWellFormed(t *testing.T) {
+	seen := map[string]bool{}
+	for _, n := range All() {
+		if n.ID == "" || n.Title == "" {
+			t.Fatalf("note missing id or title: %+v", n)
+		}
+		if seen[n.ID] {
+			t.Fatalf("duplicate note id %q", n.ID)
+		}
+		seen[n.ID] = true
+		if len(n.Pages) == 0 {
+			t.Fatalf("note %q has no pages", n.ID)
+		}
+		if n.MaxDepth != 0 && n.MaxDepth < n.MinDepth {
+			t.Fatalf("note %q has inverted depth range %d-%d", n.ID, n.MinDepth, n.MaxDepth)
+		}
+	}
+}
+
+func TestForDepthRespectsRanges(t *testing.T) {
+	for _, depth := range []int{1, 10, 25, 50, 200} {
+		notes := ForDepth(depth)
+		if len(notes) == 0 {
+			t.Fatalf("expected notes available at depth %d", depth)
+		}
+		for _, n := range notes {
+			if !n.InRange(depth) {
+				t.Fatalf("note %q returned out of range at depth %d", n.ID, depth)
+			}
+		}
+	}
+}
+
+func TestGetFindsNote(t *testing.T) {
+	if _, ok := Get("chalk"); !ok {
+		t.Fatal("expected chalk note")
+	}
+	if _, ok := Get("missing"); ok {
+		t.Fatal("expected missing note lookup to fail")
+	}
+}
@@ -5,11 +5,18 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"path/filepath"
 	"time"
 
+	"game/audio"
+	"game/console"
 	"game/engine"
 	"game/entities"
+	"game/events"
+	"game/lore"
+	"game/menu"
 	"game/render"
+	"game/theme"
 	"game/world"
 
 	"github.com/gdamore/tcell/v2"
@@ -32,17 +39,77 @@ type Game struct {
 	Floor        *world.Floor
 
 	ShowMiniMap  bool
+	MiniMapMode  miniMapMode
 	ShowWatchers bool
-
-	cheatMenuOpen       bool
-	cheatMode           cheatMode
-	cheatTeleportBuffer []rune
-	cheatMessage        string
+	ShowPerf     bool
+	SnapshotANSI bool
+	ColorMode    colorMode
+
+	// Frame counts completed update ticks; replays key input events to it.
+	Frame int
+
+	// Audio receives gameplay sound events; nil is silent.
+	Audio *audio.Bus
+
+	// Stats accumulates this run's totals for the end screen and history.
+	Stats RunStats
+
+	// Events carries cross-system notifications; see SetEvents.
+	Events *events.Bus
+
+	// lastCell is the player's cell as of the last PlayerMoved.
+	lastCell world.Point
+	// watchersSeenCount counts WatcherSeen events this run.
+	watchersSeenCount int
+	toast             hudToast
+	// stairsSeen is set once the current floor's stairs have been in view.
+	stairsSeen bool
+
+	// pause is the open pause menu; while set, update does nothing.
+	pause *menu.Menu
+	// savedAndQuit is set when the session ended with Save & Quit.
+	savedAndQuit bool
+
+	perf     perfStats
+	recorder *replayRecorder
+	replay   *replayPlayer
+	cast     *castRecorder
+	runLog   *runLog
+
+	// Journal holds every lore note collected this run, in pickup order.
+	Journal      []lore.Note
+	reader       *noteReader
+	journalOpen  bool
+	journalIndex int
+
+	// daily disables cheats so daily results stay comparable.
+	daily bool
+	// speedrun times the descent to a target depth; nil when not racing.
+	speedrun *speedrun
+
+	cheatConsoleOpen bool
+	cheatConsole     *console.Console
+	// freeCam, when set, is the viewpoint the 3D view is drawn from instead
+	// of the player.
+	freeCam *engine.Player
+	// godView replaces the 3D view with the floor seen from above.
+	godView bool
+	// restoring is the save restoreRun is re-running, if any.
+	restoring *Replay
 }
 
 func NewGame(screen tcell.Screen, floorWidth, floorHeight int) *Game {
+	return NewGameWithSeed(screen, floorWidth, floorHeight, time.Now().UnixNano())
+}
+
+// NewGameWithSeed creates a game whose floors are generated from seed, so the
+// same seed and input sequence always reproduce the same run.
+func NewGameWithSeed(screen tcell.Screen, floorWidth, floorHeight int, seed int64) *Game {
 	w, h := screen.Size()
 	floorManager := world.NewFloorManagerWithSize(floorWidth, floorHeight)
+	floorManager.Generator.WithSeed(seed)
+	corruption := world.NewCorruption()
+	floorManager.Corruption = corruption
 	floor := floorManager.GenerateFirstFloor()
 	g := &Game{
 		Screen:       screen,
@@ -50,7 +117,7 @@ func NewGame(screen tcell.Screen, floorWidth, floorHeight int) *Game {
 		Width:        w,
 		Height:       h,
 		Corruption:   0.0,
-		CorruptState: world.NewCorruption(),
+		CorruptState: corruption,
 		events:       make(chan tcell.Event, 10),
 		GameMap:      floor.Map,
 		Raycaster:    engine.NewRaycaster(w, h),
@@ -58,9 +125,14 @@ func NewGame(screen tcell.Screen, floorWidth, floorHeight int) *Game {
 		Floor:        floor,
 		ShowMiniMap:  true,
 		ShowWatchers: true,
+		SnapshotANSI: true,
 	}
 	// Start player at floor spawn (facing north).
 	g.Player = engine.NewPlayerAtCell(floor.SpawnPos.X, floor.SpawnPos.Y, -math.Pi/2)
+	g.lastCell = floor.SpawnPos
+	g.Stats = newRunStats(seed, floor.Depth, g.Frame, time.Now())
+	g.SetAudio(audio.NewBus(audio.Null{}))
+	g.SetEvents(events.NewBus())
 	// Start event polling goroutine
 	go g.pollEvents()
 	return g
@@ -83,6 +155,9 @@ func (g *Game) handleInput() {
 	for {
 		select {
 		case ev := <-g.events:
+			if g.handlePauseEvent(ev) {
+				continue
+			}
 			g.processEvent(ev)
 		default:
 			return
@@ -91,18 +166,27 @@ func (g *Game) handleInput() {
 }
 
 func (g *Game) processEvent(ev tcell.Event) {
+	g.recorder.record(g.Frame, ev)
+
 	switch ev := ev.(type) {
 	case *tcell.EventKey:
+		if ev.Key() == replayKeyView {
+			g.applyViewEvent(ev)
+			return
+		}
+		if g.handleNoteEvent(ev) {
+			return
+		}
 		if g.handleCheatEvent(ev) {
 			return
 		}
-		switch ev.Key() {
-		case tcell.KeyEscape:
-			g.Running = false
-		case tcell.KeyRune:
+		if g.handleFreeCamEvent(ev) {
+			return
+		}
+		// Esc and q pause (handlePauseEvent) before reaching here; quitting
+		// is only ever done from the pause menu.
+		if ev.Key() == tcell.KeyRune {
 			switch ev.Rune() {
-			case 'q', 'Q':
-				g.Running = false
 			case 'w', 'W':
 				g.Player.MoveForward(g.GameMap)
 			case 's', 'S':
@@ -111,6 +195,8 @@ func (g *Game) processEvent(ev tcell.Event) {
 				g.Player.RotateLeft()
 			case 'd', 'D':
 				g.Player.RotateRight()
+			case 'j', 'J':
+				g.openJournal()
 			}
 		}
 	case *tcell.EventResize:
@@ -122,6 +208,11 @@ func (g *Game) processEvent(ev tcell.Event) {
 
 // update processes game state changes
 func (g *Game) update() {
+	if g.pause != nil {
+		return
+	}
+	g.Frame++
+
 	if g.FloorManager == nil || g.Floor == nil || g.GameMap == nil || g.Player == nil {
 		return
 	}
@@ -129,13 +220,21 @@ func (g *Game) update() {
 	cellX, cellY := playerCell(g.Player)
 
 	g.Hint = stairsHint(cellX, cellY, g.Floor.StairsPos.X, g.Floor.StairsPos.Y)
-	if g.GameMap.GetCell(cellX, cellY) == engine.CellStairs {
-		g.Floor = g.FloorManager.DescendToNextFloor()
-		g.GameMap = g.Floor.Map
-		g.Player.SetCell(g.Floor.SpawnPos.X, g.Floor.SpawnPos.Y)
+	if g.GameMap.GetCell(cellX, cellY) == engine.CellNote {
+		g.pickUpNote(cellX, cellY)
+	}
+	// Stairs are handled by the FloorManager's PlayerMoved subscription.
+	if cell := (world.Point{X: cellX, Y: cellY}); cell != g.lastCell {
+		from := g.lastCell
+		g.lastCell = cell
+		g.Events.Publish(events.PlayerMoved{FromX: from.X, FromY: from.Y, X: cell.X, Y: cell.Y})
 	}
 
+	g.noteStairsSeen()
+
 	if g.Floor != nil && g.Floor.Watchers != nil {
+		// Watchers turned off aren't drawn, so they can't be seen either.
+		g.Floor.Watchers.Hidden = !g.ShowWatchers
 		g.Floor.Watchers.Update()
 	}
 
@@ -144,14 +243,75 @@ func (g *Game) update() {
 		depth = g.Floor.Depth
 	}
 	if g.CorruptState != nil {
+		exposure := 0.0
 		if g.ShowWatchers && g.Floor != nil && g.Floor.Watchers != nil {
-			g.CorruptState.AddExposure(g.Floor.Watchers.CorruptionDelta())
+			exposure = g.Floor.Watchers.CorruptionDelta()
+			g.CorruptState.AddExposure(exposure)
 		}
 		g.CorruptState.Update(depth)
 		g.Corruption = g.CorruptState.GetLevel()
+		g.Stats.sample(g.Corruption, exposure)
+	}
+	g.runLog.sample(g)
+
+	// Walls shift only where the player isn't looking: outside the fixed
+	// default view, and outside whatever the 3D view can show with the
+	// current settings (logged for replays, see logViewSettings).
+	if g.Floor != nil && g.Floor.Shifter.Tick(g.Corruption) {
+		cellX, cellY := playerCell(g.Player)
+		visible := g.Raycaster.VisibleCells(g.Player, g.GameMap)
+		visible.Add(g.Raycaster.ViewCells(g.viewer(), g.GameMap))
+		g.Floor.Shifter.Shift(g.Floor, g.Corruption, visible, world.Point{X: cellX, Y: cellY})
 	}
 }
 
+// SetAudio routes the game's and the player's sound events to bus.
+func (g *Game) SetAudio(bus *audio.Bus) {
+	g.Audio = bus
+	if g.Player != nil {
+		g.Player.Audio = bus
+	}
+}
+
+// SetEvents connects the game's systems through bus: the FloorManager
+// descends on stairs, corruption and watchers publish, and the game, HUD and
+// audio react.
+func (g *Game) SetEvents(bus *events.Bus) {
+	g.Events = bus
+	if g.FloorManager != nil {
+		g.FloorManager.Listen(bus)
+	}
+	if g.CorruptState != nil {
+		g.CorruptState.Events = bus
+	}
+	events.Subscribe(bus, g.enterFloor)
+	events.Subscribe(bus, func(events.WatcherSeen) { g.watchersSeenCount++ })
+	g.listenStats(bus)
+	g.listenSpeedrun(bus)
+	g.listenHUD(bus)
+	g.listenAudio(bus)
+}
+
+// enterFloor makes the FloorManager's current floor the one being played and
+// puts the player on its spawn.
+func (g *Game) enterFloor(events.FloorEntered) {
+	if g.FloorManager == nil || g.FloorManager.CurrentFloor == nil {
+		return
+	}
+	g.Floor = g.FloorManager.CurrentFloor
+	g.GameMap = g.Floor.Map
+	g.lastCell = g.Floor.SpawnPos
+	g.stairsSeen = false
+	if g.Player != nil {
+		g.Player.SetCell(g.Floor.SpawnPos.X, g.Floor.SpawnPos.Y)
+	}
+}
+
+// watchersSeen returns how many distinct watchers have shown themselves this run.
+func (g *Game) watchersSeen() int {
+	return g.watchersSeenCount
+}
+
 // render draws the current game state to screen
 func (g *Game) render() {
 	g.Screen.Clear()
@@ -161,91 +321,152 @@ func (g *Game) render() {
 	if g.CorruptState != nil {
 		effects = render.NewEffectsContext(g.CorruptState.Depth, g.CorruptState.GetLevel(), g.CorruptState.Ticks)
 	}
+	if g.Floor != nil {
+		biome := &g.Floor.Biome
+		effects.Theme = g.Floor.Theme
+		effects.Shading = &biome.Shading
+		effects.Scale = &biome.Effects
+		g.Raycaster.Shading = &biome.Shading
+		g.Raycaster.Palette = &biome.Palette
+	}
+	effects.WatchersSeen = g.watchersSeen()
+	effects.Audio = g.Audio
 	var watchers *entities.WatcherManager
 	if g.ShowWatchers && g.Floor != nil {
 		watchers = g.Floor.Watchers
 	}
-	g.Raycaster.RenderWithEffects(g.Screen, g.Player, g.GameMap, effects, watchers)
-
-	// Screen-space corruption overlays (below HUD).
-	render.RenderWhisperAt(g.Screen, effects, g.Width, g.Height)
-	render.ApplyFakeGeometryAt(g.Screen, effects, g.Width, g.Height)
-
-	// HUD overlay
-	hudStyle := tcell.StyleDefault.Foreground(tcell.ColorGreen).Background(tcell.ColorBlack)
-	playerStyle := tcell.StyleDefault.Foreground(tcell.ColorAqua).Background(tcell.ColorBlack)
-	stairsStyle := tcell.StyleDefault.Foreground(tcell.ColorYellow).Background(tcell.ColorBlack)
-	dimStyle := tcell.StyleDefault.Foreground(tcell.ColorDarkGray).Background(tcell.ColorBlack)
-
-	// Status line at top
-	depth := 0
-	if g.Floor != nil {
-		depth = g.Floor.Depth
-	}
-	status := fmt.Sprintf(" Depth: %d | Corruption: %.0f%% ", depth, g.Corruption*100)
-	g.drawString(0, 0, status, hudStyle)
-
-	// Mini-map (top-right, offset below status line)
-	if g.ShowMiniMap && g.Floor != nil && g.GameMap != nil && g.Player != nil {
-		cellX, cellY := playerCell(g.Player)
-		lines := buildMiniMapRect(g.GameMap, cellX, cellY, g.Floor.StairsPos.X, g.Floor.StairsPos.Y, defaultMiniMapRadiusX, defaultMiniMapRadius)
-		if len(lines) > 0 {
-			mapH := len(lines)
-			mapW := len([]rune(lines[0]))
-			startX := miniMapStartX(g.Width, mapW)
-			startY := 1
-			if startY+mapH < g.Height {
-				for y := 0; y < mapH; y++ {
-					for x, r := range []rune(lines[y]) {
-						if startX+x >= g.Width-miniMapRightMargin {
-							break
-						}
-						style := dimStyle
-						switch r {
-						case '#':
-							style = hudStyle
-						case '@':
-							style = playerStyle
-						case render.StairsChar:
-							style = stairsStyle
-						}
-						g.Screen.SetContent(startX+x, startY+y, r, nil, style)
-					}
-				}
-			}
-		}
-	}
-
-	// Controls at bottom
-	controls := " W/S: Move | A/D: Turn | C: Cheats | Q: Quit "
-	g.drawString(0, g.Height-1, controls, hudStyle)
-
-	if g.Hint != "" && g.Height >= 2 {
-		g.drawString(0, g.Height-2, " "+g.Hint+" ", stairsStyle)
+	layout := layoutHUD(g.Width, g.Height, g.hudPanels())
+	vp := layout.Viewport
+	g.Raycaster.SetViewport(vp.X, vp.Y, vp.W, vp.H)
+	if g.godView {
+		g.drawGodView(vp)
+	} else {
+		g.Raycaster.RenderWithEffects(g.Screen, g.viewer(), g.GameMap, effects, watchers)
+
+		// Corruption overlays, kept inside the view like the raycast.
+		render.RenderWhisperAt(g.Screen, effects, vp.X, vp.Y, vp.W, vp.H)
+		render.ApplyFakeGeometryAt(g.Screen, effects, vp.X, vp.Y, vp.W, vp.H)
 	}
 
-	if g.cheatMenuOpen {
-		g.renderCheatMenu()
-	}
+	layout.drawHUD(g.Screen, g.Width, g.Height)
+	applyColorMode(g.Screen, g.Width, g.Height, g.ColorMode)
 }
 
 // drawString is a helper to draw a string at x,y
 func (g *Game) drawString(x, y int, str string, style tcell.Style) {
-	for i, r := range str {
+	for i, r := range []rune(str) {
 		g.Screen.SetContent(x+i, y, r, nil, style)
 	}
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "stats" {
+		if err := runStats(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "stats: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	fsDefault := fmt.Sprintf("%dx%d", world.DefaultMapWidth, world.DefaultMapHeight)
 	floorSizeFlag := flag.String("fs", fsDefault, "floor size WxH (e.g. 16x16)")
+	cpuProfileFlag := flag.String("cpuprofile", "", "write a CPU profile of the main loop to file")
+	traceFlag := flag.String("trace", "", "write an execution trace of the main loop to file")
+	replayFlag := flag.String("replay", "", "play back a recorded replay file")
+	replaySpeedFlag := flag.Int("replay-speed", 1, "initial replay playback speed multiplier")
+	recordFlag := flag.String("record", "", "write the run's replay to file (default replays/run-<time>.replay)")
+	themeFlag := flag.String("theme", "", "theme pack file or directory of packs layered over the default")
+	audioFlag := flag.String("audio", "none", "audio backend: none, bell or cmd")
+	audioCmdFlag := flag.String("audio-cmd", "", "command for -audio cmd: a template using {event}, or event=cmd pairs separated by ';'")
+	historyFlag := flag.Bool("history", false, "print past runs from the history file and exit")
+	logFlag := flag.String("log", "", "write a JSON-lines run log to file (summarise with: stats FILE)")
+	dailyFlag := flag.Bool("daily", false, "play today's daily challenge (UTC date seed, fixed floor size, no cheats)")
+	verifyFlag := flag.String("verify", "", "re-simulate a daily replay file and print or check its result")
+	claimFlag := flag.String("claim", "", "result string for -verify to confirm")
+	speedrunFlag := flag.Int("speedrun", 0, "race to this depth with a split timer and personal best (0 = off)")
+	miniMapFlag := flag.String("minimap", "north-up", "mini-map mode: north-up, heading-up or braille")
+	continueFlag := flag.Bool("continue", false, "resume the run left with Save & Quit")
 	flag.Parse()
 
+	if *verifyFlag != "" {
+		rp, err := readReplayFile(*verifyFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading replay %q: %v\n", *verifyFlag, err)
+			os.Exit(1)
+		}
+		result, err := verifyDaily(rp, *claimFlag, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Verification failed: %v\n", err)
+			os.Exit(1)
+		}
+		if *claimFlag != "" {
+			fmt.Printf("Verified: %s\n", result)
+		} else {
+			fmt.Println(result)
+		}
+		return
+	}
+
+	if *historyFlag {
+		entries, err := readHistory(defaultHistoryPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading history: %v\n", err)
+			os.Exit(1)
+		}
+		printHistory(os.Stdout, entries)
+		return
+	}
+
 	floorW, floorH, err := parseFloorSize(*floorSizeFlag)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Invalid -fs %q: %v\n", *floorSizeFlag, err)
 		os.Exit(2)
 	}
+	if *speedrunFlag < 0 || *speedrunFlag == 1 {
+		fmt.Fprintf(os.Stderr, "Invalid -speedrun %d: the target depth must be 2 or more\n", *speedrunFlag)
+		os.Exit(2)
+	}
+	miniMapMode, err := parseMiniMapMode(*miniMapFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -minimap: %v\n", err)
+		os.Exit(2)
+	}
+	date := dailyDate(time.Now())
+	if *dailyFlag {
+		floorW, floorH = dailyFloorW, dailyFloorH
+	}
+
+	var save *Replay
+	if *continueFlag {
+		if *replayFlag != "" || *dailyFlag || *speedrunFlag > 0 {
+			fmt.Fprintln(os.Stderr, "-continue cannot be combined with -replay, -daily or -speedrun")
+			os.Exit(2)
+		}
+		save, err = readSave(defaultSavePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot continue: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var replay *Replay
+	if *replayFlag != "" {
+		replay, err = readReplayFile(*replayFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading replay %q: %v\n", *replayFlag, err)
+			os.Exit(1)
+		}
+	}
+
+	pack := theme.Default()
+	if *themeFlag != "" {
+		extra, err := theme.LoadFile(*themeFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading theme %q: %v\n", *themeFlag, err)
+			os.Exit(1)
+		}
+		pack = pack.Layer(extra)
+	}
 
 	screen, err := tcell.NewScreen()
 	if err != nil {
@@ -259,17 +480,91 @@ func main() {
 	}
 	defer screen.Fini()
 
-	game := NewGame(screen, floorW, floorH)
+	var game *Game
+	var seed int64
+	if replay != nil {
+		seed = replay.Seed
+		game = NewGameWithSeed(screen, replay.FloorW, replay.FloorH, seed)
+		game.replay = newReplayPlayer(replay, *replaySpeedFlag)
+		// Daily runs are played without cheats; replay them the same way.
+		_, game.daily = dailyDateForSeed(seed, time.Now())
+	} else if save != nil {
+		seed = save.Seed
+		game = NewGameWithSeed(screen, save.FloorW, save.FloorH, seed)
+		game.recorder = newReplayRecorder(seed, save.FloorW, save.FloorH)
+		// A saved daily keeps the daily rules.
+		if d, ok := dailyDateForSeed(seed, time.Now()); ok {
+			date, game.daily = d, true
+		}
+	} else {
+		seed = time.Now().UnixNano()
+		if *dailyFlag {
+			seed = dailySeed(date)
+		}
+		game = NewGameWithSeed(screen, floorW, floorH, seed)
+		game.recorder = newReplayRecorder(seed, floorW, floorH)
+		game.daily = *dailyFlag
+		if *speedrunFlag > 0 {
+			// An unreadable PB file just means racing without comparisons.
+			pbs, _ := readSpeedrunPBs(defaultPBPath)
+			game.speedrun = newSpeedrun(*speedrunFlag, floorW, floorH, pbs, game.Frame)
+		}
+	}
+	game.FloorManager.SetTheme(pack)
+	game.MiniMapMode = miniMapMode
+	if save != nil {
+		game.restoreRun(save)
+	}
+
+	if *logFlag != "" {
+		l, err := openRunLog(*logFlag)
+		if err != nil {
+			screen.Fini()
+			fmt.Fprintf(os.Stderr, "Error opening run log: %v\n", err)
+			os.Exit(1)
+		}
+		game.attachRunLog(l, seed)
+	}
+
+	bus, err := newAudioBus(*audioFlag, *audioCmdFlag, screen.Beep)
+	if err != nil {
+		screen.Fini()
+		fmt.Fprintf(os.Stderr, "Invalid audio settings: %v\n", err)
+		os.Exit(2)
+	}
+	game.SetAudio(bus)
+
+	stopProfiling, err := startProfiling(*cpuProfileFlag, *traceFlag)
+	if err != nil {
+		screen.Fini()
+		fmt.Fprintf(os.Stderr, "Error starting profiling: %v\n", err)
+		os.Exit(1)
+	}
+	defer stopProfiling()
 
 	// Main game loop - target ~60fps (16ms per frame)
 	frameDuration := time.Duration(16) * time.Millisecond
+	lastFrame := time.Now()
 
 	for game.Running {
 		frameStart := time.Now()
+		game.perf.Frame = frameStart.Sub(lastFrame)
+		lastFrame = frameStart
+
+		if game.replay != nil {
+			game.replay.tick(game)
+		} else {
+			game.handleInput()
+			game.update()
+		}
+		updateDone := time.Now()
+		game.perf.Update = updateDone.Sub(frameStart)
 
-		game.handleInput()
-		game.update()
 		game.render()
+		game.perf.Render = time.Since(updateDone)
+		game.perf.Rays = game.Raycaster.Stats.RaysCast
+		game.perf.Cells = game.Raycaster.Stats.CellsWritten
+		game.recordCastFrame(time.Now())
 		game.Screen.Show()
 
 		// Sleep for remaining frame time
@@ -278,4 +573,75 @@ func main() {
 			time.Sleep(frameDuration - elapsed)
 		}
 	}
+
+	game.cast.Close()
+	// The screen stays up until after the end screen, so errors from here on
+	// are collected and printed once it is finalized.
+	var exitErrs []error
+	if err := game.runLog.close(game); err != nil {
+		exitErrs = append(exitErrs, fmt.Errorf("writing run log: %w", err))
+	}
+
+	if game.savedAndQuit {
+		screen.Fini()
+		printExitErrors(exitErrs)
+		fmt.Printf("Run saved: %s (resume with -continue)\n", defaultSavePath)
+		return
+	}
+	if save != nil {
+		if err := removeSave(defaultSavePath); err != nil {
+			exitErrs = append(exitErrs, fmt.Errorf("removing save: %w", err))
+		}
+	}
+
+	// Replays re-watch an old run, so they get no end screen or history entry.
+	if game.replay == nil {
+		game.finishRun(time.Now())
+		// An unreadable history only costs the "new deepest" line.
+		history, _ := readHistory(defaultHistoryPath)
+		game.showEndScreen(bestRun(history))
+		if err := appendHistory(defaultHistoryPath, newHistoryEntry(&game.Stats)); err != nil {
+			exitErrs = append(exitErrs, fmt.Errorf("saving run history: %w", err))
+		}
+		if err := saveSpeedrunPB(defaultPBPath, game.speedrun, seed); err != nil {
+			exitErrs = append(exitErrs, fmt.Errorf("saving speedrun PB: %w", err))
+		}
+	}
+	screen.Fini()
+	printExitErrors(exitErrs)
+
+	if game.recorder != nil {
+		path := *recordFlag
+		if path == "" && game.daily {
+			path = filepath.Join(".", "replays", dailyReplayFilename(date, time.Now()))
+			err = os.MkdirAll(filepath.Dir(path), 0o755)
+		}
+		if err == nil {
+			path, err = game.saveReplay(path)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving replay: %v\n", err)
+		} else {
+			fmt.Printf("Replay saved: %s\n", path)
+		}
+	}
+
+	if game.daily && game.recorder != nil {
+		// The result comes from re-simulating the input log, exactly as
+		// -verify will, so a shared result always verifies.
+		result, err := dailyResultFor(date, game.recorder.finish(game.Frame))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error computing daily result: %v\n", err)
+			return
+		}
+		fmt.Printf("Daily result: %s\n", result)
+	}
+}
+
+// printExitErrors reports errors from shutting down a session, after the
+// screen has been finalized.
+func printExitErrors(errs []error) {
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+	}
 }
@@ -0,0 +1,413 @@
+// Package menu is a keyboard-driven menu model for the HUD: lists of actions,
+// toggles, steppers, number and text prompts, and nested submenus. It knows
+// nothing about drawing; Lines returns the text to put in a box.
+package menu
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ErrEmpty is the prompt error for submitting nothing to a Number.
+var ErrEmpty = errors.New("nothing entered")
+
+// Result tells the caller what a key did.
+type Result int
+
+const (
+	// Ignored means the key meant nothing to the menu.
+	Ignored Result = iota
+	// Handled means the menu used the key.
+	Handled
+	// Closed means the top-level menu was dismissed (Esc, or q with
+	// QuitKey set).
+	Closed
+)
+
+type kind int
+
+const (
+	kindAction kind = iota
+	kindToggle
+	kindStepper
+	kindInput
+	kindSubmenu
+)
+
+// Item is one line of a menu. Build items with Action, Toggle, Stepper,
+// Number, Text and Submenu.
+type Item struct {
+	Label string
+	// Keys activate the item directly, as Enter on it would.
+	Keys []rune
+	// DecKeys and IncKeys step a Stepper down and up.
+	DecKeys []rune
+	IncKeys []rune
+
+	kind   kind
+	action func()
+	get    func() bool
+	set    func(bool)
+	value  func() string
+	step   func(delta int)
+	input  *input
+	sub    *Menu
+}
+
+// input is the state of a number or text prompt.
+type input struct {
+	info   func() string
+	accept func(rune) bool
+	max    int
+	submit func(string) error
+	buf    []rune
+	err    string
+}
+
+// Action runs fn on Enter.
+func Action(label string, fn func()) *Item {
+	return &Item{Label: label, kind: kindAction, action: fn}
+}
+
+// Toggle shows get as ON/OFF and flips it with set on Enter or Left/Right.
+func Toggle(label string, get func() bool, set func(bool)) *Item {
+	return &Item{Label: label, kind: kindToggle, get: get, set: set}
+}
+
+// Stepper shows value and calls step with -1 or +1 on Left/Right; Enter steps
+// up.
+func Stepper(label string, value func() string, step func(delta int)) *Item {
+	return &Item{Label: label, kind: kindStepper, value: value, step: step}
+}
+
+// Number opens a prompt for up to maxDigits digits. submit receives the
+// number; an error is shown in the prompt, nil closes it. info, if set, is an
+// extra line shown above the prompt.
+func Number(label string, maxDigits int, info func() string, submit func(int) error) *Item {
+	return &Item{Label: label, kind: kindInput, input: &input{
+		info:   info,
+		accept: isASCIIDigit,
+		max:    maxDigits,
+		submit: func(s string) error {
+			if s == "" {
+				return ErrEmpty
+			}
+			n := 0
+			for _, r := range s {
+				n = n*10 + int(r-'0')
+			}
+			return submit(n)
+		},
+	}}
+}
+
+// isASCIIDigit accepts only '0'-'9': other Unicode digits can't be turned into
+// a value with r-'0'.
+func isASCIIDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// Text opens a prompt for up to maxLen printable characters.
+func Text(label string, maxLen int, info func() string, submit func(string) error) *Item {
+	return &Item{Label: label, kind: kindInput, input: &input{
+		info:   info,
+		accept: unicode.IsPrint,
+		max:    maxLen,
+		submit: submit,
+	}}
+}
+
+// Submenu opens sub on Enter; Esc in sub comes back.
+func Submenu(label string, sub *Menu) *Item {
+	return &Item{Label: label, kind: kindSubmenu, sub: sub}
+}
+
+// WithKeys sets the item's hotkeys.
+func (it *Item) WithKeys(keys ...rune) *Item {
+	it.Keys = keys
+	return it
+}
+
+// WithStepKeys sets a Stepper's down and up hotkeys.
+func (it *Item) WithStepKeys(dec, inc []rune) *Item {
+	it.DecKeys, it.IncKeys = dec, inc
+	return it
+}
+
+// Menu is a page of items with a cursor. While a submenu or prompt is open,
+// keys and Lines go to it.
+type Menu struct {
+	Title string
+	Items []*Item
+	// Footer is a help line under the items.
+	Footer string
+	// Message is a status line under the footer, e.g. the result of the
+	// last action.
+	Message string
+	// LetterNav adds W/S to move and A/D to step, after hotkeys.
+	LetterNav bool
+	// QuitKey makes q and Q dismiss the top-level menu like Esc.
+	QuitKey bool
+	Cursor  int
+
+	open   *Menu // open submenu
+	prompt *Item // open prompt
+}
+
+// Reset closes any submenu or prompt and clears messages, ready to show the
+// menu again.
+func (m *Menu) Reset() {
+	if m.open != nil {
+		m.open.Reset()
+	}
+	m.open, m.prompt = nil, nil
+	m.Message = ""
+	m.Cursor = 0
+}
+
+// Depth is how many submenus and prompts are open below m.
+func (m *Menu) Depth() int {
+	switch {
+	case m.prompt != nil:
+		return 1
+	case m.open != nil:
+		return 1 + m.open.Depth()
+	}
+	return 0
+}
+
+// HandleKey applies a key to the innermost open page.
+func (m *Menu) HandleKey(ev *tcell.EventKey) Result {
+	if m.open != nil {
+		if m.open.HandleKey(ev) == Closed {
+			m.open = nil
+		}
+		return Handled
+	}
+	if m.prompt != nil {
+		m.handlePrompt(ev)
+		return Handled
+	}
+
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		return Closed
+	case tcell.KeyUp:
+		m.move(-1)
+		return Handled
+	case tcell.KeyDown:
+		m.move(1)
+		return Handled
+	case tcell.KeyLeft:
+		m.stepCursor(-1)
+		return Handled
+	case tcell.KeyRight:
+		m.stepCursor(1)
+		return Handled
+	case tcell.KeyEnter:
+		if len(m.Items) > 0 {
+			m.activate(m.Items[m.Cursor])
+		}
+		return Handled
+	case tcell.KeyRune:
+	default:
+		return Ignored
+	}
+
+	r := ev.Rune()
+	for i, it := range m.Items {
+		switch {
+		case hasKey(it.Keys, r):
+			m.Cursor = i
+			m.activate(it)
+			return Handled
+		case hasKey(it.DecKeys, r):
+			m.Cursor = i
+			it.doStep(-1)
+			return Handled
+		case hasKey(it.IncKeys, r):
+			m.Cursor = i
+			it.doStep(1)
+			return Handled
+		}
+	}
+	if m.QuitKey && (r == 'q' || r == 'Q') {
+		return Closed
+	}
+	if m.LetterNav {
+		switch unicode.ToLower(r) {
+		case 'w':
+			m.move(-1)
+			return Handled
+		case 's':
+			m.move(1)
+			return Handled
+		case 'a':
+			m.stepCursor(-1)
+			return Handled
+		case 'd':
+			m.stepCursor(1)
+			return Handled
+		}
+	}
+	return Ignored
+}
+
+func (m *Menu) move(delta int) {
+	if n := len(m.Items); n > 0 {
+		m.Cursor = (m.Cursor + delta + n) % n
+	}
+}
+
+func (m *Menu) stepCursor(delta int) {
+	if len(m.Items) > 0 {
+		m.Items[m.Cursor].doStep(delta)
+	}
+}
+
+func (it *Item) doStep(delta int) {
+	switch it.kind {
+	case kindStepper:
+		it.step(delta)
+	case kindToggle:
+		it.set(!it.get())
+	}
+}
+
+func (m *Menu) activate(it *Item) {
+	switch it.kind {
+	case kindAction:
+		it.action()
+	case kindToggle:
+		it.set(!it.get())
+	case kindStepper:
+		it.step(1)
+	case kindInput:
+		it.input.buf = it.input.buf[:0]
+		it.input.err = ""
+		m.prompt = it
+	case kindSubmenu:
+		it.sub.Reset()
+		m.open = it.sub
+	}
+}
+
+func (m *Menu) handlePrompt(ev *tcell.EventKey) {
+	in := m.prompt.input
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		m.prompt = nil
+	case tcell.KeyEnter:
+		text := string(in.buf)
+		in.buf = in.buf[:0]
+		if err := in.submit(text); err != nil {
+			in.err = err.Error()
+			return
+		}
+		m.prompt = nil
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(in.buf) > 0 {
+			in.buf = in.buf[:len(in.buf)-1]
+		}
+	case tcell.KeyRune:
+		if r := ev.Rune(); in.accept(r) && len(in.buf) < in.max {
+			in.buf = append(in.buf, r)
+		}
+	}
+}
+
+// Lines renders the innermost open page.
+func (m *Menu) Lines() []string {
+	if m.open != nil {
+		return m.open.Lines()
+	}
+	if m.prompt != nil {
+		return m.promptLines()
+	}
+
+	lines := []string{m.Title, ""}
+	for i, it := range m.Items {
+		marker := "  "
+		if i == m.Cursor {
+			marker = "> "
+		}
+		lines = append(lines, marker+it.keyLabel()+it.Label+it.valueLabel())
+	}
+	if m.Footer != "" {
+		lines = append(lines, "", m.Footer)
+	}
+	if m.Message != "" {
+		lines = append(lines, m.Message)
+	}
+	return lines
+}
+
+func (m *Menu) promptLines() []string {
+	in := m.prompt.input
+	lines := []string{m.Title + ": " + strings.ToUpper(m.prompt.Label)}
+	if in.info != nil {
+		lines = append(lines, in.info())
+	}
+	lines = append(lines, "> "+string(in.buf), "", "Enter: OK  Esc: Back")
+	if in.err != "" {
+		lines = append(lines, in.err)
+	}
+	return lines
+}
+
+// keyLabel is the "[K] " prefix naming an item's hotkeys.
+func (it *Item) keyLabel() string {
+	var keys []string
+	for _, r := range it.Keys {
+		keys = append(keys, string(unicode.ToUpper(r)))
+	}
+	if len(it.DecKeys) > 0 && len(it.IncKeys) > 0 {
+		keys = append(keys, string(it.IncKeys[0])+"/"+string(it.DecKeys[0]))
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	return "[" + dedupe(keys) + "] "
+}
+
+func (it *Item) valueLabel() string {
+	switch it.kind {
+	case kindToggle:
+		if it.get() {
+			return ": ON"
+		}
+		return ": OFF"
+	case kindStepper:
+		return ": < " + it.value() + " >"
+	case kindSubmenu:
+		return " >"
+	case kindInput:
+		return "..."
+	}
+	return ""
+}
+
+// dedupe joins keys, dropping repeats such as the upper-case twin of a key.
+func dedupe(keys []string) string {
+	seen := map[string]bool{}
+	var out []string
+	for _, k := range keys {
+		if !seen[k] {
+			seen[k] = true
+			out = append(out, k)
+		}
+	}
+	return strings.Join(out, ",")
+}
+
+func hasKey(keys []rune, r rune) bool {
+	for _, k := range keys {
+		if k == r {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,247 @@
+package menu
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func runeKey(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func key(k tcell.Key) *tcell.EventKey {
+	return tcell.NewEventKey(k, 0, tcell.ModNone)
+}
+
+func TestMenuNavigatesAndActivates(t *testing.T) {
+	selected, on, level := "", false, 3
+	m := &Menu{
+		Title: "TEST",
+		Items: []*Item{
+			Action("First", func() { selected = "first" }),
+			Toggle("Flag", func() bool { return on }, func(v bool) { on = v }),
+			Stepper("Level", func() string { return strconv.Itoa(level) }, func(d int) { level += d }),
+		},
+	}
+
+	m.HandleKey(key(tcell.KeyUp))
+	if m.Cursor != 2 {
+		t.Fatalf("expected the cursor to wrap to the last item, got %d", m.Cursor)
+	}
+	m.HandleKey(key(tcell.KeyRight))
+	m.HandleKey(key(tcell.KeyRight))
+	m.HandleKey(key(tcell.KeyLeft))
+	m.HandleKey(key(tcell.KeyEnter))
+	if level != 5 {
+		t.Fatalf("expected level 5 after +1 +1 -1 and Enter, got %d", level)
+	}
+
+	m.HandleKey(key(tcell.KeyUp))
+	m.HandleKey(key(tcell.KeyEnter))
+	if !on {
+		t.Fatal("expected Enter to flip the toggle")
+	}
+	m.HandleKey(key(tcell.KeyDown))
+	m.HandleKey(key(tcell.KeyDown))
+	m.HandleKey(key(tcell.KeyEnter))
+	if selected != "first" {
+		t.Fatalf("expected Enter to run the first item, got %q", selected)
+	}
+
+	if got := m.HandleKey(runeKey('x')); got != Ignored {
+		t.Fatalf("expected unbound keys to be ignored, got %v", got)
+	}
+	if got := m.HandleKey(key(tcell.KeyEscape)); got != Closed {
+		t.Fatalf("expected Esc to close the menu, got %v", got)
+	}
+}
+
+func TestMenuHotkeysAndStepKeys(t *testing.T) {
+	on, level := false, 0
+	m := &Menu{
+		Items: []*Item{
+			Toggle("Flag", func() bool { return on }, func(v bool) { on = v }).WithKeys('f', 'F'),
+			Stepper("Level", func() string { return strconv.Itoa(level) }, func(d int) { level += d }).
+				WithStepKeys([]rune{'-'}, []rune{'+'}),
+		},
+	}
+
+	m.HandleKey(runeKey('F'))
+	if !on {
+		t.Fatal("expected the hotkey to flip the toggle")
+	}
+	m.HandleKey(runeKey('+'))
+	m.HandleKey(runeKey('+'))
+	m.HandleKey(runeKey('-'))
+	if level != 1 {
+		t.Fatalf("expected level 1, got %d", level)
+	}
+	if m.Cursor != 1 {
+		t.Fatalf("expected the cursor to follow the hotkey, got %d", m.Cursor)
+	}
+}
+
+func TestMenuLetterNavAndQuitKey(t *testing.T) {
+	level := 0
+	m := &Menu{
+		LetterNav: true,
+		QuitKey:   true,
+		Items: []*Item{
+			Action("First", func() {}),
+			Stepper("Level", func() string { return strconv.Itoa(level) }, func(d int) { level += d }),
+		},
+	}
+
+	m.HandleKey(runeKey('s'))
+	m.HandleKey(runeKey('d'))
+	m.HandleKey(runeKey('d'))
+	m.HandleKey(runeKey('a'))
+	if m.Cursor != 1 || level != 1 {
+		t.Fatalf("expected cursor 1 and level 1, got %d and %d", m.Cursor, level)
+	}
+	m.HandleKey(runeKey('w'))
+	if m.Cursor != 0 {
+		t.Fatalf("expected w to move up, got %d", m.Cursor)
+	}
+	if got := m.HandleKey(runeKey('q')); got != Closed {
+		t.Fatalf("expected q to close with QuitKey set, got %v", got)
+	}
+}
+
+func TestMenuNumberPrompt(t *testing.T) {
+	got := 0
+	m := &Menu{
+		Title: "TEST",
+		Items: []*Item{
+			Number("Depth", 3, func() string { return "Current: 1" }, func(n int) error {
+				if n == 0 {
+					return errors.New("too shallow")
+				}
+				got = n
+				return nil
+			}).WithKeys('t'),
+		},
+	}
+
+	m.HandleKey(runeKey('t'))
+	if m.Depth() != 1 {
+		t.Fatalf("expected the prompt to open, depth %d", m.Depth())
+	}
+	m.HandleKey(key(tcell.KeyEnter))
+	want := []string{"TEST: DEPTH", "Current: 1", "> ", "", "Enter: OK  Esc: Back", ErrEmpty.Error()}
+	if lines := m.Lines(); !reflect.DeepEqual(lines, want) {
+		t.Fatalf("lines = %q, want %q", lines, want)
+	}
+
+	for _, r := range "0x" {
+		m.HandleKey(runeKey(r))
+	}
+	m.HandleKey(key(tcell.KeyEnter))
+	if m.Depth() != 1 || m.Lines()[5] != "too shallow" {
+		t.Fatalf("expected the submit error to keep the prompt open, got %q", m.Lines())
+	}
+
+	for _, r := range "1٣234" {
+		m.HandleKey(runeKey(r))
+	}
+	m.HandleKey(key(tcell.KeyBackspace2))
+	m.HandleKey(runeKey('9'))
+	m.HandleKey(key(tcell.KeyEnter))
+	if got != 129 {
+		t.Fatalf("expected 129 (ASCII digits only, max 3, one backspace), got %d", got)
+	}
+	if m.Depth() != 0 {
+		t.Fatal("expected a good submit to close the prompt")
+	}
+}
+
+func TestMenuTextPromptEscapes(t *testing.T) {
+	name := ""
+	m := &Menu{
+		Items: []*Item{
+			Text("Name", 8, nil, func(s string) error { name = s; return nil }),
+		},
+	}
+
+	m.HandleKey(key(tcell.KeyEnter))
+	m.HandleKey(runeKey('x'))
+	if got := m.HandleKey(key(tcell.KeyEscape)); got != Handled || m.Depth() != 0 {
+		t.Fatalf("expected Esc to leave only the prompt, got %v at depth %d", got, m.Depth())
+	}
+	if name != "" {
+		t.Fatalf("expected Esc not to submit, got %q", name)
+	}
+
+	m.HandleKey(key(tcell.KeyEnter))
+	for _, r := range "Eibon" {
+		m.HandleKey(runeKey(r))
+	}
+	m.HandleKey(key(tcell.KeyEnter))
+	if name != "Eibon" {
+		t.Fatalf("expected Eibon, got %q", name)
+	}
+}
+
+func TestMenuSubmenu(t *testing.T) {
+	level := 0
+	sub := &Menu{
+		Title: "SUB",
+		Items: []*Item{
+			Stepper("Level", func() string { return strconv.Itoa(level) }, func(d int) { level += d }),
+		},
+	}
+	m := &Menu{Title: "MAIN", Items: []*Item{Submenu("More", sub)}}
+
+	m.HandleKey(key(tcell.KeyEnter))
+	if m.Depth() != 1 || m.Lines()[0] != "SUB" {
+		t.Fatalf("expected the submenu to open, got %q", m.Lines())
+	}
+	m.HandleKey(key(tcell.KeyRight))
+	if level != 1 {
+		t.Fatalf("expected keys to reach the submenu, level %d", level)
+	}
+	if got := m.HandleKey(key(tcell.KeyEscape)); got != Handled || m.Depth() != 0 {
+		t.Fatalf("expected Esc to go back to the parent, got %v at depth %d", got, m.Depth())
+	}
+
+	m.HandleKey(key(tcell.KeyEnter))
+	m.Reset()
+	if m.Depth() != 0 {
+		t.Fatal("expected Reset to close the submenu")
+	}
+}
+
+func TestMenuLines(t *testing.T) {
+	m := &Menu{
+		Title:   "TEST",
+		Footer:  "help",
+		Message: "done",
+		Items: []*Item{
+			Action("Go", func() {}).WithKeys('g', 'G'),
+			Toggle("Flag", func() bool { return true }, func(bool) {}),
+			Stepper("Speed", func() string { return "fast" }, func(int) {}).WithStepKeys([]rune{'-'}, []rune{'+'}),
+			Number("Seed", 4, nil, func(int) error { return nil }),
+			Submenu("More", &Menu{}),
+		},
+		Cursor: 2,
+	}
+	want := []string{
+		"TEST",
+		"",
+		"  [G] Go",
+		"  Flag: ON",
+		"> [+/-] Speed: < fast >",
+		"  Seed...",
+		"  More >",
+		"",
+		"help",
+		"done",
+	}
+	if got := m.Lines(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("lines = %q, want %q", got, want)
+	}
+}
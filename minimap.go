@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"game/engine"
+	"game/render"
+)
+
+// miniMapMode selects how the mini-map is drawn.
+type miniMapMode int
+
+const (
+	// miniMapNorthUp draws one cell per glyph around the player, north up.
+	miniMapNorthUp miniMapMode = iota
+	// miniMapHeadingUp rotates the same view so the player faces up.
+	miniMapHeadingUp
+	// miniMapBraille packs 2x4 cells into each braille glyph and shows the
+	// whole floor.
+	miniMapBraille
+	miniMapModeCount
+)
+
+var miniMapModeNames = [...]string{"north-up", "heading-up", "braille"}
+
+func (m miniMapMode) String() string {
+	if m < 0 || m >= miniMapModeCount {
+		return "unknown"
+	}
+	return miniMapModeNames[m]
+}
+
+func parseMiniMapMode(s string) (miniMapMode, error) {
+	for i, name := range miniMapModeNames {
+		if s == name {
+			return miniMapMode(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown mini-map mode %q (want north-up, heading-up or braille)", s)
+}
+
+// playerArrows are the player markers by heading, clockwise from east (angles
+// grow towards south).
+var playerArrows = [8]rune{'→', '↘', '↓', '↙', '←', '↖', '↑', '↗'}
+
+// playerArrow returns the arrow pointing along angle, snapped to 45°.
+func playerArrow(angle float64) rune {
+	i := int(math.Round(angle/(math.Pi/4))) % 8
+	if i < 0 {
+		i += 8
+	}
+	return playerArrows[i]
+}
+
+func isPlayerArrow(r rune) bool {
+	for _, a := range playerArrows {
+		if r == a {
+			return true
+		}
+	}
+	return false
+}
+
+// miniMapGlyph is how a cell appears on the north-up and heading-up maps.
+// The map lies about illusions the same way the view does.
+func miniMapGlyph(gameMap *engine.GameMap, x, y int) rune {
+	if !gameMap.IsValid(x, y) {
+		return ' '
+	}
+	switch gameMap.GetCell(x, y) {
+	case engine.CellWall, engine.CellFakeWall:
+		return '#'
+	case engine.CellStairs:
+		return render.StairsChar
+	case engine.CellNote:
+		return render.NoteChar
+	case engine.CellPortal:
+		return render.PortalChar
+	default:
+		return '.'
+	}
+}
+
+// buildMiniMapHeadingUp renders the cells around the player rotated so the
+// player's facing points up. Each glyph samples the cell under its centre.
+func buildMiniMapHeadingUp(gameMap *engine.GameMap, player *engine.Player, stairsX, stairsY, radiusX, radiusY int) []string {
+	if gameMap == nil || player == nil || radiusX < 0 || radiusY < 0 {
+		return nil
+	}
+
+	// Screen up is the facing direction; screen right is 90° clockwise of it.
+	fwdX, fwdY := math.Cos(player.Angle), math.Sin(player.Angle)
+	rightX, rightY := -fwdY, fwdX
+	cx, cy := math.Floor(player.X)+0.5, math.Floor(player.Y)+0.5
+
+	lines := make([]string, 0, radiusY*2+1)
+	for dy := -radiusY; dy <= radiusY; dy++ {
+		row := make([]rune, 0, radiusX*2+1)
+		for dx := -radiusX; dx <= radiusX; dx++ {
+			wx := cx + float64(dx)*rightX - float64(dy)*fwdX
+			wy := cy + float64(dx)*rightY - float64(dy)*fwdY
+			x, y := int(math.Floor(wx)), int(math.Floor(wy))
+
+			ch := miniMapGlyph(gameMap, x, y)
+			if x == stairsX && y == stairsY {
+				ch = render.StairsChar
+			}
+			if dx == 0 && dy == 0 {
+				ch = playerArrows[6] // always facing up
+			}
+			row = append(row, ch)
+		}
+		lines = append(lines, string(row))
+	}
+	return lines
+}
+
+// brailleDots maps a cell's position within a 2x4 braille block to its dot bit.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+const (
+	brailleBlank      = '⠀'
+	brailleCellsWide  = 2
+	brailleCellsHigh  = 4
+	brailleMaxDotRune = brailleBlank + 0xFF
+)
+
+// isBraille reports whether r is a braille pattern glyph.
+func isBraille(r rune) bool {
+	return r >= brailleBlank && r <= brailleMaxDotRune
+}
+
+// buildMiniMapBraille renders the whole floor with a raised dot per solid
+// cell, 2x4 cells per glyph. The glyphs holding the player and the stairs are
+// replaced by the player's arrow and the stairs marker.
+func buildMiniMapBraille(gameMap *engine.GameMap, player *engine.Player, stairsX, stairsY int) []string {
+	if gameMap == nil || gameMap.Width <= 0 || gameMap.Height <= 0 {
+		return nil
+	}
+	cols := (gameMap.Width + brailleCellsWide - 1) / brailleCellsWide
+	rows := (gameMap.Height + brailleCellsHigh - 1) / brailleCellsHigh
+
+	grid := make([][]rune, rows)
+	for gy := range grid {
+		grid[gy] = make([]rune, cols)
+		for gx := range grid[gy] {
+			ch := rune(brailleBlank)
+			for sy := 0; sy < brailleCellsHigh; sy++ {
+				for sx := 0; sx < brailleCellsWide; sx++ {
+					x, y := gx*brailleCellsWide+sx, gy*brailleCellsHigh+sy
+					switch miniMapGlyph(gameMap, x, y) {
+					case '#', render.PortalChar:
+						ch |= brailleDots[sy][sx]
+					}
+				}
+			}
+			grid[gy][gx] = ch
+		}
+	}
+
+	mark := func(x, y int, r rune) {
+		if gameMap.IsValid(x, y) {
+			grid[y/brailleCellsHigh][x/brailleCellsWide] = r
+		}
+	}
+	mark(stairsX, stairsY, render.StairsChar)
+	if player != nil {
+		px, py := playerCell(player)
+		mark(px, py, playerArrow(player.Angle))
+	}
+
+	lines := make([]string, rows)
+	for i, row := range grid {
+		lines[i] = string(row)
+	}
+	return lines
+}
+
+// miniMapVariants builds the mini-map for the game's current mode, largest
+// first, for the HUD layout to pick from.
+func (g *Game) miniMapVariants() [][]string {
+	if g.MiniMapMode == miniMapBraille {
+		return [][]string{g.miniMapLines(0, 0)}
+	}
+	return [][]string{
+		g.miniMapLines(defaultMiniMapRadiusX, defaultMiniMapRadius),
+		g.miniMapLines(compactMiniMapRadiusX, compactMiniMapRadius),
+	}
+}
+
+// miniMapLines builds the mini-map for the game's current mode. The radii
+// bound the north-up and heading-up views; braille always shows the floor.
+func (g *Game) miniMapLines(radiusX, radiusY int) []string {
+	if g.Floor == nil || g.GameMap == nil || g.Player == nil {
+		return nil
+	}
+	stairs := g.Floor.StairsPos
+	switch g.MiniMapMode {
+	case miniMapHeadingUp:
+		return buildMiniMapHeadingUp(g.GameMap, g.Player, stairs.X, stairs.Y, radiusX, radiusY)
+	case miniMapBraille:
+		return buildMiniMapBraille(g.GameMap, g.Player, stairs.X, stairs.Y)
+	default:
+		cellX, cellY := playerCell(g.Player)
+		return buildMiniMapRect(g.GameMap, cellX, cellY, g.Player.Angle, stairs.X, stairs.Y, radiusX, radiusY)
+	}
+}
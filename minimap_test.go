@@ -0,0 +1,190 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"game/engine"
+)
+
+// newMiniMapTestMap is a 5x5 room with stairs east of the centre and a note
+// south of it, so rotations are easy to tell apart.
+func newMiniMapTestMap() *engine.GameMap {
+	return &engine.GameMap{
+		Width:  5,
+		Height: 5,
+		Cells: [][]int{
+			{engine.CellWall, engine.CellWall, engine.CellWall, engine.CellWall, engine.CellWall},
+			{engine.CellWall, engine.CellEmpty, engine.CellEmpty, engine.CellEmpty, engine.CellWall},
+			{engine.CellWall, engine.CellEmpty, engine.CellEmpty, engine.CellStairs, engine.CellWall},
+			{engine.CellWall, engine.CellEmpty, engine.CellNote, engine.CellEmpty, engine.CellWall},
+			{engine.CellWall, engine.CellWall, engine.CellWall, engine.CellWall, engine.CellWall},
+		},
+	}
+}
+
+func solidMiniMapTestMap(w, h int) *engine.GameMap {
+	m := &engine.GameMap{Width: w, Height: h, Cells: make([][]int, h)}
+	for y := range m.Cells {
+		m.Cells[y] = make([]int, w)
+		for x := range m.Cells[y] {
+			m.Cells[y][x] = engine.CellWall
+		}
+	}
+	return m
+}
+
+func TestPlayerArrowSnapsToEighths(t *testing.T) {
+	cases := []struct {
+		angle float64
+		want  rune
+	}{
+		{0, '→'},
+		{math.Pi / 4, '↘'},
+		{math.Pi / 2, '↓'},
+		{math.Pi, '←'},
+		{-math.Pi / 2, '↑'},
+		{3 * math.Pi / 2, '↑'},
+		{-math.Pi / 4, '↗'},
+		{0.3, '→'},
+		{2*math.Pi + 0.1, '→'},
+	}
+	for _, c := range cases {
+		if got := playerArrow(c.angle); got != c.want {
+			t.Errorf("playerArrow(%.2f) = %q, want %q", c.angle, got, c.want)
+		}
+	}
+}
+
+func TestBuildMiniMapNorthUpDrawsArrow(t *testing.T) {
+	got := buildMiniMapRect(newMiniMapTestMap(), 2, 2, math.Pi/2, 3, 2, 1, 1)
+	want := []string{
+		"...",
+		".↓v",
+		".?.",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("north-up map:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestBuildMiniMapHeadingUpRotatesToFacing(t *testing.T) {
+	cases := []struct {
+		name  string
+		angle float64
+		want  []string
+	}{
+		{"east", 0, []string{
+			".v.",
+			".↑?",
+			"...",
+		}},
+		{"south", math.Pi / 2, []string{
+			".?.",
+			"v↑.",
+			"...",
+		}},
+		{"west", math.Pi, []string{
+			"...",
+			"?↑.",
+			".v.",
+		}},
+		{"north", -math.Pi / 2, []string{
+			"...",
+			".↑v",
+			".?.",
+		}},
+	}
+	m := newMiniMapTestMap()
+	for _, c := range cases {
+		p := engine.NewPlayerAtCell(2, 2, c.angle)
+		got := buildMiniMapHeadingUp(m, p, 3, 2, 1, 1)
+		if strings.Join(got, "\n") != strings.Join(c.want, "\n") {
+			t.Errorf("%s: heading-up map:\n%s\nwant:\n%s", c.name, strings.Join(got, "\n"), strings.Join(c.want, "\n"))
+		}
+	}
+}
+
+func TestBuildMiniMapHeadingUpKeepsSize(t *testing.T) {
+	p := engine.NewPlayerAtCell(2, 2, 0.7)
+	lines := buildMiniMapHeadingUp(newMiniMapTestMap(), p, 3, 2, 4, 2)
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 lines, got %d", len(lines))
+	}
+	for i, l := range lines {
+		if n := len([]rune(l)); n != 9 {
+			t.Fatalf("line %d: expected width 9, got %d (%q)", i, n, l)
+		}
+	}
+	if got := []rune(lines[2])[4]; got != '↑' {
+		t.Fatalf("expected the player arrow at the centre, got %q", got)
+	}
+}
+
+func TestBuildMiniMapBraillePacksCells(t *testing.T) {
+	// Walls down the left column and across the bottom row of a 4x4 floor.
+	m := &engine.GameMap{
+		Width:  4,
+		Height: 4,
+		Cells: [][]int{
+			{engine.CellWall, engine.CellEmpty, engine.CellEmpty, engine.CellEmpty},
+			{engine.CellWall, engine.CellEmpty, engine.CellEmpty, engine.CellEmpty},
+			{engine.CellWall, engine.CellEmpty, engine.CellEmpty, engine.CellEmpty},
+			{engine.CellWall, engine.CellWall, engine.CellWall, engine.CellPortal},
+		},
+	}
+	got := buildMiniMapBraille(m, nil, -1, -1)
+	// Left glyph: dots 1,2,3,7 (left column) and 8 (bottom right).
+	// Right glyph: dots 7 and 8 (bottom row, portal included).
+	want := []string{"⣇⣀"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("braille map = %q, want %q", got, want)
+	}
+}
+
+func TestBuildMiniMapBrailleFitsWholeFloor(t *testing.T) {
+	m := solidMiniMapTestMap(64, 64)
+	lines := buildMiniMapBraille(m, nil, -1, -1)
+	if len(lines) != 16 {
+		t.Fatalf("expected 16 lines for a 64-high floor, got %d", len(lines))
+	}
+	for i, l := range lines {
+		if l != strings.Repeat("⣿", 32) {
+			t.Fatalf("line %d: expected 32 full glyphs, got %q", i, l)
+		}
+	}
+
+	odd := buildMiniMapBraille(solidMiniMapTestMap(5, 5), nil, -1, -1)
+	if len(odd) != 2 || len([]rune(odd[0])) != 3 {
+		t.Fatalf("expected a 5x5 floor to round up to 3x2 glyphs, got %q", odd)
+	}
+}
+
+func TestBuildMiniMapBrailleMarksPlayerAndStairs(t *testing.T) {
+	m := solidMiniMapTestMap(8, 8)
+	p := engine.NewPlayerAtCell(5, 6, math.Pi)
+	lines := buildMiniMapBraille(m, p, 1, 2)
+
+	if got := []rune(lines[0])[0]; got != 'v' {
+		t.Fatalf("expected stairs marker in the top-left glyph, got %q", got)
+	}
+	if got := []rune(lines[1])[2]; got != '←' {
+		t.Fatalf("expected player arrow in glyph (2,1), got %q", got)
+	}
+	if got := []rune(lines[1])[0]; !isBraille(got) {
+		t.Fatalf("expected other glyphs to stay braille, got %q", got)
+	}
+}
+
+func TestParseMiniMapMode(t *testing.T) {
+	for m := miniMapNorthUp; m < miniMapModeCount; m++ {
+		got, err := parseMiniMapMode(m.String())
+		if err != nil || got != m {
+			t.Fatalf("parseMiniMapMode(%q) = %v, %v", m.String(), got, err)
+		}
+	}
+	if _, err := parseMiniMapMode("sideways"); err == nil {
+		t.Fatal("expected an error for an unknown mode")
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"game/lore"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	noteReaderMaxWidth = 56
+	noteReaderMinWidth = 20
+)
+
+// noteReader is the paged overlay showing a single lore note.
+type noteReader struct {
+	note        lore.Note
+	page        int
+	fromJournal bool
+}
+
+// pickUpNote collects the note under the player, adds it to the journal and
+// opens the reader.
+func (g *Game) pickUpNote(cellX, cellY int) {
+	placed, ok := g.Floor.TakeNote(cellX, cellY)
+	if !ok {
+		return
+	}
+	note, ok := lore.Get(placed.NoteID)
+	if !ok {
+		return
+	}
+	g.addToJournal(note)
+	g.reader = &noteReader{note: note}
+}
+
+func (g *Game) addToJournal(note lore.Note) {
+	for _, n := range g.Journal {
+		if n.ID == note.ID {
+			return
+		}
+	}
+	g.Journal = append(g.Journal, note)
+}
+
+// handleNoteEvent routes keys to the reader or journal when either is open.
+func (g *Game) handleNoteEvent(ev *tcell.EventKey) bool {
+	if g.reader != nil {
+		g.handleReaderKey(ev)
+		return true
+	}
+	if g.journalOpen {
+		g.handleJournalKey(ev)
+		return true
+	}
+	return false
+}
+
+func (g *Game) handleReaderKey(ev *tcell.EventKey) {
+	r := g.reader
+	switch ev.Key() {
+	case tcell.KeyEscape, tcell.KeyEnter:
+		g.closeReader()
+		return
+	case tcell.KeyRight:
+		r.nextPage()
+		return
+	case tcell.KeyLeft:
+		r.prevPage()
+		return
+	case tcell.KeyRune:
+	default:
+		return
+	}
+
+	switch ev.Rune() {
+	case ' ', 'n', 'N', 'd', 'D':
+		r.nextPage()
+	case 'p', 'P', 'a', 'A':
+		r.prevPage()
+	case 'q', 'Q':
+		g.closeReader()
+	}
+}
+
+func (r *noteReader) nextPage() {
+	if r.page < len(r.note.Pages)-1 {
+		r.page++
+	}
+}
+
+func (r *noteReader) prevPage() {
+	if r.page > 0 {
+		r.page--
+	}
+}
+
+func (g *Game) closeReader() {
+	if g.reader != nil && g.reader.fromJournal {
+		g.journalOpen = true
+	}
+	g.reader = nil
+}
+
+func (g *Game) handleJournalKey(ev *tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		g.journalOpen = false
+		return
+	case tcell.KeyUp:
+		g.moveJournalSelection(-1)
+		return
+	case tcell.KeyDown:
+		g.moveJournalSelection(1)
+		return
+	case tcell.KeyEnter:
+		g.openJournalEntry()
+		return
+	case tcell.KeyRune:
+	default:
+		return
+	}
+
+	switch ev.Rune() {
+	case 'j', 'J', 'q', 'Q':
+		g.journalOpen = false
+	case 'w', 'W', 'k', 'K':
+		g.moveJournalSelection(-1)
+	case 's', 'S':
+		g.moveJournalSelection(1)
+	case ' ':
+		g.openJournalEntry()
+	}
+}
+
+func (g *Game) moveJournalSelection(delta int) {
+	if len(g.Journal) == 0 {
+		return
+	}
+	g.journalIndex = (g.journalIndex + delta + len(g.Journal)) % len(g.Journal)
+}
+
+func (g *Game) openJournalEntry() {
+	if g.journalIndex < 0 || g.journalIndex >= len(g.Journal) {
+		return
+	}
+	g.journalOpen = false
+	g.reader = &noteReader{note: g.Journal[g.journalIndex], fromJournal: true}
+}
+
+func (g *Game) openJournal() {
+	g.journalOpen = true
+	if g.journalIndex >= len(g.Journal) {
+		g.journalIndex = 0
+	}
+}
+
+// noteReaderWidth is the text width of the reader box for the current screen.
+func (g *Game) noteReaderWidth() int {
+	w := g.Width - 6
+	if w > noteReaderMaxWidth {
+		w = noteReaderMaxWidth
+	}
+	if w < noteReaderMinWidth {
+		w = noteReaderMinWidth
+	}
+	return w
+}
+
+// readerLines lays out the current page, garbled by the current corruption.
+func (g *Game) readerLines() []string {
+	r := g.reader
+	if r == nil || len(r.note.Pages) == 0 {
+		return nil
+	}
+	width := g.noteReaderWidth()
+	seed := lore.SeedFor(r.note.ID) ^ uint64(r.page)
+
+	lines := []string{lore.Garble(r.note.Title, g.Corruption, seed^0x7171E), ""}
+	text := lore.Garble(r.note.Pages[r.page], g.Corruption, seed)
+	lines = append(lines, wrapText(text, width)...)
+	lines = append(lines, "", fmt.Sprintf("Page %d/%d  <-/->: Page  Esc: Close", r.page+1, len(r.note.Pages)))
+	return lines
+}
+
+func (g *Game) journalLines() []string {
+	lines := []string{fmt.Sprintf("JOURNAL (%d found)", len(g.Journal)), ""}
+	if len(g.Journal) == 0 {
+		lines = append(lines, "Nothing yet. Notes you find are kept here.")
+	}
+	for i, n := range g.Journal {
+		marker := "  "
+		if i == g.journalIndex {
+			marker = "> "
+		}
+		lines = append(lines, marker+n.Title)
+	}
+	lines = append(lines, "", "W/S: Select  Enter: Read  J/Esc: Close")
+	return lines
+}
+
+// notesLines is the open reader or journal, or nil when neither is open.
+func (g *Game) notesLines() []string {
+	switch {
+	case g.reader != nil:
+		return g.readerLines()
+	case g.journalOpen:
+		return g.journalLines()
+	}
+	return nil
+}
+
+// wrapText breaks text into lines of at most width runes, splitting on spaces.
+func wrapText(text string, width int) []string {
+	if width <= 0 {
+		return nil
+	}
+	var lines []string
+	var cur []rune
+	for _, word := range strings.Fields(text) {
+		w := []rune(word)
+		for len(w) > width {
+			if len(cur) > 0 {
+				lines = append(lines, string(cur))
+				cur = nil
+			}
+			lines = append(lines, string(w[:width]))
+			w = w[width:]
+		}
+		switch {
+		case len(cur) == 0:
+			cur = append(cur, w...)
+		case len(cur)+1+len(w) <= width:
+			cur = append(cur, ' ')
+			cur = append(cur, w...)
+		default:
+			lines = append(lines, string(cur))
+			cur = append([]rune(nil), w...)
+		}
+	}
+	if len(cur) > 0 {
+		lines = append(lines, string(cur))
+	}
+	return lines
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"game/engine"
+	"game/lore"
+	"game/world"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// newTestGameWithNote returns a game whose current floor has a note, with the
+// player standing on the note's cell.
+func newTestGameWithNote(t *testing.T) (*Game, world.NotePlacement) {
+	t.Helper()
+
+	fm := world.NewFloorManagerWithSize(24, 24)
+	for seed := int64(1); seed <= 50; seed++ {
+		fm.Generator.WithSeed(seed)
+		floor := fm.TeleportToDepth(2)
+		if len(floor.Notes) == 0 {
+			continue
+		}
+		placed := floor.Notes[0]
+		g := &Game{
+			Running:      true,
+			Width:        80,
+			Height:       24,
+			CorruptState: world.NewCorruption(),
+			FloorManager: fm,
+			Floor:        floor,
+			GameMap:      floor.Map,
+			Player:       engine.NewPlayerAtCell(placed.Pos.X, placed.Pos.Y, 0),
+		}
+		return g, placed
+	}
+	t.Fatal("no floor with a note found")
+	return nil, world.NotePlacement{}
+}
+
+func key(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func TestWalkingOntoNoteOpensReaderAndJournal(t *testing.T) {
+	g, placed := newTestGameWithNote(t)
+
+	g.update()
+
+	if g.reader == nil || g.reader.note.ID != placed.NoteID {
+		t.Fatalf("expected reader open on %q, got %+v", placed.NoteID, g.reader)
+	}
+	if len(g.Journal) != 1 || g.Journal[0].ID != placed.NoteID {
+		t.Fatalf("expected note in journal, got %+v", g.Journal)
+	}
+	if g.GameMap.GetCell(placed.Pos.X, placed.Pos.Y) != engine.CellEmpty {
+		t.Fatal("expected note cell cleared after pickup")
+	}
+
+	// Movement keys page instead of moving while reading.
+	x, y := g.Player.X, g.Player.Y
+	g.processEvent(key('w'))
+	if g.Player.X != x || g.Player.Y != y {
+		t.Fatal("expected player frozen while reading")
+	}
+
+	g.processEvent(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	if g.reader != nil {
+		t.Fatal("expected reader closed")
+	}
+	if !g.Running {
+		t.Fatal("expected Esc in reader not to quit")
+	}
+}
+
+func TestNoteReaderPaging(t *testing.T) {
+	g := &Game{Width: 80, Height: 24}
+	note, _ := lore.Get("surveyor-1")
+	g.reader = &noteReader{note: note}
+
+	g.processEvent(key(' '))
+	if g.reader.page != 1 {
+		t.Fatalf("expected page 1, got %d", g.reader.page)
+	}
+	g.processEvent(key(' '))
+	if g.reader.page != len(note.Pages)-1 {
+		t.Fatalf("expected paging to stop at last page, got %d", g.reader.page)
+	}
+	g.processEvent(tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone))
+	if g.reader.page != 0 {
+		t.Fatalf("expected page 0, got %d", g.reader.page)
+	}
+
+	lines := g.readerLines()
+	if !strings.Contains(lines[len(lines)-1], "Page 1/2") {
+		t.Fatalf("unexpected footer %q", lines[len(lines)-1])
+	}
+}
+
+func TestJournalReopensCollectedNote(t *testing.T) {
+	g := &Game{Running: true, Width: 80, Height: 24}
+	first, _ := lore.Get("chalk")
+	second, _ := lore.Get("hymn")
+	g.addToJournal(first)
+	g.addToJournal(second)
+	g.addToJournal(first)
+	if len(g.Journal) != 2 {
+		t.Fatalf("expected duplicate notes ignored, got %d entries", len(g.Journal))
+	}
+
+	g.processEvent(key('j'))
+	if !g.journalOpen {
+		t.Fatal("expected journal open")
+	}
+	g.processEvent(key('s'))
+	g.processEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if g.reader == nil || g.reader.note.ID != "hymn" {
+		t.Fatalf("expected hymn open in reader, got %+v", g.reader)
+	}
+
+	g.processEvent(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	if !g.journalOpen {
+		t.Fatal("expected closing reader to return to journal")
+	}
+}
+
+func TestReaderGarblesWithCorruption(t *testing.T) {
+	note, _ := lore.Get("mirror")
+	clean := &Game{Width: 80, Height: 24, reader: &noteReader{note: note}}
+	corrupt := &Game{Width: 80, Height: 24, Corruption: 1.0, reader: &noteReader{note: note}}
+
+	if strings.Join(clean.readerLines(), "\n") == strings.Join(corrupt.readerLines(), "\n") {
+		t.Fatal("expected corruption to garble note text")
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	lines := wrapText("the deep calls to the deep", 10)
+	want := []string{"the deep", "calls to", "the deep"}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %v, got %v", want, lines)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, lines)
+		}
+	}
+	if got := wrapText("abcdefghijkl", 5); len(got) != 3 || got[2] != "kl" {
+		t.Fatalf("expected long words split, got %v", got)
+	}
+}
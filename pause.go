@@ -0,0 +1,260 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"game/menu"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// defaultSavePath holds the run left with Save & Quit, resumed by -continue.
+var defaultSavePath = filepath.Join(".", "runs", "save.replay")
+
+// Settings ranges for the pause menu.
+const (
+	settingsFOVStepDeg  = 5
+	settingsMinFOVDeg   = 40
+	settingsMaxFOVDeg   = 120
+	settingsDistStep    = 2
+	settingsMinDist     = 6
+	settingsMaxDist     = 32
+	pauseMenuFooterHelp = "W/S: Select  Enter: Choose  Esc: Back"
+)
+
+// colorMode is how the frame's colors reach the terminal.
+type colorMode int
+
+const (
+	colorFull colorMode = iota
+	// colorMono keeps glyphs and attributes but drops all colors.
+	colorMono
+	colorModeCount
+)
+
+func (m colorMode) String() string {
+	if m == colorMono {
+		return "mono"
+	}
+	return "color"
+}
+
+// applyColorMode rewrites the drawn frame for mode.
+func applyColorMode(screen tcell.Screen, width, height int, mode colorMode) {
+	if mode != colorMono {
+		return
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			ch, combc, style, _ := screen.GetContent(x, y)
+			_, _, attrs := style.Decompose()
+			screen.SetContent(x, y, ch, combc, tcell.StyleDefault.Attributes(attrs))
+		}
+	}
+}
+
+// openPause freezes the game and shows the pause menu.
+func (g *Game) openPause() {
+	settings := &menu.Menu{
+		Title:     "SETTINGS",
+		Footer:    "A/D: Change  Esc: Back",
+		LetterNav: true,
+		Items: []*menu.Item{
+			menu.Stepper("Field of view", g.fovSetting, g.adjustFOV),
+			menu.Stepper("Render distance", g.distanceSetting, g.adjustDistance),
+			menu.Stepper("Mini-map", g.miniMapSetting, g.adjustMiniMap),
+			menu.Stepper("Colors", func() string { return g.ColorMode.String() }, g.adjustColorMode),
+		},
+	}
+	g.pause = &menu.Menu{
+		Title:     "PAUSED",
+		Footer:    pauseMenuFooterHelp,
+		LetterNav: true,
+		Items: []*menu.Item{
+			menu.Action("Resume", g.closePause),
+			menu.Submenu("Settings", settings),
+			menu.Action("Save & Quit", g.saveAndQuit),
+			menu.Action("Quit without saving", func() { g.Running = false }),
+		},
+	}
+}
+
+func (g *Game) closePause() {
+	g.pause = nil
+}
+
+// handlePauseEvent opens the pause menu on Esc or q during live play and
+// drives it while open. Pausing only freezes time and changes settings, so
+// these keys never reach the replay recorder; the view settings that matter
+// to the simulation are logged by logViewSettings instead.
+func (g *Game) handlePauseEvent(ev tcell.Event) bool {
+	key, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return false
+	}
+	if g.pause == nil {
+		if g.reader != nil || g.journalOpen || g.cheatConsoleOpen {
+			return false
+		}
+		if key.Key() == tcell.KeyEscape || (key.Key() == tcell.KeyRune && (key.Rune() == 'q' || key.Rune() == 'Q')) {
+			g.openPause()
+			return true
+		}
+		return false
+	}
+
+	if g.pause.HandleKey(key) == menu.Closed {
+		g.closePause()
+	}
+	return true
+}
+
+// pauseLines is the open pause screen, or nil when not paused.
+func (g *Game) pauseLines() []string {
+	if g.pause == nil {
+		return nil
+	}
+	return g.pause.Lines()
+}
+
+// saveAndQuit writes the run's input log so -continue can rebuild it, then
+// ends the session. A save holds only input, so a speedrun's splits and
+// disqualification would not survive it; speedruns can't be saved.
+func (g *Game) saveAndQuit() {
+	if g.speedrun != nil {
+		g.pause.Message = "Speedruns can't be saved: the timer would not carry over"
+		return
+	}
+	if err := g.saveRun(defaultSavePath); err != nil {
+		g.pause.Message = fmt.Sprintf("Save failed: %v", err)
+		return
+	}
+	g.savedAndQuit = true
+	g.Running = false
+}
+
+func (g *Game) saveRun(path string) error {
+	if g.recorder == nil {
+		return errors.New("not recording")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	rp := g.recorder.finish(g.Frame)
+	rp.Elapsed = time.Since(g.Stats.Start)
+	return writeReplayFile(path, rp)
+}
+
+// readSave loads the saved run. A missing save is an error: there is nothing
+// to continue.
+func readSave(path string) (*Replay, error) {
+	rp, err := readReplayFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, errors.New("no saved run")
+	}
+	return rp, err
+}
+
+// removeSave deletes the save once its run has ended another way.
+func removeSave(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// restoreRun rebuilds a saved run by feeding its input log through g, which
+// must be fresh from NewGameWithSeed with the save's seed and floor size.
+// Events are recorded again, so the continued run's replay covers all of it.
+// The run's clock is set back by the save's wall time, so its duration counts
+// earlier sessions too.
+func (g *Game) restoreRun(rp *Replay) {
+	g.restoring = rp
+	defer func() { g.restoring = nil }()
+	g.Stats.backdate(rp.Elapsed)
+	next := 0
+	for g.Running {
+		for next < len(rp.Events) && rp.Events[next].Frame <= g.Frame {
+			g.processEvent(rp.Events[next].keyEvent())
+			next++
+		}
+		if g.Frame >= rp.Frames {
+			return
+		}
+		g.update()
+	}
+}
+
+// resimulating reports whether g is re-running recorded input, from a replay
+// or a save, rather than taking it live.
+func (g *Game) resimulating() bool {
+	return g.replay != nil || g.restoring != nil
+}
+
+func (g *Game) fovSetting() string {
+	return fmt.Sprintf("%.0f°", g.Raycaster.FOV*180/math.Pi)
+}
+
+func (g *Game) adjustFOV(delta int) {
+	deg := math.Round(g.Raycaster.FOV*180/math.Pi) + float64(delta*settingsFOVStepDeg)
+	deg = math.Max(settingsMinFOVDeg, math.Min(settingsMaxFOVDeg, deg))
+	g.Raycaster.FOV = deg * math.Pi / 180
+	g.logViewSettings()
+}
+
+func (g *Game) distanceSetting() string {
+	return fmt.Sprintf("%.0f", g.Raycaster.MaxDist)
+}
+
+func (g *Game) adjustDistance(delta int) {
+	d := g.Raycaster.MaxDist + float64(delta*settingsDistStep)
+	g.Raycaster.MaxDist = math.Max(settingsMinDist, math.Min(settingsMaxDist, d))
+	g.logViewSettings()
+}
+
+// logViewSettings records the FOV and render distance for replays. The live
+// settings are rounded to what the log holds first, so both sides agree.
+func (g *Game) logViewSettings() {
+	ev := viewEvent(g.Raycaster.FOV, g.Raycaster.MaxDist)
+	g.applyViewEvent(ev)
+	g.recorder.record(g.Frame, ev)
+}
+
+func (g *Game) applyViewEvent(ev *tcell.EventKey) {
+	if g.Raycaster != nil {
+		g.Raycaster.FOV, g.Raycaster.MaxDist = viewFromEvent(ev)
+	}
+}
+
+func (g *Game) miniMapSetting() string {
+	if !g.ShowMiniMap {
+		return "off"
+	}
+	return g.MiniMapMode.String()
+}
+
+// adjustMiniMap steps through off and each mini-map mode.
+func (g *Game) adjustMiniMap(delta int) {
+	// Position 0 is off, then one per mode.
+	pos := 0
+	if g.ShowMiniMap {
+		pos = int(g.MiniMapMode) + 1
+	}
+	n := int(miniMapModeCount) + 1
+	pos = (pos + delta + n) % n
+	g.ShowMiniMap = pos > 0
+	if pos > 0 {
+		g.MiniMapMode = miniMapMode(pos - 1)
+	}
+}
+
+func (g *Game) adjustColorMode(delta int) {
+	n := int(colorModeCount)
+	g.ColorMode = colorMode((int(g.ColorMode) + delta + n) % n)
+}
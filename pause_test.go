@@ -0,0 +1,295 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+var escKey = tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone)
+
+func runeKey(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func TestPauseFreezesUpdatesAndSkipsRecording(t *testing.T) {
+	g := newHeadlessGame(t, 16, 16, 11)
+	g.recorder = newReplayRecorder(11, 16, 16)
+	g.update()
+
+	g.events <- escKey
+	g.handleInput()
+	if g.pause == nil {
+		t.Fatal("expected Esc to pause")
+	}
+	if !g.Running {
+		t.Fatal("expected Esc to pause rather than quit")
+	}
+
+	frame, ticks := g.Frame, g.CorruptState.Ticks
+	for i := 0; i < 10; i++ {
+		g.update()
+	}
+	if g.Frame != frame || g.CorruptState.Ticks != ticks {
+		t.Fatalf("expected time frozen while paused: frame %d->%d, ticks %d->%d", frame, g.Frame, ticks, g.CorruptState.Ticks)
+	}
+
+	g.events <- runeKey('s')
+	g.events <- escKey
+	g.handleInput()
+	if g.pause != nil {
+		t.Fatal("expected Esc to resume")
+	}
+	if n := len(g.recorder.replay.Events); n != 0 {
+		t.Fatalf("expected pause keys kept out of the replay, got %d events", n)
+	}
+	g.update()
+	if g.Frame != frame+1 {
+		t.Fatalf("expected time to run again after resuming, got frame %d", g.Frame)
+	}
+}
+
+func TestPauseMenuOpensOnQButNotOverOtherMenus(t *testing.T) {
+	g := newTestGameForCheats(t)
+	if !g.handlePauseEvent(runeKey('q')) || g.pause == nil {
+		t.Fatal("expected q to pause")
+	}
+	g.closePause()
+
+	g.openCheatConsole()
+	if g.handlePauseEvent(escKey) {
+		t.Fatal("expected Esc to be left to the open cheat menu")
+	}
+}
+
+func TestPauseMenuQuitWithoutSaving(t *testing.T) {
+	g := newTestGameForCheats(t)
+	g.openPause()
+	for i := 0; i < 3; i++ {
+		g.handlePauseEvent(runeKey('s'))
+	}
+	g.handlePauseEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if g.Running || g.savedAndQuit {
+		t.Fatalf("expected quit without save, got running=%v saved=%v", g.Running, g.savedAndQuit)
+	}
+}
+
+func TestPauseSaveAndQuitRefusesSpeedruns(t *testing.T) {
+	defer func(path string) { defaultSavePath = path }(defaultSavePath)
+	defaultSavePath = filepath.Join(t.TempDir(), "save.replay")
+
+	g := newTestGameForCheats(t)
+	g.recorder = newReplayRecorder(1, 16, 16)
+	g.speedrun = newSpeedrun(3, 16, 16, nil, g.Frame)
+	g.openPause()
+	for i := 0; i < 2; i++ {
+		g.handlePauseEvent(runeKey('s'))
+	}
+	g.handlePauseEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if !g.Running || g.savedAndQuit || !strings.HasPrefix(g.pause.Message, "Speedruns can't be saved") {
+		t.Fatalf("expected the save refused, got running=%v saved=%v message %q", g.Running, g.savedAndQuit, g.pause.Message)
+	}
+	if _, err := os.Stat(defaultSavePath); !os.IsNotExist(err) {
+		t.Fatalf("expected no save file, got %v", err)
+	}
+}
+
+func TestPauseSettingsEditLive(t *testing.T) {
+	g := newHeadlessGame(t, 16, 16, 3)
+	g.openPause()
+	g.handlePauseEvent(runeKey('s'))
+	g.handlePauseEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if g.pause.Depth() != 1 {
+		t.Fatal("expected Settings to open the settings screen")
+	}
+
+	// FOV: 60° up one step, then clamped at the top.
+	g.handlePauseEvent(runeKey('d'))
+	if got := g.Raycaster.FOV * 180 / math.Pi; math.Abs(got-65) > 1e-9 {
+		t.Fatalf("expected FOV 65°, got %.2f", got)
+	}
+	for i := 0; i < 20; i++ {
+		g.handlePauseEvent(runeKey('d'))
+	}
+	if got := g.Raycaster.FOV * 180 / math.Pi; math.Abs(got-settingsMaxFOVDeg) > 1e-9 {
+		t.Fatalf("expected FOV clamped to %d°, got %.2f", settingsMaxFOVDeg, got)
+	}
+
+	g.handlePauseEvent(runeKey('s'))
+	g.handlePauseEvent(runeKey('a'))
+	if g.Raycaster.MaxDist != 14 {
+		t.Fatalf("expected render distance 14, got %.0f", g.Raycaster.MaxDist)
+	}
+
+	// Mini-map: north-up -> heading-up -> braille -> off -> north-up.
+	g.handlePauseEvent(runeKey('s'))
+	for _, want := range []string{"heading-up", "braille", "off", "north-up"} {
+		g.handlePauseEvent(runeKey('d'))
+		if got := g.miniMapSetting(); got != want {
+			t.Fatalf("expected mini-map %s, got %s", want, got)
+		}
+	}
+
+	g.handlePauseEvent(runeKey('s'))
+	g.handlePauseEvent(runeKey('d'))
+	if g.ColorMode != colorMono {
+		t.Fatalf("expected mono colors, got %s", g.ColorMode)
+	}
+
+	g.handlePauseEvent(escKey)
+	if g.pause == nil || g.pause.Depth() != 0 {
+		t.Fatal("expected Esc to go back from settings to the pause menu")
+	}
+}
+
+func TestApplyColorModeMonoDropsColors(t *testing.T) {
+	screen := newLayoutTestScreen(t, 2, 1)
+	screen.SetContent(0, 0, '#', nil, tcell.StyleDefault.Foreground(tcell.ColorRed).Reverse(true))
+	screen.SetContent(1, 0, '.', nil, tcell.StyleDefault.Background(tcell.ColorBlue))
+
+	applyColorMode(screen, 2, 1, colorMono)
+
+	ch, _, style, _ := screen.GetContent(0, 0)
+	if ch != '#' || style != tcell.StyleDefault.Reverse(true) {
+		t.Fatalf("expected '#' kept with reverse only, got %q %v", ch, style)
+	}
+	if _, _, style, _ := screen.GetContent(1, 0); style != tcell.StyleDefault {
+		t.Fatalf("expected background color dropped, got %v", style)
+	}
+}
+
+func TestSaveAndContinueRebuildsRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.replay")
+
+	g := newHeadlessGame(t, 16, 16, 99)
+	g.recorder = newReplayRecorder(99, 16, 16)
+	for i, r := range []rune{'w', 'd', 'w', 'w', 'a', 'w', 's'} {
+		g.processEvent(runeKey(r))
+		for j := 0; j <= i; j++ {
+			g.update()
+		}
+	}
+	g.openPause()
+	// Pretend the run had been going for ten minutes.
+	g.Stats.backdate(10 * time.Minute)
+	if err := g.saveRun(path); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	save, err := readSave(path)
+	if err != nil {
+		t.Fatalf("read save: %v", err)
+	}
+	if save.Elapsed < 10*time.Minute {
+		t.Fatalf("expected the save to hold the wall time played, got %s", save.Elapsed)
+	}
+	c := newHeadlessGame(t, save.FloorW, save.FloorH, save.Seed)
+	c.recorder = newReplayRecorder(save.Seed, save.FloorW, save.FloorH)
+	c.restoreRun(save)
+	c.finishRun(time.Now())
+	if c.Stats.Duration() < save.Elapsed || c.Stats.Floors[0].Elapsed < save.Elapsed {
+		t.Fatalf("expected the continued run and floor to count the earlier %s, got %s and %s", save.Elapsed, c.Stats.Duration(), c.Stats.Floors[0].Elapsed)
+	}
+
+	if c.Frame != g.Frame || c.Floor.Depth != g.Floor.Depth {
+		t.Fatalf("restored frame/depth %d/%d, want %d/%d", c.Frame, c.Floor.Depth, g.Frame, g.Floor.Depth)
+	}
+	if c.Player.X != g.Player.X || c.Player.Y != g.Player.Y || c.Player.Angle != g.Player.Angle {
+		t.Fatalf("restored player %+v, want %+v", *c.Player, *g.Player)
+	}
+	if c.Corruption != g.Corruption {
+		t.Fatalf("restored corruption %v, want %v", c.Corruption, g.Corruption)
+	}
+	if len(c.recorder.replay.Events) != len(save.Events) {
+		t.Fatalf("expected the continued run to re-record %d events, got %d", len(save.Events), len(c.recorder.replay.Events))
+	}
+
+	if err := removeSave(path); err != nil {
+		t.Fatalf("remove save: %v", err)
+	}
+	if _, err := readSave(path); err == nil {
+		t.Fatal("expected no save after removing it")
+	}
+	if err := removeSave(path); err != nil {
+		t.Fatalf("expected removing a missing save to succeed, got %v", err)
+	}
+}
+
+func TestContinueSkipsConsoleCommandsThatWriteFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "floor.txt")
+
+	g := newHeadlessGame(t, 16, 16, 99)
+	g.recorder = newReplayRecorder(99, 16, 16)
+	g.processEvent(runeKey('c'))
+	for _, r := range "dump " + path {
+		g.processEvent(runeKey(r))
+	}
+	g.processEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	g.update()
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("expected the live dump to write %s: %v", path, err)
+	}
+
+	c := newHeadlessGame(t, 16, 16, 99)
+	c.restoreRun(g.recorder.finish(g.Frame))
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected restoring not to dump again, got %v", err)
+	}
+	if got := c.cheatConsole.Output[len(c.cheatConsole.Output)-1]; got != "Skipped while replaying: dump" {
+		t.Fatalf("unexpected console output %q", got)
+	}
+
+	// Once restored, the session is live again.
+	cheat(t, c, "dump "+path)
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected a live dump after restoring: %v", err)
+	}
+}
+
+func TestPauseViewSettingsShieldWallsAndReplay(t *testing.T) {
+	g := newHeadlessGame(t, 16, 16, 7)
+	g.recorder = newReplayRecorder(7, 16, 16)
+	g.processEvent(runeKey('c'))
+	for _, r := range "corrupt 1" {
+		g.processEvent(runeKey(r))
+	}
+	g.processEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	g.processEvent(escKey)
+
+	g.openPause()
+	g.adjustFOV(20)
+	g.adjustDistance(20)
+	g.closePause()
+
+	view := g.Raycaster.ViewCells(g.viewer(), g.GameMap)
+	before := dumpMap(g.GameMap, nil)
+	for i := 0; i < 600; i++ {
+		g.update()
+	}
+	if g.Floor.Shifter.Shifts == 0 {
+		t.Fatal("expected walls to shift at full corruption")
+	}
+	after := dumpMap(g.GameMap, nil)
+	rowsBefore, rowsAfter := strings.Split(before, "\n"), strings.Split(after, "\n")
+	for y := 0; y < g.GameMap.Height; y++ {
+		for x := 0; x < g.GameMap.Width; x++ {
+			if view.Contains(x, y) && []rune(rowsBefore[y])[x] != []rune(rowsAfter[y])[x] {
+				t.Fatalf("cell (%d,%d) shifted inside the %.0f° view", x, y, g.Raycaster.FOV*180/math.Pi)
+			}
+		}
+	}
+
+	c := newHeadlessGame(t, 16, 16, 7)
+	c.restoreRun(g.recorder.finish(g.Frame))
+	if c.Raycaster.FOV != g.Raycaster.FOV || c.Raycaster.MaxDist != g.Raycaster.MaxDist {
+		t.Fatalf("restored view %v/%v, want %v/%v", c.Raycaster.FOV, c.Raycaster.MaxDist, g.Raycaster.FOV, g.Raycaster.MaxDist)
+	}
+	if dumpMap(c.GameMap, nil) != after {
+		t.Fatal("expected the restored run to shift the same walls")
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// perfStats holds timings and counters for the most recently completed frame.
+type perfStats struct {
+	Frame  time.Duration
+	Update time.Duration
+	Render time.Duration
+	Rays   int
+	Cells  int
+}
+
+func (p perfStats) lines() []string {
+	fps := 0.0
+	if p.Frame > 0 {
+		fps = float64(time.Second) / float64(p.Frame)
+	}
+	return []string{
+		fmt.Sprintf(" frame  %6.2fms (%3.0f fps) ", durationMs(p.Frame), fps),
+		fmt.Sprintf(" update %6.2fms ", durationMs(p.Update)),
+		fmt.Sprintf(" render %6.2fms ", durationMs(p.Render)),
+		fmt.Sprintf(" rays   %6d ", p.Rays),
+		fmt.Sprintf(" cells  %6d ", p.Cells),
+	}
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// perfPanel is the perf overlay, stacked in the top-left corner.
+func (g *Game) perfPanel() hudPanel {
+	style := tcell.StyleDefault.Foreground(tcell.ColorLime).Background(tcell.ColorBlack)
+	return linesPanel("perf", anchorTopLeft, hudZOverlay, func(rune) tcell.Style { return style }, g.perf.lines())
+}
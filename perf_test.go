@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPerfStatsLines(t *testing.T) {
+	p := perfStats{
+		Frame:  16 * time.Millisecond,
+		Update: 250 * time.Microsecond,
+		Render: 3 * time.Millisecond,
+		Rays:   120,
+		Cells:  4800,
+	}
+
+	lines := p.lines()
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 lines, got %d", len(lines))
+	}
+	if !strings.Contains(lines[0], "16.00ms") || !strings.Contains(lines[0], "62 fps") {
+		t.Fatalf("unexpected frame line %q", lines[0])
+	}
+	if !strings.Contains(lines[3], "120") {
+		t.Fatalf("expected ray count in %q", lines[3])
+	}
+	if !strings.Contains(lines[4], "4800") {
+		t.Fatalf("expected cell count in %q", lines[4])
+	}
+}
+
+func TestCheatConsoleTogglesPerfOverlay(t *testing.T) {
+	g := newTestGameForCheats(t)
+
+	cheat(t, g, "perf")
+	if !g.ShowPerf {
+		t.Fatal("expected perf overlay toggled on")
+	}
+}
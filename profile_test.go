@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStartProfilingWritesFiles(t *testing.T) {
+	dir := t.TempDir()
+	cpuPath := filepath.Join(dir, "cpu.pprof")
+	tracePath := filepath.Join(dir, "trace.out")
+
+	stop, err := startProfiling(cpuPath, tracePath)
+	if err != nil {
+		t.Fatalf("start profiling: %v", err)
+	}
+	stop()
+
+	for _, path := range []string{cpuPath, tracePath} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("stat %s: %v", path, err)
+		}
+		if info.Size() == 0 {
+			t.Fatalf("expected %s to be non-empty", path)
+		}
+	}
+}
+
+func TestStartProfilingNoopWithoutPaths(t *testing.T) {
+	stop, err := startProfiling("", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stop()
+}
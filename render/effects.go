@@ -3,23 +3,14 @@ package render
 import (
 	"time"
 
+	"game/audio"
+	"game/theme"
+
 	"github.com/gdamore/tcell/v2"
 )
 
-// Randomly replace wall chars with glitchy alternatives.
-var glitchChars = []rune{'╳', '◊', '∆', '¤', '§', '░'}
-
-// ANSI color shifts - walls occasionally wrong color.
-var corruptColors = []tcell.Color{tcell.ColorRed, tcell.ColorFuchsia, tcell.ColorDarkRed}
-
-// Lovecraftian text fragments appearing on screen.
-var whispers = []string{
-	"ph'nglui mglw'nafh",
-	"the deep calls",
-	"THEY SEE YOU",
-	"do not look back",
-	"the angles are wrong",
-}
+// Glitch glyphs, corruption colors and whisper text come from theme packs
+// (see package theme); EffectsContext.Theme selects the resolved tables.
 
 const (
 	maxCharGlitchChance  = 0.10
@@ -36,6 +27,45 @@ type EffectsContext struct {
 	Depth      int
 	Ticks      int
 	Seed       uint64
+
+	// Theme holds the glyph/color/whisper tables for this floor. Nil uses the
+	// embedded default pack resolved at Depth.
+	Theme *theme.Tables
+	// WatchersSeen is the run's watcher sighting count, for whisper text.
+	WatchersSeen int
+
+	// Shading is the wall table glitches apply to; nil means ShadeChars.
+	Shading *Shading
+	// Scale multiplies effect intensities; nil means unscaled.
+	Scale *EffectScale
+
+	// Audio receives a WhisperShown event once per whisper; nil is silent.
+	Audio *audio.Bus
+}
+
+// EffectScale multiplies the strength of each corruption effect.
+type EffectScale struct {
+	Glitch  float64
+	Bleed   float64
+	Whisper float64
+	FakeGeo float64
+}
+
+// DefaultEffectScale leaves every effect at its base strength.
+var DefaultEffectScale = EffectScale{Glitch: 1, Bleed: 1, Whisper: 1, FakeGeo: 1}
+
+func (ctx EffectsContext) scale() EffectScale {
+	if ctx.Scale == nil {
+		return DefaultEffectScale
+	}
+	return *ctx.Scale
+}
+
+func (ctx EffectsContext) tables() *theme.Tables {
+	if ctx.Theme != nil {
+		return ctx.Theme
+	}
+	return theme.DefaultTables(ctx.Depth)
 }
 
 func NewEffectsContext(depth int, corruption float64, ticks int) EffectsContext {
@@ -61,16 +91,19 @@ func ApplyCharGlitch(char rune, corruption float64) rune {
 }
 
 func ApplyCharGlitchAt(char rune, ctx EffectsContext, x, y int) rune {
-	if ctx.Corruption <= 0 || !isWallShadeChar(char) {
+	if ctx.Corruption <= 0 || !ctx.Shading.IsWall(char) {
 		return char
 	}
 
-	p := clamp01(ctx.Corruption) * maxCharGlitchChance
+	p := clamp01(ctx.Corruption) * maxCharGlitchChance * ctx.scale().Glitch
 	if !chance01(cellNoise(ctx, x, y, 0xA11CE), p) {
 		return char
 	}
 
-	return glitchChars[pickIndex(cellNoise(ctx, x, y, 0xC0FFEE), len(glitchChars))]
+	if g, ok := theme.Pick(ctx.tables().GlitchChars, cellNoise(ctx, x, y, 0xC0FFEE)); ok {
+		return g
+	}
+	return char
 }
 
 // ApplyColorBleed occasionally shifts the wall color.
@@ -91,30 +124,24 @@ func ApplyColorBleedAt(style tcell.Style, ctx EffectsContext, x, y int) tcell.St
 		return style
 	}
 
-	p := clamp01(ctx.Corruption) * maxColorBleedChance
+	p := clamp01(ctx.Corruption) * maxColorBleedChance * ctx.scale().Bleed
 	if !chance01(cellNoise(ctx, x, y, 0xB1EED), p) {
 		return style
 	}
 
-	return style.Foreground(corruptColors[pickIndex(cellNoise(ctx, x, y, 0xD15EA5E), len(corruptColors))])
-}
-
-func RenderWhisper(screen tcell.Screen, corruption float64) {
-	if screen == nil {
-		return
+	if c, ok := theme.Pick(ctx.tables().CorruptColors, cellNoise(ctx, x, y, 0xD15EA5E)); ok {
+		return style.Foreground(c)
 	}
-	w, h := screen.Size()
-	RenderWhisperAt(screen, EffectsContext{
-		Corruption: corruption,
-		Seed:       uint64(time.Now().UnixNano()),
-		Ticks:      int(time.Now().UnixNano()),
-	}, w, h)
+	return style
 }
 
-func RenderWhisperAt(screen tcell.Screen, ctx EffectsContext, width, height int) {
+// RenderWhisperAt may show a whisper somewhere in the width x height area at
+// (originX, originY), normally the 3D viewport.
+func RenderWhisperAt(screen tcell.Screen, ctx EffectsContext, originX, originY, width, height int) {
 	if screen == nil || width <= 0 || height <= 0 {
 		return
 	}
+	whispers := ctx.tables().Whispers
 	if ctx.Corruption < whisperStartLevel || len(whispers) == 0 {
 		return
 	}
@@ -125,20 +152,21 @@ func RenderWhisperAt(screen tcell.Screen, ctx EffectsContext, width, height int)
 		window = ctx.Ticks / whisperWindowTicks
 	}
 
-	if !chance01(mix64(ctx.Seed^uint64(window)^0x51A57E), intensity*maxWhisperPerWindow) {
+	if !chance01(mix64(ctx.Seed^uint64(window)^0x51A57E), intensity*maxWhisperPerWindow*ctx.scale().Whisper) {
 		return
 	}
 
-	msg := whispers[pickIndex(mix64(ctx.Seed^uint64(window)^0x57EAD), len(whispers))]
+	msg, _ := theme.Pick(whispers, mix64(ctx.Seed^uint64(window)^0x57EAD))
+	msg = theme.Expand(msg, theme.RunState{
+		Depth:        ctx.Depth,
+		WatchersSeen: ctx.WatchersSeen,
+		Corruption:   ctx.Corruption,
+	})
 	if len(msg) == 0 {
 		return
 	}
 
-	usableHeight := height - 3
-	if usableHeight <= 0 {
-		return
-	}
-	y := 1 + pickIndex(mix64(ctx.Seed^uint64(window)^0x900D), usableHeight)
+	y := originY + pickIndex(mix64(ctx.Seed^uint64(window)^0x900D), height)
 	maxX := width - len([]rune(msg))
 	if maxX < 0 {
 		maxX = 0
@@ -150,23 +178,15 @@ func RenderWhisperAt(screen tcell.Screen, ctx EffectsContext, width, height int)
 		if x+i >= width {
 			break
 		}
-		screen.SetContent(x+i, y, r, nil, style)
+		screen.SetContent(originX+x+i, y, r, nil, style)
 	}
+	// A whisper stays up for its whole window; sound it once.
+	ctx.Audio.EmitOnce(audio.WhisperShown, window)
 }
 
-func ApplyFakeGeometry(screen tcell.Screen, corruption float64) {
-	if screen == nil {
-		return
-	}
-	w, h := screen.Size()
-	ApplyFakeGeometryAt(screen, EffectsContext{
-		Corruption: corruption,
-		Seed:       uint64(time.Now().UnixNano()),
-		Ticks:      int(time.Now().UnixNano()),
-	}, w, h)
-}
-
-func ApplyFakeGeometryAt(screen tcell.Screen, ctx EffectsContext, width, height int) {
+// ApplyFakeGeometryAt scatters phantom wall cells over the width x height
+// area at (originX, originY), normally the 3D viewport.
+func ApplyFakeGeometryAt(screen tcell.Screen, ctx EffectsContext, originX, originY, width, height int) {
 	if screen == nil || width <= 0 || height <= 0 {
 		return
 	}
@@ -175,7 +195,7 @@ func ApplyFakeGeometryAt(screen tcell.Screen, ctx EffectsContext, width, height
 	}
 
 	intensity := clamp01((ctx.Corruption - fakeGeoStartLevel) / (1.0 - fakeGeoStartLevel))
-	count := int(intensity * maxFakeGeometryCells)
+	count := int(intensity * maxFakeGeometryCells * ctx.scale().FakeGeo)
 	if count < 1 {
 		count = 1
 	}
@@ -187,23 +207,8 @@ func ApplyFakeGeometryAt(screen tcell.Screen, ctx EffectsContext, width, height
 		n := mix64(ctx.Seed ^ uint64(ctx.Ticks) ^ uint64(i)*0x9E3779B97F4A7C15)
 		x := pickIndex(n^0x1234, width)
 		y := pickIndex(n^0xBEEF, height)
-
-		// Avoid painting over HUD areas by staying away from the very top/bottom rows.
-		if y <= 1 || y >= height-2 {
-			continue
-		}
-
-		screen.SetContent(x, y, char, nil, style)
-	}
-}
-
-func isWallShadeChar(r rune) bool {
-	for _, c := range ShadeChars {
-		if r == c {
-			return true
-		}
+		screen.SetContent(originX+x, originY+y, char, nil, style)
 	}
-	return false
 }
 
 func clamp01(v float64) float64 {
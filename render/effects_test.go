@@ -1,8 +1,12 @@
 package render
 
 import (
+	"strings"
 	"testing"
 
+	"game/audio"
+	"game/theme"
+
 	"github.com/gdamore/tcell/v2"
 )
 
@@ -50,3 +54,173 @@ func TestApplyColorBleedAtDeterministicPerFrameAndPosition(t *testing.T) {
 		t.Fatalf("expected deterministic output, got fg %v vs %v", fga, fgb)
 	}
 }
+
+func TestEffectsUseContextTheme(t *testing.T) {
+	tables := &theme.Tables{
+		Whispers:      []theme.Weighted[string]{{Value: "{watchers} saw floor {depth}", Weight: 1}},
+		GlitchChars:   []theme.Weighted[rune]{{Value: 'Z', Weight: 1}},
+		CorruptColors: []theme.Weighted[tcell.Color]{{Value: tcell.ColorLime, Weight: 1}},
+	}
+	style := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+
+	glitched, bled := false, false
+	for tick := 0; tick < 200 && !(glitched && bled); tick++ {
+		ctx := NewEffectsContext(5, 1.0, tick)
+		ctx.Theme = tables
+		for x := 0; x < 40; x++ {
+			if got := ApplyCharGlitchAt(ShadeChars[0], ctx, x, 3); got != ShadeChars[0] {
+				if got != 'Z' {
+					t.Fatalf("expected themed glitch char, got %q", got)
+				}
+				glitched = true
+			}
+			if fg, _, _ := ApplyColorBleedAt(style, ctx, x, 3).Decompose(); fg != tcell.ColorWhite {
+				if fg != tcell.ColorLime {
+					t.Fatalf("expected themed color, got %v", fg)
+				}
+				bled = true
+			}
+		}
+	}
+	if !glitched || !bled {
+		t.Fatalf("expected themed effects to trigger (glitch=%v bleed=%v)", glitched, bled)
+	}
+
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("init screen: %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(60, 20)
+	for tick := 0; tick < 100000; tick += whisperWindowTicks {
+		screen.Clear()
+		ctx := NewEffectsContext(5, 1.0, tick)
+		ctx.Theme = tables
+		ctx.WatchersSeen = 7
+		RenderWhisperAt(screen, ctx, 0, 0, 60, 20)
+		screen.Show()
+		cells, w, _ := screen.GetContents()
+		var sb strings.Builder
+		for _, c := range cells {
+			if len(c.Runes) > 0 {
+				sb.WriteRune(c.Runes[0])
+			} else {
+				sb.WriteRune(' ')
+			}
+		}
+		if text := sb.String(); strings.TrimSpace(text) != "" {
+			if !strings.Contains(text, "7 saw floor 5") {
+				t.Fatalf("expected expanded whisper, got %q (width %d)", strings.TrimSpace(text), w)
+			}
+			return
+		}
+	}
+	t.Fatal("expected a whisper to appear")
+}
+
+func TestRenderWhisperEmitsOncePerShowing(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("init screen: %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(60, 20)
+
+	rec := &audio.Recorder{}
+	bus := audio.NewBus(rec)
+	shownWindows := 0
+	// At depth 30 and full corruption, a whisper shows in some but not all
+	// of the first dozen windows.
+	for window := 0; window < 12; window++ {
+		shown := false
+		for i := 0; i < whisperWindowTicks; i++ {
+			screen.Clear()
+			ctx := NewEffectsContext(30, 1.0, window*whisperWindowTicks+i)
+			ctx.Audio = bus
+			RenderWhisperAt(screen, ctx, 0, 0, 60, 20)
+			screen.Show()
+			cells, _, _ := screen.GetContents()
+			for _, c := range cells {
+				if len(c.Runes) > 0 && c.Runes[0] != ' ' {
+					shown = true
+				}
+			}
+		}
+		if shown {
+			shownWindows++
+		}
+	}
+	if shownWindows == 0 || shownWindows == 12 {
+		t.Fatalf("expected whispers in some windows, got %d of 12", shownWindows)
+	}
+	if got := rec.Count(audio.WhisperShown); got != shownWindows {
+		t.Fatalf("expected one event per whisper window (%d), got %d", shownWindows, got)
+	}
+}
+
+func TestEffectScaleChangesFrequency(t *testing.T) {
+	count := func(scale *EffectScale) int {
+		n := 0
+		for tick := 0; tick < 20; tick++ {
+			ctx := NewEffectsContext(20, 1.0, tick)
+			ctx.Scale = scale
+			for x := 0; x < 100; x++ {
+				if ApplyCharGlitchAt(ShadeChars[0], ctx, x, 4) != ShadeChars[0] {
+					n++
+				}
+			}
+		}
+		return n
+	}
+
+	base := count(nil)
+	if off := count(&EffectScale{}); off != 0 {
+		t.Fatalf("expected zero scale to disable glitches, got %d", off)
+	}
+	if doubled := count(&EffectScale{Glitch: 2}); doubled <= base {
+		t.Fatalf("expected doubled scale to glitch more (base %d, doubled %d)", base, doubled)
+	}
+}
+
+func BenchmarkApplyCharGlitchAt(b *testing.B) {
+	ctx := NewEffectsContext(30, 1.0, 42)
+	in := ShadeChars[0]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ApplyCharGlitchAt(in, ctx, i%400, i%120)
+	}
+}
+
+func TestOverlaysStayInsideTheirArea(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("init screen: %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(60, 20)
+
+	// The area a HUD would leave: rows 2-16, columns 4-43.
+	const ox, oy, w, h = 4, 2, 40, 15
+	drawn := 0
+	for tick := 0; tick < 50*whisperWindowTicks; tick += whisperWindowTicks {
+		screen.Clear()
+		ctx := NewEffectsContext(5, 1.0, tick)
+		RenderWhisperAt(screen, ctx, ox, oy, w, h)
+		ApplyFakeGeometryAt(screen, ctx, ox, oy, w, h)
+		screen.Show()
+		cells, sw, _ := screen.GetContents()
+		for i, c := range cells {
+			if len(c.Runes) == 0 || c.Runes[0] == ' ' {
+				continue
+			}
+			drawn++
+			if x, y := i%sw, i/sw; x < ox || x >= ox+w || y < oy || y >= oy+h {
+				t.Fatalf("tick %d: %q drawn at (%d,%d), outside the area", tick, c.Runes[0], x, y)
+			}
+		}
+	}
+	if drawn == 0 {
+		t.Fatal("expected overlays to draw something")
+	}
+}
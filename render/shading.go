@@ -1,5 +1,7 @@
 package render
 
+import "github.com/gdamore/tcell/v2"
+
 // ShadeChars maps distance to ASCII characters for wall rendering
 // Index 0 is closest (brightest), higher indices are farther (dimmer)
 var ShadeChars = []rune{'█', '▓', '▒', '░', '.', ' '}
@@ -7,20 +9,24 @@ var ShadeChars = []rune{'█', '▓', '▒', '░', '.', ' '}
 // GetShade returns the appropriate shading character for a given distance
 // maxDist is the maximum render distance
 func GetShade(distance, maxDist float64) rune {
+	return shadeFrom(ShadeChars, distance, maxDist)
+}
+
+func shadeFrom(chars []rune, distance, maxDist float64) rune {
 	if distance <= 0 {
-		return ShadeChars[0]
+		return chars[0]
 	}
 	if distance >= maxDist {
-		return ShadeChars[len(ShadeChars)-1]
+		return chars[len(chars)-1]
 	}
 
 	// Map distance to shade index
 	ratio := distance / maxDist
-	index := int(ratio * float64(len(ShadeChars)-1))
-	if index >= len(ShadeChars) {
-		index = len(ShadeChars) - 1
+	index := int(ratio * float64(len(chars)-1))
+	if index >= len(chars) {
+		index = len(chars) - 1
 	}
-	return ShadeChars[index]
+	return chars[index]
 }
 
 // CeilingChar is the character used for ceiling
@@ -29,21 +35,82 @@ const CeilingChar = ' '
 // StairsChar is the character used to indicate stairs in overlays/sprites.
 const StairsChar = 'v'
 
+// NoteChar is the character used to indicate a lore note in overlays/sprites.
+const NoteChar = '?'
+
+// PortalChar marks a portal cell on overlays such as the mini-map.
+const PortalChar = 'O'
+
 // FloorChars are characters for floor rendering (closer = denser)
 var FloorChars = []rune{'.', ':', ';', ' '}
 
 // GetFloorShade returns floor shading based on row distance from center
 func GetFloorShade(rowFromCenter, halfHeight int) rune {
+	return floorShadeFrom(FloorChars, rowFromCenter, halfHeight)
+}
+
+func floorShadeFrom(chars []rune, rowFromCenter, halfHeight int) rune {
 	if halfHeight <= 0 {
-		return FloorChars[0]
+		return chars[0]
 	}
 	ratio := float64(rowFromCenter) / float64(halfHeight)
-	index := int(ratio * float64(len(FloorChars)-1))
+	index := int(ratio * float64(len(chars)-1))
 	if index < 0 {
 		index = 0
 	}
-	if index >= len(FloorChars) {
-		index = len(FloorChars) - 1
+	if index >= len(chars) {
+		index = len(chars) - 1
+	}
+	return chars[index]
+}
+
+// Shading is a set of wall and floor shade tables, nearest first. Empty
+// tables fall back to ShadeChars/FloorChars.
+type Shading struct {
+	Walls  []rune
+	Floors []rune
+}
+
+// Wall returns the wall character for distance using this table.
+func (s *Shading) Wall(distance, maxDist float64) rune {
+	if s == nil || len(s.Walls) == 0 {
+		return GetShade(distance, maxDist)
 	}
-	return FloorChars[index]
+	return shadeFrom(s.Walls, distance, maxDist)
+}
+
+// Floor returns the floor character for a row using this table.
+func (s *Shading) Floor(rowFromCenter, halfHeight int) rune {
+	if s == nil || len(s.Floors) == 0 {
+		return GetFloorShade(rowFromCenter, halfHeight)
+	}
+	return floorShadeFrom(s.Floors, rowFromCenter, halfHeight)
+}
+
+// IsWall reports whether r is one of this table's wall characters.
+func (s *Shading) IsWall(r rune) bool {
+	walls := ShadeChars
+	if s != nil && len(s.Walls) > 0 {
+		walls = s.Walls
+	}
+	for _, c := range walls {
+		if r == c {
+			return true
+		}
+	}
+	return false
+}
+
+// Palette colors the base layers of the 3D view.
+type Palette struct {
+	Wall    tcell.Color
+	Ceiling tcell.Color
+	Floor   tcell.Color
+}
+
+// DefaultPalette is the palette used when no biome overrides it.
+var DefaultPalette = Palette{
+	Wall:    tcell.ColorWhite,
+	Ceiling: tcell.ColorDarkBlue,
+	Floor:   tcell.ColorDarkGray,
 }
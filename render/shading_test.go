@@ -57,3 +57,35 @@ func TestShadeCharsOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestShadingFallsBackToDefaults(t *testing.T) {
+	var nilShading *Shading
+	empty := &Shading{}
+	for _, s := range []*Shading{nilShading, empty} {
+		if got := s.Wall(0.1, 16); got != GetShade(0.1, 16) {
+			t.Errorf("expected default wall shade, got %c", got)
+		}
+		if got := s.Floor(3, 20); got != GetFloorShade(3, 20) {
+			t.Errorf("expected default floor shade, got %c", got)
+		}
+		if !s.IsWall(ShadeChars[0]) {
+			t.Error("expected default wall chars to count as walls")
+		}
+	}
+}
+
+func TestShadingCustomTables(t *testing.T) {
+	s := &Shading{Walls: []rune{'#', '=', ' '}, Floors: []rune{'~', ' '}}
+	if got := s.Wall(0, 16); got != '#' {
+		t.Errorf("expected nearest custom wall char, got %c", got)
+	}
+	if got := s.Wall(16, 16); got != ' ' {
+		t.Errorf("expected farthest custom wall char, got %c", got)
+	}
+	if got := s.Floor(0, 20); got != '~' {
+		t.Errorf("expected custom floor char, got %c", got)
+	}
+	if !s.IsWall('=') || s.IsWall(ShadeChars[0]) {
+		t.Error("expected IsWall to use the custom table only")
+	}
+}
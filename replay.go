@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// replayMagic identifies replay files; the byte after it is the format version.
+// Version 2 adds the wall time a saved run had been played. Replays without one
+// are still written as version 1, so their bytes (and daily checks) don't
+// change.
+const (
+	replayMagic          = "ABYR"
+	replayVersion        = 1
+	replayVersionElapsed = 2
+)
+
+// Limits on decoded replays, so a hostile file can't exhaust memory or keep
+// a re-simulation running for ever.
+const (
+	replayMaxFrames = 24 * 60 * 60 * 60 // a day of 60 Hz updates
+	replayMaxEvents = 1 << 22
+	// replayMaxElapsedMs is a year of wall time, well inside time.Duration.
+	replayMaxElapsedMs = 365 * 24 * 60 * 60 * 1000
+	// replayMinEventSize is the fewest bytes an encoded event takes.
+	replayMinEventSize = 4
+)
+
+// replayKeyView is a pseudo key logging a view-settings change made in the
+// pause menu, whose own keys aren't recorded. The FOV and render distance
+// decide which walls may shift, so replays apply them on the same frame. Rune
+// carries the FOV in millidegrees and Mod the distance in hundredths.
+const replayKeyView tcell.Key = 0x7f00
+
+func viewEvent(fov, maxDist float64) *tcell.EventKey {
+	return tcell.NewEventKey(replayKeyView, rune(math.Round(fov*180/math.Pi*1000)), tcell.ModMask(math.Round(maxDist*100)))
+}
+
+func viewFromEvent(ev *tcell.EventKey) (fov, maxDist float64) {
+	return float64(ev.Rune()) / 1000 * math.Pi / 180, float64(ev.Modifiers()) / 100
+}
+
+// replayEvent is a single input event tagged with the frame it was processed on.
+type replayEvent struct {
+	Frame int
+	Key   tcell.Key
+	Rune  rune
+	Mod   tcell.ModMask
+}
+
+// Replay is everything needed to re-run a session exactly: the floor seed and
+// size, plus every key event with its frame index.
+type Replay struct {
+	Seed   int64
+	FloorW int
+	FloorH int
+	Frames int
+	Events []replayEvent
+	// Elapsed is the wall time played before a Save & Quit, added back when
+	// the run is continued. Zero for ordinary replays.
+	Elapsed time.Duration
+}
+
+func (e replayEvent) keyEvent() *tcell.EventKey {
+	return tcell.NewEventKey(e.Key, e.Rune, e.Mod)
+}
+
+// replayRecorder captures key events as they are processed by Game.processEvent.
+type replayRecorder struct {
+	replay Replay
+}
+
+func newReplayRecorder(seed int64, floorW, floorH int) *replayRecorder {
+	return &replayRecorder{replay: Replay{Seed: seed, FloorW: floorW, FloorH: floorH}}
+}
+
+func (r *replayRecorder) record(frame int, ev tcell.Event) {
+	if r == nil {
+		return
+	}
+	key, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return
+	}
+	r.replay.Events = append(r.replay.Events, replayEvent{
+		Frame: frame,
+		Key:   key.Key(),
+		Rune:  key.Rune(),
+		Mod:   key.Modifiers(),
+	})
+}
+
+// finish returns the recorded replay, stamped with the final frame count.
+func (r *replayRecorder) finish(frames int) *Replay {
+	if r == nil {
+		return nil
+	}
+	out := r.replay
+	out.Frames = frames
+	out.Events = append([]replayEvent(nil), r.replay.Events...)
+	return &out
+}
+
+// encodeReplay writes the compact binary form: magic, version, then varints for
+// the header and delta-encoded event frames.
+func encodeReplay(w io.Writer, rp *Replay) error {
+	if rp == nil {
+		return errors.New("nil replay")
+	}
+	buf := make([]byte, 0, 16+len(rp.Events)*5)
+	buf = append(buf, replayMagic...)
+	if rp.Elapsed > 0 {
+		buf = append(buf, replayVersionElapsed)
+	} else {
+		buf = append(buf, replayVersion)
+	}
+	buf = binary.AppendVarint(buf, rp.Seed)
+	buf = binary.AppendUvarint(buf, uint64(rp.FloorW))
+	buf = binary.AppendUvarint(buf, uint64(rp.FloorH))
+	buf = binary.AppendUvarint(buf, uint64(rp.Frames))
+	if rp.Elapsed > 0 {
+		buf = binary.AppendUvarint(buf, uint64(rp.Elapsed.Milliseconds()))
+	}
+	buf = binary.AppendUvarint(buf, uint64(len(rp.Events)))
+
+	prev := 0
+	for _, ev := range rp.Events {
+		if ev.Frame < prev {
+			return fmt.Errorf("events out of order at frame %d", ev.Frame)
+		}
+		buf = binary.AppendUvarint(buf, uint64(ev.Frame-prev))
+		buf = binary.AppendUvarint(buf, uint64(ev.Key))
+		buf = binary.AppendUvarint(buf, uint64(ev.Rune))
+		buf = binary.AppendUvarint(buf, uint64(ev.Mod))
+		prev = ev.Frame
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+func decodeReplay(r io.Reader) (*Replay, error) {
+	br := bufio.NewReader(r)
+
+	head := make([]byte, len(replayMagic)+1)
+	if _, err := io.ReadFull(br, head); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if !bytes.Equal(head[:len(replayMagic)], []byte(replayMagic)) {
+		return nil, errors.New("not a replay file")
+	}
+	version := head[len(replayMagic)]
+	if version != replayVersion && version != replayVersionElapsed {
+		return nil, fmt.Errorf("unsupported replay version %d", version)
+	}
+
+	rp := &Replay{}
+	var err error
+	if rp.Seed, err = binary.ReadVarint(br); err != nil {
+		return nil, fmt.Errorf("read seed: %w", err)
+	}
+	// Header fields are checked before conversion so huge values can't wrap.
+	fields := []struct {
+		dst      *int
+		min, max uint64
+		name     string
+	}{
+		{&rp.FloorW, minFloorSize, maxFloorSize, "floor width"},
+		{&rp.FloorH, minFloorSize, maxFloorSize, "floor height"},
+		{&rp.Frames, 0, replayMaxFrames, "frame count"},
+	}
+	for _, f := range fields {
+		v, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("read header: %w", err)
+		}
+		if v < f.min || v > f.max {
+			return nil, fmt.Errorf("%s %d out of range %d-%d", f.name, v, f.min, f.max)
+		}
+		*f.dst = int(v)
+	}
+	if version == replayVersionElapsed {
+		ms, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("read elapsed time: %w", err)
+		}
+		if ms > replayMaxElapsedMs {
+			return nil, fmt.Errorf("elapsed time %dms out of range", ms)
+		}
+		rp.Elapsed = time.Duration(ms) * time.Millisecond
+	}
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("read event count: %w", err)
+	}
+	if count > replayMaxEvents {
+		return nil, fmt.Errorf("%d events is too many", count)
+	}
+
+	// Only trust the count as far as the buffered input could hold it; the
+	// slice grows as events actually arrive.
+	capacity := count
+	if buffered := uint64(br.Buffered() / replayMinEventSize); capacity > buffered {
+		capacity = buffered
+	}
+	rp.Events = make([]replayEvent, 0, capacity)
+	frame := 0
+	for i := uint64(0); i < count; i++ {
+		var vals [4]uint64
+		for j := range vals {
+			if vals[j], err = binary.ReadUvarint(br); err != nil {
+				return nil, fmt.Errorf("read event %d: %w", i, err)
+			}
+		}
+		if vals[0] > uint64(rp.Frames-frame) {
+			return nil, fmt.Errorf("event %d is past the last frame", i)
+		}
+		frame += int(vals[0])
+		rp.Events = append(rp.Events, replayEvent{
+			Frame: frame,
+			Key:   tcell.Key(vals[1]),
+			Rune:  rune(vals[2]),
+			Mod:   tcell.ModMask(vals[3]),
+		})
+	}
+	return rp, nil
+}
+
+func writeReplayFile(path string, rp *Replay) error {
+	var b bytes.Buffer
+	if err := encodeReplay(&b, rp); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
+
+func readReplayFile(path string) (*Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return decodeReplay(f)
+}
+
+func replayFilename(ts time.Time) string {
+	return fmt.Sprintf("run-%s.replay", ts.UTC().Format("20060102-150405"))
+}
+
+// saveReplay writes the recorded run to path, or to ./replays when path is empty.
+func (g *Game) saveReplay(path string) (string, error) {
+	if g == nil || g.recorder == nil {
+		return "", errors.New("not recording")
+	}
+	if path == "" {
+		dir := filepath.Join(".", "replays")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return "", err
+		}
+		path = filepath.Join(dir, replayFilename(time.Now()))
+	}
+	if err := writeReplayFile(path, g.recorder.finish(g.Frame)); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const maxReplaySpeed = 16
+
+// replayPlayer feeds a recorded Replay back through Game.processEvent one frame
+// at a time. Live input is only used for the playback controls.
+type replayPlayer struct {
+	replay *Replay
+	next   int
+	speed  int
+	paused bool
+	step   bool
+	done   bool
+}
+
+func newReplayPlayer(rp *Replay, speed int) *replayPlayer {
+	if speed < 1 {
+		speed = 1
+	}
+	if speed > maxReplaySpeed {
+		speed = maxReplaySpeed
+	}
+	return &replayPlayer{replay: rp, speed: speed}
+}
+
+// handleControl processes a live key press during playback. It returns false
+// when the viewer asked to quit.
+func (rp *replayPlayer) handleControl(ev tcell.Event) bool {
+	key, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return true
+	}
+	switch key.Key() {
+	case tcell.KeyEscape:
+		return false
+	case tcell.KeyRight:
+		rp.step = true
+		return true
+	case tcell.KeyRune:
+	default:
+		return true
+	}
+
+	switch key.Rune() {
+	case 'q', 'Q':
+		return false
+	case ' ':
+		rp.paused = !rp.paused
+	case '.':
+		rp.step = true
+	case '+', '=':
+		if rp.speed < maxReplaySpeed {
+			rp.speed *= 2
+		}
+	case '-', '_':
+		if rp.speed > 1 {
+			rp.speed /= 2
+		}
+	}
+	return true
+}
+
+// framesThisTick is how many game frames to simulate for one real frame.
+func (rp *replayPlayer) framesThisTick() int {
+	if rp.done {
+		return 0
+	}
+	if rp.paused {
+		if rp.step {
+			rp.step = false
+			return 1
+		}
+		return 0
+	}
+	return rp.speed
+}
+
+// advance simulates a single recorded frame on g.
+func (rp *replayPlayer) advance(g *Game) {
+	if rp.done {
+		return
+	}
+	for rp.next < len(rp.replay.Events) && rp.replay.Events[rp.next].Frame <= g.Frame {
+		g.processEvent(rp.replay.Events[rp.next].keyEvent())
+		rp.next++
+	}
+	if !g.Running {
+		// The run ended itself (a finished speedrun); hold the final frame
+		// instead. Older recordings end with a q, which no longer quits
+		// outside the pause menu; they stop at their last frame below.
+		g.Running = true
+		rp.finish()
+		return
+	}
+	// Events on the last frame came after its update; like restoreRun,
+	// stop once they're in rather than simulating past the recording.
+	if g.Frame >= rp.replay.Frames && rp.next >= len(rp.replay.Events) {
+		rp.finish()
+		return
+	}
+	g.update()
+	if g.Frame >= rp.replay.Frames && rp.next >= len(rp.replay.Events) {
+		rp.finish()
+	}
+}
+
+func (rp *replayPlayer) finish() {
+	rp.done = true
+	rp.paused = true
+}
+
+// tick drains live input into the playback controls and advances the simulation.
+func (rp *replayPlayer) tick(g *Game) {
+drain:
+	for {
+		select {
+		case ev := <-g.events:
+			if resize, ok := ev.(*tcell.EventResize); ok {
+				g.processEvent(resize)
+				continue
+			}
+			if !rp.handleControl(ev) {
+				g.Running = false
+				return
+			}
+		default:
+			break drain
+		}
+	}
+
+	for n := rp.framesThisTick(); n > 0 && !rp.done; n-- {
+		rp.advance(g)
+	}
+}
+
+func (rp *replayPlayer) statusLine(frame int) string {
+	state := fmt.Sprintf("x%d", rp.speed)
+	switch {
+	case rp.done:
+		state = "END"
+	case rp.paused:
+		state = "PAUSED"
+	}
+	return fmt.Sprintf(" REPLAY %s | frame %d/%d | Space: Pause | .: Step | +/-: Speed | Q: Quit ", state, frame, rp.replay.Frames)
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func newHeadlessGame(t *testing.T, floorW, floorH int, seed int64) *Game {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("init screen: %v", err)
+	}
+	screen.SetSize(80, 24)
+	t.Cleanup(screen.Fini)
+	return NewGameWithSeed(screen, floorW, floorH, seed)
+}
+
+func TestReplayEncodeDecodeRoundTrip(t *testing.T) {
+	rp := &Replay{
+		Seed:   -42,
+		FloorW: 16,
+		FloorH: 20,
+		Frames: 900,
+		Events: []replayEvent{
+			{Frame: 0, Key: tcell.KeyRune, Rune: 'w'},
+			{Frame: 3, Key: tcell.KeyRune, Rune: 'd'},
+			{Frame: 3, Key: tcell.KeyEnter},
+			{Frame: 870, Key: tcell.KeyRune, Rune: 'é', Mod: tcell.ModShift},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := encodeReplay(&buf, rp); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	got, err := decodeReplay(&buf)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !reflect.DeepEqual(got, rp) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", got, rp)
+	}
+}
+
+func TestReplayElapsedOnlyChangesTheFormatWhenSet(t *testing.T) {
+	rp := &Replay{Seed: 5, FloorW: 16, FloorH: 16, Frames: 60, Events: []replayEvent{{Frame: 2, Key: tcell.KeyRune, Rune: 'w'}}}
+	var plain bytes.Buffer
+	if err := encodeReplay(&plain, rp); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if v := plain.Bytes()[len(replayMagic)]; v != replayVersion {
+		t.Fatalf("expected a replay without elapsed time to stay version %d, got %d", replayVersion, v)
+	}
+
+	rp.Elapsed = 90*time.Minute + 1500*time.Millisecond
+	var saved bytes.Buffer
+	if err := encodeReplay(&saved, rp); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	got, err := decodeReplay(&saved)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !reflect.DeepEqual(got, rp) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", got, rp)
+	}
+
+	bad := append([]byte(replayMagic), replayVersionElapsed)
+	bad = binary.AppendVarint(bad, 1)
+	for _, f := range []uint64{16, 16, 10, replayMaxElapsedMs + 1, 0} {
+		bad = binary.AppendUvarint(bad, f)
+	}
+	if _, err := decodeReplay(bytes.NewReader(bad)); err == nil {
+		t.Fatal("expected an error for an elapsed time out of range")
+	}
+}
+
+func TestDecodeReplayRejectsGarbage(t *testing.T) {
+	if _, err := decodeReplay(bytes.NewReader([]byte("nope, not a replay"))); err == nil {
+		t.Fatal("expected error for non-replay data")
+	}
+}
+
+// replayHeader encodes a replay header followed by raw varint fields.
+func replayHeader(seed int64, fields ...uint64) []byte {
+	b := append([]byte(replayMagic), replayVersion)
+	b = binary.AppendVarint(b, seed)
+	for _, f := range fields {
+		b = binary.AppendUvarint(b, f)
+	}
+	return b
+}
+
+func TestDecodeReplayRejectsBadHeaders(t *testing.T) {
+	var valid bytes.Buffer
+	if err := encodeReplay(&valid, &Replay{Seed: 1, FloorW: 16, FloorH: 16, Frames: 10, Events: []replayEvent{{Frame: 4, Key: tcell.KeyRune, Rune: 'w'}}}); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{"truncated magic", []byte("ABY")},
+		{"truncated header", replayHeader(1, 16)},
+		{"truncated events", valid.Bytes()[:valid.Len()-2]},
+		{"floor too small", replayHeader(1, minFloorSize-1, 16, 10, 0)},
+		{"floor too large", replayHeader(1, 16, maxFloorSize+1, 10, 0)},
+		{"floor wraps", replayHeader(1, math.MaxUint64, 16, 10, 0)},
+		{"too many frames", replayHeader(1, 16, 16, replayMaxFrames+1, 0)},
+		{"frames wrap", replayHeader(1, 16, 16, math.MaxUint64, 0)},
+		{"too many events", replayHeader(1, 16, 16, 10, replayMaxEvents+1)},
+		{"count past input", replayHeader(1, 16, 16, 10, replayMaxEvents)},
+		{"event past last frame", replayHeader(1, 16, 16, 10, 1, 11, uint64(tcell.KeyRune), 'w', 0)},
+	} {
+		if _, err := decodeReplay(bytes.NewReader(tc.data)); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+	if _, err := decodeReplay(bytes.NewReader(valid.Bytes())); err != nil {
+		t.Fatalf("expected the valid replay to decode: %v", err)
+	}
+}
+
+func FuzzDecodeReplay(f *testing.F) {
+	var valid bytes.Buffer
+	if err := encodeReplay(&valid, &Replay{Seed: 1, FloorW: 16, FloorH: 16, Frames: 10, Events: []replayEvent{{Frame: 4, Key: tcell.KeyRune, Rune: 'w'}}}); err != nil {
+		f.Fatalf("encode: %v", err)
+	}
+	f.Add(valid.Bytes())
+	f.Add(replayHeader(1, 16, 16, 10, replayMaxEvents))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		rp, err := decodeReplay(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		if rp.Frames > replayMaxFrames || len(rp.Events) > replayMaxEvents {
+			t.Fatalf("decoded an oversized replay: %d frames, %d events", rp.Frames, len(rp.Events))
+		}
+		for _, ev := range rp.Events {
+			if ev.Frame > rp.Frames {
+				t.Fatalf("event at frame %d past %d", ev.Frame, rp.Frames)
+			}
+		}
+	})
+}
+
+func TestReplayRecorderCapturesKeyEventsWithFrames(t *testing.T) {
+	g := newHeadlessGame(t, 16, 16, 7)
+	g.recorder = newReplayRecorder(7, 16, 16)
+
+	g.update()
+	g.update()
+	g.processEvent(tcell.NewEventKey(tcell.KeyRune, 'd', tcell.ModNone))
+	g.processEvent(tcell.NewEventResize(100, 30))
+	g.update()
+
+	rp := g.recorder.finish(g.Frame)
+	if rp.Frames != 3 {
+		t.Fatalf("expected 3 frames, got %d", rp.Frames)
+	}
+	if len(rp.Events) != 1 {
+		t.Fatalf("expected only the key event to be recorded, got %d", len(rp.Events))
+	}
+	if ev := rp.Events[0]; ev.Frame != 2 || ev.Rune != 'd' {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+}
+
+func TestReplayPlaybackReproducesRun(t *testing.T) {
+	const seed = 1234
+	inputs := map[int][]rune{
+		0:  {'w'},
+		5:  {'d', 'w'},
+		9:  {'w'},
+		14: {'a', 'a', 'w'},
+		20: {'s'},
+		31: {'d', 'w', 'w'},
+	}
+
+	live := newHeadlessGame(t, 16, 16, seed)
+	live.recorder = newReplayRecorder(seed, 16, 16)
+	for frame := 0; frame < 40; frame++ {
+		for _, r := range inputs[frame] {
+			live.processEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+		}
+		live.update()
+	}
+
+	path := filepath.Join(t.TempDir(), "run.replay")
+	if err := writeReplayFile(path, live.recorder.finish(live.Frame)); err != nil {
+		t.Fatalf("write replay: %v", err)
+	}
+	rp, err := readReplayFile(path)
+	if err != nil {
+		t.Fatalf("read replay: %v", err)
+	}
+
+	played := newHeadlessGame(t, rp.FloorW, rp.FloorH, rp.Seed)
+	player := newReplayPlayer(rp, 4)
+	for i := 0; i < 100 && !player.done; i++ {
+		player.tick(played)
+	}
+
+	if !player.done {
+		t.Fatal("expected playback to finish")
+	}
+	if played.Frame != live.Frame {
+		t.Fatalf("frame mismatch: live %d, replay %d", live.Frame, played.Frame)
+	}
+	if lp, pp := live.Player, played.Player; lp.X != pp.X || lp.Y != pp.Y || lp.Angle != pp.Angle {
+		t.Fatalf("player mismatch: live (%v,%v,%v), replay (%v,%v,%v)", lp.X, lp.Y, lp.Angle, pp.X, pp.Y, pp.Angle)
+	}
+	if played.Floor.Depth != live.Floor.Depth || played.Corruption != live.Corruption {
+		t.Fatalf("state mismatch: live depth %d corruption %f, replay depth %d corruption %f",
+			live.Floor.Depth, live.Corruption, played.Floor.Depth, played.Corruption)
+	}
+}
+
+func TestReplayEndingWithQuitKeyStopsAtItsLastFrame(t *testing.T) {
+	const seed = 77
+	live := newHeadlessGame(t, 16, 16, seed)
+	live.recorder = newReplayRecorder(seed, 16, 16)
+	for frame := 0; frame < 30; frame++ {
+		if frame%7 == 0 {
+			live.processEvent(runeKey('w'))
+		}
+		live.update()
+	}
+	// Recordings from before the pause menu end with the quit key.
+	rp := live.recorder.finish(live.Frame)
+	rp.Events = append(rp.Events, replayEvent{Frame: live.Frame, Key: tcell.KeyRune, Rune: 'q'})
+
+	played := newHeadlessGame(t, rp.FloorW, rp.FloorH, rp.Seed)
+	player := newReplayPlayer(rp, 1)
+	for i := 0; i < 100 && !player.done; i++ {
+		player.advance(played)
+	}
+	if !player.done || played.Frame != live.Frame {
+		t.Fatalf("expected playback to stop at frame %d, got %d (done %v)", live.Frame, played.Frame, player.done)
+	}
+	if lp, pp := live.Player, played.Player; lp.X != pp.X || lp.Y != pp.Y {
+		t.Fatalf("player mismatch: live (%v,%v), replay (%v,%v)", lp.X, lp.Y, pp.X, pp.Y)
+	}
+
+	restored := newHeadlessGame(t, rp.FloorW, rp.FloorH, rp.Seed)
+	restored.restoreRun(rp)
+	if restored.Frame != live.Frame || !restored.Running {
+		t.Fatalf("expected the restored run live at frame %d, got %d (running %v)", live.Frame, restored.Frame, restored.Running)
+	}
+}
+
+func TestReplayPlayerPauseAndStep(t *testing.T) {
+	g := newHeadlessGame(t, 16, 16, 1)
+	rp := newReplayPlayer(&Replay{Seed: 1, FloorW: 16, FloorH: 16, Frames: 10}, 1)
+
+	rp.handleControl(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone))
+	rp.tick(g)
+	if g.Frame != 0 {
+		t.Fatalf("expected no frames while paused, got %d", g.Frame)
+	}
+
+	rp.handleControl(tcell.NewEventKey(tcell.KeyRune, '.', tcell.ModNone))
+	rp.tick(g)
+	if g.Frame != 1 {
+		t.Fatalf("expected one frame after step, got %d", g.Frame)
+	}
+
+	rp.handleControl(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone))
+	rp.handleControl(tcell.NewEventKey(tcell.KeyRune, '+', tcell.ModNone))
+	rp.tick(g)
+	if g.Frame != 3 {
+		t.Fatalf("expected two frames at x2, got frame %d", g.Frame)
+	}
+}
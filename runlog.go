@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"game/events"
+)
+
+// runLogSampleTicks is how often (in update ticks, ~1s) corruption is sampled.
+const runLogSampleTicks = 60
+
+// Run log record types.
+const (
+	logRun        = "run"
+	logFloor      = "floor"
+	logFloorDone  = "floor_done"
+	logCorruption = "corruption"
+	logWatcher    = "watcher"
+	logCheat      = "cheat"
+	logSnapshot   = "snapshot"
+	logEnd        = "end"
+)
+
+// logRecord is one line of the run log. Fields unused by a record type are
+// omitted.
+type logRecord struct {
+	Type  string `json:"type"`
+	Frame int    `json:"frame"`
+	// Millis is wall-clock time since the log was opened.
+	Millis int64 `json:"ms"`
+
+	Depth  int    `json:"depth,omitempty"`
+	Seed   int64  `json:"seed,omitempty"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+	Biome  string `json:"biome,omitempty"`
+
+	OpenCells      int `json:"open_cells,omitempty"`
+	StairsDistance int `json:"stairs_distance,omitempty"`
+
+	Steps    int     `json:"steps,omitempty"`
+	Ticks    int     `json:"ticks,omitempty"`
+	FloorMs  int64   `json:"floor_ms,omitempty"`
+	Watchers int     `json:"watchers,omitempty"`
+	Level    float64 `json:"corruption,omitempty"`
+
+	Action string `json:"action,omitempty"`
+	Detail string `json:"detail,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
+// runLog writes gameplay events as newline-delimited JSON (-log).
+type runLog struct {
+	w     *bufio.Writer
+	file  io.Closer
+	now   func() time.Time
+	start time.Time
+	err   error
+
+	// Current floor, closed out by a floor_done record.
+	depth      int
+	floorFrame int
+	floorStart time.Time
+	floorSteps int // Player.Steps on arrival
+	watchers   int
+}
+
+func newRunLog(w io.Writer, now func() time.Time) *runLog {
+	if now == nil {
+		now = time.Now
+	}
+	return &runLog{w: bufio.NewWriter(w), now: now, start: now()}
+}
+
+func openRunLog(path string) (*runLog, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	l := newRunLog(f, nil)
+	l.file = f
+	return l, nil
+}
+
+// attachRunLog starts logging g: a run header, the current floor, and every
+// later event on g.Events.
+func (g *Game) attachRunLog(l *runLog, seed int64) {
+	if l == nil {
+		return
+	}
+	g.runLog = l
+	w, h := 0, 0
+	if g.FloorManager != nil {
+		w, h = g.FloorManager.MapWidth, g.FloorManager.MapHeight
+	}
+	l.write(logRecord{Type: logRun, Frame: g.Frame, Seed: seed, Width: w, Height: h})
+	l.floorEntered(g)
+
+	events.Subscribe(g.Events, func(events.FloorEntered) {
+		l.floorDone(g)
+		l.floorEntered(g)
+	})
+	events.Subscribe(g.Events, func(e events.WatcherSeen) {
+		l.watchers++
+		l.write(logRecord{Type: logWatcher, Frame: g.Frame, Depth: e.Depth, Watchers: g.watchersSeen()})
+	})
+	events.Subscribe(g.Events, func(e events.CheatUsed) {
+		l.write(logRecord{Type: logCheat, Frame: g.Frame, Depth: l.depth, Action: e.Action, Detail: e.Detail})
+	})
+	events.Subscribe(g.Events, func(e events.SnapshotTaken) {
+		l.write(logRecord{Type: logSnapshot, Frame: g.Frame, Depth: l.depth, Path: e.Path})
+	})
+}
+
+// sample logs the corruption level every runLogSampleTicks frames.
+func (l *runLog) sample(g *Game) {
+	if l == nil || g.Frame%runLogSampleTicks != 0 {
+		return
+	}
+	l.write(logRecord{Type: logCorruption, Frame: g.Frame, Depth: l.depth, Level: g.Corruption})
+}
+
+// close finishes the current floor, writes the end record and closes the file.
+func (l *runLog) close(g *Game) error {
+	if l == nil {
+		return nil
+	}
+	depth := l.depth
+	l.floorDone(g)
+	l.write(logRecord{Type: logEnd, Frame: g.Frame, Depth: depth, Watchers: g.watchersSeen()})
+	if err := l.w.Flush(); err != nil && l.err == nil {
+		l.err = err
+	}
+	if l.file != nil {
+		if err := l.file.Close(); err != nil && l.err == nil {
+			l.err = err
+		}
+	}
+	return l.err
+}
+
+func (l *runLog) floorEntered(g *Game) {
+	f := g.Floor
+	if f == nil {
+		return
+	}
+	l.depth = f.Depth
+	l.floorFrame = g.Frame
+	l.floorStart = l.now()
+	l.watchers = 0
+	if g.Player != nil {
+		l.floorSteps = g.Player.Steps
+	}
+
+	rec := logRecord{
+		Type:           logFloor,
+		Frame:          g.Frame,
+		Depth:          f.Depth,
+		Biome:          f.Biome.Name,
+		OpenCells:      f.Stats.OpenCells,
+		StairsDistance: f.Stats.StairsDistance,
+	}
+	if f.Map != nil {
+		rec.Width, rec.Height = f.Map.Width, f.Map.Height
+	}
+	if g.FloorManager != nil && g.FloorManager.Generator != nil {
+		rec.Seed = g.FloorManager.Generator.Seed
+	}
+	l.write(rec)
+}
+
+func (l *runLog) floorDone(g *Game) {
+	if l.depth == 0 {
+		return
+	}
+	steps := 0
+	if g.Player != nil {
+		steps = g.Player.Steps - l.floorSteps
+	}
+	l.write(logRecord{
+		Type:     logFloorDone,
+		Frame:    g.Frame,
+		Depth:    l.depth,
+		Steps:    steps,
+		Ticks:    g.Frame - l.floorFrame,
+		FloorMs:  l.now().Sub(l.floorStart).Milliseconds(),
+		Watchers: l.watchers,
+	})
+	l.depth = 0
+}
+
+func (l *runLog) write(rec logRecord) {
+	if l.err != nil {
+		return
+	}
+	rec.Millis = l.now().Sub(l.start).Milliseconds()
+	data, err := json.Marshal(rec)
+	if err != nil {
+		l.err = err
+		return
+	}
+	data = append(data, '\n')
+	if _, err := l.w.Write(data); err != nil {
+		l.err = err
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"game/events"
+)
+
+func fakeClock(step time.Duration) func() time.Time {
+	now := time.Unix(0, 0)
+	return func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+}
+
+func decodeRunLog(t *testing.T, data []byte) []logRecord {
+	t.Helper()
+	var recs []logRecord
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var rec logRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("bad line %q: %v", line, err)
+		}
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
+func TestRunLogRecordsFloorsStepsAndCheats(t *testing.T) {
+	var buf bytes.Buffer
+	g := newTestGameForCheats(t)
+	g.attachRunLog(newRunLog(&buf, fakeClock(10*time.Millisecond)), 123)
+
+	start := g.Floor
+	g.Player.Steps += 4
+	g.Player.SetCell(start.StairsPos.X, start.StairsPos.Y)
+	for i := 0; i < runLogSampleTicks; i++ {
+		g.update()
+	}
+	cheat(t, g, "map off")
+	if err := g.runLog.close(g); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	recs := decodeRunLog(t, buf.Bytes())
+	var types []string
+	for _, r := range recs {
+		if r.Type != logCorruption && r.Type != logWatcher {
+			types = append(types, r.Type)
+		}
+	}
+	want := []string{logRun, logFloor, logFloorDone, logFloor, logCheat, logFloorDone, logEnd}
+	if strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected records %v, got %v", want, types)
+	}
+
+	first := recs[1]
+	if first.Depth != 1 || first.OpenCells != start.Stats.OpenCells || first.StairsDistance != start.Stats.StairsDistance || first.Seed != 123 {
+		t.Fatalf("unexpected floor record %+v (stats %+v)", first, start.Stats)
+	}
+	if done := recs[2]; done.Depth != 1 || done.Steps != 4 || done.FloorMs <= 0 {
+		t.Fatalf("unexpected floor_done record %+v", done)
+	}
+
+	samples := 0
+	for _, r := range recs {
+		if r.Type == logCorruption {
+			samples++
+		}
+		if r.Type == logCheat && (r.Action != "map" || r.Detail != "off") {
+			t.Fatalf("unexpected cheat %+v", r)
+		}
+	}
+	if samples != 1 {
+		t.Fatalf("expected one corruption sample per %d ticks, got %d", runLogSampleTicks, samples)
+	}
+}
+
+func TestRunStatsSummarisesLog(t *testing.T) {
+	var buf bytes.Buffer
+	g := newTestGameForCheats(t)
+	g.attachRunLog(newRunLog(&buf, fakeClock(time.Second)), 123)
+	g.Player.SetCell(g.Floor.StairsPos.X, g.Floor.StairsPos.Y)
+	g.update()
+	g.teleportToDepth(12)
+	g.Events.Publish(events.CheatUsed{Action: "teleport", Detail: "12"})
+	if err := g.runLog.close(g); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "run.jsonl")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	var out bytes.Buffer
+	if err := runStats([]string{path}, &out); err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{"Seed 123", "Floors: 3, deepest: 12", "avg", "Cheats: teleport x1"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in summary:\n%s", want, text)
+		}
+	}
+
+	if err := runStats(nil, &out); err == nil {
+		t.Fatal("expected usage error without a log")
+	}
+	bad := filepath.Join(t.TempDir(), "bad.jsonl")
+	os.WriteFile(bad, []byte("{not json\n"), 0o644)
+	if err := runStats([]string{bad}, &out); err == nil {
+		t.Fatal("expected error for a malformed log")
+	}
+}
+
+func TestRunStatsAverageSkipsUnreachableStairs(t *testing.T) {
+	for _, tc := range []struct {
+		distances []int
+		want      string
+	}{
+		{[]int{10, -1, 20}, "15"},
+		{[]int{-1, -1}, "-"},
+	} {
+		s := &runSummary{cheats: map[string]int{}}
+		for i, d := range tc.distances {
+			s.add(logRecord{Type: logFloor, Depth: i + 1, OpenCells: 100, StairsDistance: d})
+		}
+		var out bytes.Buffer
+		s.write(&out)
+		var avg []string
+		for _, line := range strings.Split(out.String(), "\n") {
+			if fields := strings.Fields(line); len(fields) > 0 && fields[0] == "avg" {
+				avg = fields
+			}
+		}
+		if len(avg) < 3 || avg[2] != tc.want {
+			t.Fatalf("distances %v: expected average stairs %q, got %q in\n%s", tc.distances, tc.want, avg, out.String())
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"game/events"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// FloorTime is how long the player spent on one floor.
+type FloorTime struct {
+	Depth   int
+	Ticks   int
+	Elapsed time.Duration
+}
+
+// RunStats accumulates statistics over a run for the end screen and the
+// history file.
+type RunStats struct {
+	Seed    int64
+	Start   time.Time
+	End     time.Time
+	Deepest int
+	Steps   int
+	Floors  []FloorTime
+	// PeakCorruption is the highest corruption level reached.
+	PeakCorruption float64
+	// Exposure is the total corruption added by looking at Watchers.
+	Exposure     float64
+	WatchersSeen int
+	Notes        int
+	// Cheats counts cheat menu actions by name.
+	Cheats map[string]int
+
+	floor      FloorTime
+	floorFrame int
+	floorStart time.Time
+}
+
+func newRunStats(seed int64, depth, frame int, now time.Time) RunStats {
+	s := RunStats{Seed: seed, Start: now, Cheats: map[string]int{}}
+	s.enterFloor(depth, frame, now)
+	return s
+}
+
+// backdate moves the run's start, and the current floor's, back by d: the
+// wall time played before the run was saved.
+func (s *RunStats) backdate(d time.Duration) {
+	s.Start = s.Start.Add(-d)
+	s.floorStart = s.floorStart.Add(-d)
+}
+
+// enterFloor closes out the current floor's time and starts timing depth.
+func (s *RunStats) enterFloor(depth, frame int, now time.Time) {
+	s.closeFloor(frame, now)
+	s.floor = FloorTime{Depth: depth}
+	s.floorFrame = frame
+	s.floorStart = now
+	if depth > s.Deepest {
+		s.Deepest = depth
+	}
+}
+
+func (s *RunStats) closeFloor(frame int, now time.Time) {
+	if s.floor.Depth == 0 {
+		return
+	}
+	s.floor.Ticks = frame - s.floorFrame
+	s.floor.Elapsed = now.Sub(s.floorStart)
+	s.Floors = append(s.Floors, s.floor)
+	s.floor = FloorTime{}
+}
+
+// sample records per-tick maxima and totals.
+func (s *RunStats) sample(corruption, exposure float64) {
+	if corruption > s.PeakCorruption {
+		s.PeakCorruption = corruption
+	}
+	s.Exposure += exposure
+}
+
+// Duration is the wall time between the run's start and end.
+func (s *RunStats) Duration() time.Duration {
+	if s.End.IsZero() {
+		return 0
+	}
+	return s.End.Sub(s.Start)
+}
+
+// CheatCount is the number of cheat actions used.
+func (s *RunStats) CheatCount() int {
+	n := 0
+	for _, c := range s.Cheats {
+		n += c
+	}
+	return n
+}
+
+// SlowestFloor returns the floor with the longest time, if any.
+func (s *RunStats) SlowestFloor() (FloorTime, bool) {
+	if len(s.Floors) == 0 {
+		return FloorTime{}, false
+	}
+	slowest := s.Floors[0]
+	for _, f := range s.Floors[1:] {
+		if f.Elapsed > slowest.Elapsed {
+			slowest = f
+		}
+	}
+	return slowest, true
+}
+
+// listenStats keeps g.Stats current from the event bus.
+func (g *Game) listenStats(bus *events.Bus) {
+	events.Subscribe(bus, func(e events.FloorEntered) {
+		g.Stats.enterFloor(e.Depth, g.Frame, g.statsNow())
+	})
+	events.Subscribe(bus, func(e events.CheatUsed) {
+		if g.Stats.Cheats == nil {
+			g.Stats.Cheats = map[string]int{}
+		}
+		g.Stats.Cheats[e.Action]++
+	})
+}
+
+// statsNow is the time g.Stats stamps events with. While restoreRun re-runs
+// a save, the save's wall time is spread evenly over its frames, so floors
+// finished in an earlier session keep a share of it.
+func (g *Game) statsNow() time.Time {
+	if rp := g.restoring; rp != nil && rp.Frames > 0 {
+		share := float64(rp.Elapsed) * float64(g.Frame) / float64(rp.Frames)
+		return g.Stats.Start.Add(time.Duration(share))
+	}
+	return time.Now()
+}
+
+// finishRun stops the clock and copies end-of-run totals into g.Stats.
+func (g *Game) finishRun(now time.Time) {
+	s := &g.Stats
+	s.closeFloor(g.Frame, now)
+	s.End = now
+	if g.Player != nil {
+		s.Steps = g.Player.Steps
+	}
+	s.WatchersSeen = g.watchersSeen()
+	s.Notes = len(g.Journal)
+}
+
+// endScreenLines summarises the finished run. best is the deepest earlier
+// run without cheats, if there is one.
+func (g *Game) endScreenLines(best *historyEntry) []string {
+	s := &g.Stats
+	lines := []string{
+		"THE DESCENT ENDS",
+		"",
+		fmt.Sprintf("Deepest floor: %d", s.Deepest),
+		fmt.Sprintf("Steps taken: %d", s.Steps),
+		fmt.Sprintf("Time: %s over %d floors", s.Duration().Round(time.Second), len(s.Floors)),
+	}
+	if f, ok := s.SlowestFloor(); ok {
+		lines = append(lines, fmt.Sprintf("Longest floor: %d (%s)", f.Depth, f.Elapsed.Round(100*time.Millisecond)))
+	}
+	lines = append(lines,
+		fmt.Sprintf("Peak corruption: %.0f%%", s.PeakCorruption*100),
+		fmt.Sprintf("Watcher exposure: %.1f%%", s.Exposure*100),
+		fmt.Sprintf("Watchers seen: %d", s.WatchersSeen),
+		fmt.Sprintf("Notes found: %d", s.Notes),
+	)
+	if n := s.CheatCount(); n > 0 {
+		names := make([]string, 0, len(s.Cheats))
+		for name := range s.Cheats {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		lines = append(lines, fmt.Sprintf("Cheats used: %d (%s)", n, strings.Join(names, ", ")))
+	} else {
+		lines = append(lines, "Cheats used: none")
+		switch {
+		case best == nil:
+			lines = append(lines, "", "First recorded descent.")
+		case s.Deepest > best.Deepest:
+			lines = append(lines, "", fmt.Sprintf("New deepest descent! (was %d)", best.Deepest))
+		}
+	}
+	if g.speedrun != nil {
+		lines = append(lines, "", g.speedrun.summary())
+	}
+	return append(lines, "", "Press any key")
+}
+
+// showEndScreen draws the run summary over the last frame and waits for a key.
+func (g *Game) showEndScreen(best *historyEntry) {
+	if g.Screen == nil {
+		return
+	}
+	g.drawCenteredBox(g.endScreenLines(best))
+	g.Screen.Show()
+	for ev := range g.events {
+		switch ev := ev.(type) {
+		case *tcell.EventKey:
+			return
+		case *tcell.EventResize:
+			g.Width, g.Height = ev.Size()
+			g.Screen.Clear()
+			g.drawCenteredBox(g.endScreenLines(best))
+			g.Screen.Sync()
+		}
+	}
+}
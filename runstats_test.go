@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"game/events"
+)
+
+func TestRunStatsTimesEachFloor(t *testing.T) {
+	t0 := time.Unix(100, 0)
+	s := newRunStats(7, 1, 0, t0)
+	s.enterFloor(2, 90, t0.Add(3*time.Second))
+	s.enterFloor(5, 100, t0.Add(4*time.Second))
+	s.closeFloor(160, t0.Add(10*time.Second))
+
+	want := []FloorTime{
+		{Depth: 1, Ticks: 90, Elapsed: 3 * time.Second},
+		{Depth: 2, Ticks: 10, Elapsed: time.Second},
+		{Depth: 5, Ticks: 60, Elapsed: 6 * time.Second},
+	}
+	if len(s.Floors) != len(want) {
+		t.Fatalf("expected %d floors, got %+v", len(want), s.Floors)
+	}
+	for i := range want {
+		if s.Floors[i] != want[i] {
+			t.Fatalf("floor %d: expected %+v, got %+v", i, want[i], s.Floors[i])
+		}
+	}
+	if s.Deepest != 5 {
+		t.Fatalf("expected deepest 5, got %d", s.Deepest)
+	}
+	if f, _ := s.SlowestFloor(); f.Depth != 5 {
+		t.Fatalf("expected slowest floor 5, got %+v", f)
+	}
+
+	s.sample(0.3, 0.01)
+	s.sample(0.2, 0.02)
+	if s.PeakCorruption != 0.3 || s.Exposure < 0.0299 || s.Exposure > 0.0301 {
+		t.Fatalf("unexpected peak %.2f exposure %.3f", s.PeakCorruption, s.Exposure)
+	}
+}
+
+func TestFinishRunCollectsTotals(t *testing.T) {
+	g := newTestGameForCheats(t)
+	start := time.Now()
+	g.Stats = newRunStats(123, g.Floor.Depth, g.Frame, start)
+
+	g.Player.Steps = 12
+	g.Player.SetCell(g.Floor.StairsPos.X, g.Floor.StairsPos.Y)
+	g.update()
+	g.Events.Publish(events.WatcherSeen{Depth: 2})
+	g.finishRun(start.Add(time.Minute))
+
+	s := g.Stats
+	if s.Deepest != 2 || s.Steps != 12 || s.WatchersSeen != 1 || len(s.Floors) != 2 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+	if s.Duration() != time.Minute {
+		t.Fatalf("expected a one minute run, got %s", s.Duration())
+	}
+
+	lines := strings.Join(g.endScreenLines(nil), "\n")
+	for _, want := range []string{"Deepest floor: 2", "Steps taken: 12", "Cheats used: none", "First recorded descent."} {
+		if !strings.Contains(lines, want) {
+			t.Fatalf("expected %q in end screen:\n%s", want, lines)
+		}
+	}
+	if lines := strings.Join(g.endScreenLines(&historyEntry{Deepest: 1}), "\n"); !strings.Contains(lines, "New deepest descent! (was 1)") {
+		t.Fatalf("expected a new record line:\n%s", lines)
+	}
+}
+
+func TestRunStatsCountsCheats(t *testing.T) {
+	g := newTestGameForCheats(t)
+	g.teleportToDepth(9)
+	g.Events.Publish(events.CheatUsed{Action: "teleport", Detail: "9"})
+	g.Events.Publish(events.CheatUsed{Action: "minimap"})
+	g.finishRun(time.Now())
+
+	if g.Stats.CheatCount() != 2 || g.Stats.Deepest != 9 {
+		t.Fatalf("unexpected stats %+v", g.Stats)
+	}
+	lines := strings.Join(g.endScreenLines(nil), "\n")
+	if !strings.Contains(lines, "Cheats used: 2 (minimap, teleport)") || strings.Contains(lines, "First recorded") {
+		t.Fatalf("unexpected end screen:\n%s", lines)
+	}
+}
+
+func TestStatsClockSpreadsSavedTimeWhileRestoring(t *testing.T) {
+	g := newTestGameForCheats(t)
+	g.restoring = &Replay{Frames: 100, Elapsed: time.Minute}
+	g.Frame = 50
+	if got := g.statsNow().Sub(g.Stats.Start); got != 30*time.Second {
+		t.Fatalf("expected half the saved minute at half the frames, got %s", got)
+	}
+	g.restoring = nil
+	if time.Since(g.statsNow()) > time.Second {
+		t.Fatal("expected the wall clock once restoring is done")
+	}
+}
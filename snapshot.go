@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 
+	"game/events"
+
 	"github.com/gdamore/tcell/v2"
 )
 
@@ -44,16 +46,25 @@ func snapshotFilename(meta snapshotMeta) string {
 	return fmt.Sprintf("depth-%02d-%s.txt", meta.Depth, ts)
 }
 
-func writeSnapshotFile(path string, meta snapshotMeta, lines []string) error {
-	var b strings.Builder
-	b.WriteString(fmt.Sprintf("# depth=%d corruption=%.4f ticks=%d size=%dx%d time=%s\n",
+// ansiSnapshotPath returns the ANSI-colored sibling of a plain .txt snapshot path.
+func ansiSnapshotPath(txtPath string) string {
+	return strings.TrimSuffix(txtPath, filepath.Ext(txtPath)) + ".ans"
+}
+
+func snapshotHeader(meta snapshotMeta) string {
+	return fmt.Sprintf("# depth=%d corruption=%.4f ticks=%d size=%dx%d time=%s\n",
 		meta.Depth,
 		meta.Corruption,
 		meta.Ticks,
 		meta.Width,
 		meta.Height,
 		meta.Timestamp.UTC().Format(time.RFC3339Nano),
-	))
+	)
+}
+
+func writeSnapshotFile(path string, meta snapshotMeta, lines []string) error {
+	var b strings.Builder
+	b.WriteString(snapshotHeader(meta))
 	for _, line := range lines {
 		b.WriteString(line)
 		b.WriteByte('\n')
@@ -61,6 +72,19 @@ func writeSnapshotFile(path string, meta snapshotMeta, lines []string) error {
 	return os.WriteFile(path, []byte(b.String()), 0o644)
 }
 
+// writeSnapshotANSIFile writes lines from captureScreenANSI; the file renders
+// in color with `cat` on any ANSI terminal.
+func writeSnapshotANSIFile(path string, meta snapshotMeta, lines []string) error {
+	var b strings.Builder
+	b.WriteString(snapshotHeader(meta))
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	b.WriteString(ansiReset)
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
 func (g *Game) captureSnapshot() (string, error) {
 	if g == nil || g.Screen == nil {
 		return "", fmt.Errorf("screen not ready")
@@ -94,5 +118,12 @@ func (g *Game) captureSnapshot() (string, error) {
 	if err := writeSnapshotFile(path, meta, lines); err != nil {
 		return "", err
 	}
+	if g.SnapshotANSI {
+		ansiLines := captureScreenANSI(g.Screen, g.Width, g.Height)
+		if err := writeSnapshotANSIFile(ansiSnapshotPath(path), meta, ansiLines); err != nil {
+			return "", err
+		}
+	}
+	g.Events.Publish(events.SnapshotTaken{Path: path})
 	return path, nil
 }
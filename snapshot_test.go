@@ -4,6 +4,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -69,4 +70,19 @@ func TestSnapshotRenderToFile(t *testing.T) {
 	if len(data) == 0 {
 		t.Fatal("expected snapshot data")
 	}
+
+	ansiPath := ansiSnapshotPath(path)
+	if filepath.Ext(ansiPath) != ".ans" {
+		t.Fatalf("expected .ans sibling, got %s", ansiPath)
+	}
+	if err := writeSnapshotANSIFile(ansiPath, meta, captureScreenANSI(screen, width, height)); err != nil {
+		t.Fatalf("write ansi snapshot: %v", err)
+	}
+	ansi, err := os.ReadFile(ansiPath)
+	if err != nil {
+		t.Fatalf("read ansi snapshot: %v", err)
+	}
+	if !strings.Contains(string(ansi), "\x1b[") {
+		t.Fatal("expected ANSI escapes in colored snapshot")
+	}
 }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+
+	"game/audio"
+	"game/events"
+)
+
+// listenAudio turns game events into sound events on g.Audio.
+func (g *Game) listenAudio(bus *events.Bus) {
+	events.Subscribe(bus, func(e events.FloorEntered) {
+		if e.From > 0 && e.Depth == e.From+1 {
+			g.Audio.Emit(audio.StairsDescended)
+		}
+	})
+	events.Subscribe(bus, func(events.WatcherSeen) {
+		g.Audio.Emit(audio.WatcherAppears)
+	})
+	events.Subscribe(bus, func(events.CorruptionThresholdCrossed) {
+		g.Audio.Emit(audio.CorruptionThreshold)
+	})
+}
+
+// newAudioBus builds the bus selected by the -audio and -audio-cmd flags.
+func newAudioBus(mode, command string, beep func() error) (*audio.Bus, error) {
+	switch mode {
+	case "", "none":
+		return audio.NewBus(audio.Null{}), nil
+	case "bell":
+		return audio.NewBus(audio.NewBell(beep)), nil
+	case "cmd":
+		c, err := audio.ParseCommand(command)
+		if err != nil {
+			return nil, err
+		}
+		return audio.NewBus(c), nil
+	default:
+		return nil, fmt.Errorf("unknown audio backend %q (want none, bell or cmd)", mode)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"game/audio"
+)
+
+func TestCorruptionThresholdsSoundCues(t *testing.T) {
+	rec := &audio.Recorder{}
+	g := newTestGameForCheats(t)
+	g.SetAudio(audio.NewBus(rec))
+
+	g.CorruptState.AdjustBias(0.20)
+	g.CorruptState.Update(1)
+	if rec.Count(audio.CorruptionThreshold) != 0 {
+		t.Fatal("expected no cue below the first level")
+	}
+	g.CorruptState.AdjustBias(0.35)
+	g.CorruptState.Update(1)
+	if got := rec.Count(audio.CorruptionThreshold); got != 2 {
+		t.Fatalf("expected cues for 25%% and 50%%, got %d", got)
+	}
+	g.CorruptState.AdjustBias(-0.15)
+	g.CorruptState.Update(1)
+	if got := rec.Count(audio.CorruptionThreshold); got != 2 {
+		t.Fatalf("expected no cue when corruption falls, got %d", got)
+	}
+}
+
+func TestUpdateEmitsStairsOnDescent(t *testing.T) {
+	rec := &audio.Recorder{}
+	g := newTestGameForCheats(t)
+	g.SetAudio(audio.NewBus(rec))
+	if g.Player.Audio == nil {
+		t.Fatal("expected SetAudio to reach the player")
+	}
+
+	g.Player.SetCell(g.Floor.StairsPos.X, g.Floor.StairsPos.Y)
+	g.update()
+	if got := rec.Count(audio.StairsDescended); got != 1 {
+		t.Fatalf("expected one stairs event, got %d", got)
+	}
+	if g.Floor.Depth != 2 {
+		t.Fatalf("expected descent to depth 2, got %d", g.Floor.Depth)
+	}
+	if x, y := playerCell(g.Player); x != g.Floor.SpawnPos.X || y != g.Floor.SpawnPos.Y {
+		t.Fatalf("expected player at the new spawn, got %d,%d", x, y)
+	}
+
+	g.teleportToDepth(7)
+	if got := rec.Count(audio.StairsDescended); got != 1 {
+		t.Fatalf("expected teleports to stay silent, got %d stairs events", got)
+	}
+}
+
+func TestNewAudioBusModes(t *testing.T) {
+	rings := 0
+	beep := func() error { rings++; return nil }
+
+	for _, mode := range []string{"", "none"} {
+		bus, err := newAudioBus(mode, "", beep)
+		if err != nil || bus == nil {
+			t.Fatalf("mode %q: bus=%v err=%v", mode, bus, err)
+		}
+		bus.Emit(audio.StairsDescended)
+	}
+	if rings != 0 {
+		t.Fatal("expected the null backend to stay silent")
+	}
+
+	bus, err := newAudioBus("bell", "", beep)
+	if err != nil {
+		t.Fatalf("bell: %v", err)
+	}
+	bus.Emit(audio.StairsDescended)
+	bus.Emit(audio.Footstep)
+	if rings != 1 {
+		t.Fatalf("expected the bell to ring once, got %d", rings)
+	}
+
+	if _, err := newAudioBus("cmd", "", beep); err == nil {
+		t.Fatal("expected cmd without -audio-cmd to fail")
+	}
+	if _, err := newAudioBus("cmd", "true", beep); err != nil {
+		t.Fatalf("cmd: %v", err)
+	}
+	if _, err := newAudioBus("kazoo", "", func() error { return errors.New("unused") }); err == nil {
+		t.Fatal("expected unknown backend to fail")
+	}
+}
+
+func TestWatcherSeenCountsAndSoundsWhenShown(t *testing.T) {
+	rec := &audio.Recorder{}
+	g := newHeadlessGame(t, 24, 24, 7)
+	g.SetAudio(audio.NewBus(rec))
+	g.teleportToDepth(40)
+
+	g.ShowWatchers = false
+	for i := 0; i < 200; i++ {
+		g.update()
+	}
+	if g.watchersSeen() != 0 || rec.Count(audio.WatcherAppears) != 0 {
+		t.Fatalf("expected hidden watchers to go uncounted and silent, got %d seen", g.watchersSeen())
+	}
+
+	g.ShowWatchers = true
+	for i := 0; i < 200; i++ {
+		g.update()
+	}
+	if g.watchersSeen() == 0 {
+		t.Fatal("expected watchers seen once shown")
+	}
+	if got := rec.Count(audio.WatcherAppears); got != g.watchersSeen() {
+		t.Fatalf("expected one sound per watcher seen (%d), got %d", g.watchersSeen(), got)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"game/events"
+)
+
+// ticksPerSecond converts update ticks to displayed time; the main loop
+// targets one tick per 16ms frame.
+const ticksPerSecond = 60
+
+// defaultPBPath stores speedrun personal bests, keyed by target and floor size.
+var defaultPBPath = filepath.Join(".", "runs", "speedrun-pb.json")
+
+// speedrunPB is the best finished run for one target and floor size.
+// Splits[i] is the tick the run reached depth i+2.
+type speedrunPB struct {
+	Ticks  int   `json:"ticks"`
+	Splits []int `json:"splits"`
+	Seed   int64 `json:"seed"`
+}
+
+// speedrun times a descent from depth 1 to Target in update ticks.
+type speedrun struct {
+	Target int
+	// Key identifies the category in the PB file, e.g. "depth10-32x32".
+	Key string
+	PB  *speedrunPB
+
+	start  int
+	Splits []int
+	// Finished is the tick the target was reached, 0 while running.
+	Finished     int
+	Disqualified bool
+	// NewPB is set when a finished run beat (or set) the personal best.
+	NewPB bool
+}
+
+func speedrunKey(target, floorW, floorH int) string {
+	return fmt.Sprintf("depth%d-%dx%d", target, floorW, floorH)
+}
+
+func newSpeedrun(target, floorW, floorH int, pbs map[string]speedrunPB, frame int) *speedrun {
+	sr := &speedrun{Target: target, Key: speedrunKey(target, floorW, floorH), start: frame}
+	if pb, ok := pbs[sr.Key]; ok {
+		sr.PB = &pb
+	}
+	return sr
+}
+
+// elapsed is the run time in ticks at frame.
+func (sr *speedrun) elapsed(frame int) int {
+	if sr.Finished > 0 {
+		return sr.Finished
+	}
+	return frame - sr.start
+}
+
+// split records reaching a new depth at frame. It reports whether the target
+// has been reached.
+func (sr *speedrun) split(depth, frame int) bool {
+	if sr.Finished > 0 {
+		return true
+	}
+	sr.Splits = append(sr.Splits, frame-sr.start)
+	if depth < sr.Target {
+		return false
+	}
+	sr.Finished = frame - sr.start
+	if !sr.Disqualified && (sr.PB == nil || sr.Finished < sr.PB.Ticks) {
+		sr.NewPB = true
+	}
+	return true
+}
+
+// delta compares the latest split with the personal best's split for the same
+// floor, in ticks. ok is false when there is nothing to compare.
+func (sr *speedrun) delta() (int, bool) {
+	i := len(sr.Splits) - 1
+	if sr.PB == nil || i < 0 || i >= len(sr.PB.Splits) {
+		return 0, false
+	}
+	return sr.Splits[i] - sr.PB.Splits[i], true
+}
+
+// statusText is the timer segment of the HUD status line.
+func (sr *speedrun) statusText(frame int) string {
+	if sr == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(formatTicks(sr.elapsed(frame)))
+	if d, ok := sr.delta(); ok {
+		b.WriteString(" (" + formatDeltaTicks(d) + ")")
+	}
+	fmt.Fprintf(&b, " -> %d", sr.Target)
+	if sr.Disqualified {
+		b.WriteString(" DQ")
+	}
+	return b.String()
+}
+
+// summary is the speedrun line for the end screen.
+func (sr *speedrun) summary() string {
+	switch {
+	case sr.Finished == 0:
+		return fmt.Sprintf("Speedrun to %d: not finished", sr.Target)
+	case sr.Disqualified:
+		return fmt.Sprintf("Speedrun to %d: %s (disqualified: cheats)", sr.Target, formatTicks(sr.Finished))
+	case sr.NewPB && sr.PB != nil:
+		return fmt.Sprintf("Speedrun to %d: %s, new PB (%s)", sr.Target, formatTicks(sr.Finished), formatDeltaTicks(sr.Finished-sr.PB.Ticks))
+	case sr.NewPB:
+		return fmt.Sprintf("Speedrun to %d: %s, first PB", sr.Target, formatTicks(sr.Finished))
+	default:
+		return fmt.Sprintf("Speedrun to %d: %s (PB %s)", sr.Target, formatTicks(sr.Finished), formatTicks(sr.PB.Ticks))
+	}
+}
+
+// listenSpeedrun splits on every descent and ends the run at the target.
+func (g *Game) listenSpeedrun(bus *events.Bus) {
+	events.Subscribe(bus, func(e events.FloorEntered) {
+		if g.speedrun == nil || e.Depth != e.From+1 {
+			return
+		}
+		if g.speedrun.split(e.Depth, g.Frame) {
+			g.Running = false
+		}
+	})
+}
+
+// saveSpeedrunPB records sr in the PB file at path if it set a new best.
+func saveSpeedrunPB(path string, sr *speedrun, seed int64) error {
+	if sr == nil || !sr.NewPB {
+		return nil
+	}
+	pbs, err := readSpeedrunPBs(path)
+	if err != nil {
+		return err
+	}
+	if pbs == nil {
+		pbs = map[string]speedrunPB{}
+	}
+	pbs[sr.Key] = speedrunPB{Ticks: sr.Finished, Splits: sr.Splits, Seed: seed}
+	data, err := json.MarshalIndent(pbs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// readSpeedrunPBs loads the PB file. A missing file has no PBs.
+func readSpeedrunPBs(path string) (map[string]speedrunPB, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pbs map[string]speedrunPB
+	if err := json.Unmarshal(data, &pbs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return pbs, nil
+}
+
+// formatTicks renders ticks as m:ss.cc.
+func formatTicks(ticks int) string {
+	if ticks < 0 {
+		ticks = 0
+	}
+	centis := ticks * 100 / ticksPerSecond
+	return fmt.Sprintf("%d:%02d.%02d", centis/6000, centis/100%60, centis%100)
+}
+
+// formatDeltaTicks renders a signed split difference in seconds.
+func formatDeltaTicks(ticks int) string {
+	sign := "+"
+	if ticks < 0 {
+		sign = "-"
+		ticks = -ticks
+	}
+	centis := ticks * 100 / ticksPerSecond
+	return fmt.Sprintf("%s%d.%02d", sign, centis/100, centis%100)
+}
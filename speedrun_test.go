@@ -0,0 +1,125 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestFormatTicks(t *testing.T) {
+	cases := map[int]string{0: "0:00.00", 30: "0:00.50", 61 * ticksPerSecond: "1:01.00", -5: "0:00.00"}
+	for ticks, want := range cases {
+		if got := formatTicks(ticks); got != want {
+			t.Fatalf("formatTicks(%d) = %q, want %q", ticks, got, want)
+		}
+	}
+	if got := formatDeltaTicks(-90); got != "-1.50" {
+		t.Fatalf("unexpected negative delta %q", got)
+	}
+	if got := formatDeltaTicks(6); got != "+0.10" {
+		t.Fatalf("unexpected positive delta %q", got)
+	}
+}
+
+func TestSpeedrunSplitsAgainstPB(t *testing.T) {
+	pbs := map[string]speedrunPB{
+		speedrunKey(3, 20, 20): {Ticks: 200, Splits: []int{120, 200}},
+	}
+	sr := newSpeedrun(3, 20, 20, pbs, 10)
+	if sr.PB == nil {
+		t.Fatal("expected the PB for this category to load")
+	}
+	if sr.split(2, 100) {
+		t.Fatal("expected the run to continue before the target")
+	}
+	if d, ok := sr.delta(); !ok || d != -30 {
+		t.Fatalf("expected -30 ticks against the PB split, got %d %v", d, ok)
+	}
+	if got := sr.statusText(130); !strings.Contains(got, "0:02.00 (-0.50) -> 3") {
+		t.Fatalf("unexpected status %q", got)
+	}
+	if !sr.split(3, 190) {
+		t.Fatal("expected the target depth to finish the run")
+	}
+	if sr.Finished != 180 || !sr.NewPB || sr.elapsed(500) != 180 {
+		t.Fatalf("unexpected finish %+v", sr)
+	}
+	if !strings.Contains(sr.summary(), "new PB (-0.33)") {
+		t.Fatalf("unexpected summary %q", sr.summary())
+	}
+
+	other := newSpeedrun(3, 32, 32, pbs, 0)
+	if other.PB != nil {
+		t.Fatal("expected PBs to be per floor size")
+	}
+}
+
+func TestSpeedrunEndsGameAtTargetDepth(t *testing.T) {
+	g := newTestGameForCheats(t)
+	g.speedrun = newSpeedrun(3, 32, 32, nil, g.Frame)
+
+	for i := 0; i < 2; i++ {
+		g.Frame += 50
+		g.Player.SetCell(g.Floor.StairsPos.X, g.Floor.StairsPos.Y)
+		g.update()
+	}
+	if g.Running {
+		t.Fatal("expected the run to end at the target depth")
+	}
+	sr := g.speedrun
+	if len(sr.Splits) != 2 || sr.Finished != sr.Splits[1] || !sr.NewPB {
+		t.Fatalf("unexpected speedrun state %+v", sr)
+	}
+	g.finishRun(g.Stats.End)
+	if lines := strings.Join(g.endScreenLines(nil), "\n"); !strings.Contains(lines, "first PB") {
+		t.Fatalf("expected speedrun summary on the end screen:\n%s", lines)
+	}
+}
+
+func TestSpeedrunCheatConsoleDisqualifies(t *testing.T) {
+	g := newTestGameForCheats(t)
+	g.speedrun = newSpeedrun(2, 32, 32, nil, 0)
+	g.handleCheatEvent(tcell.NewEventKey(tcell.KeyRune, 'c', tcell.ModNone))
+	if !g.speedrun.Disqualified {
+		t.Fatal("expected opening the cheat console to disqualify the run")
+	}
+	g.speedrun.split(2, 60)
+	if g.speedrun.NewPB || !strings.Contains(g.speedrun.summary(), "disqualified") {
+		t.Fatalf("expected a disqualified finish, got %+v", g.speedrun)
+	}
+	if !strings.HasSuffix(g.speedrun.statusText(60), " DQ") {
+		t.Fatalf("expected DQ in the status text, got %q", g.speedrun.statusText(60))
+	}
+}
+
+func TestSpeedrunPBFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs", "pb.json")
+	if pbs, err := readSpeedrunPBs(path); err != nil || pbs != nil {
+		t.Fatalf("expected no PBs for a missing file, got %v %v", pbs, err)
+	}
+
+	sr := newSpeedrun(2, 16, 16, nil, 0)
+	sr.split(2, 300)
+	if err := saveSpeedrunPB(path, sr, 99); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	pbs, err := readSpeedrunPBs(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	pb, ok := pbs[sr.Key]
+	if !ok || pb.Ticks != 300 || pb.Seed != 99 || len(pb.Splits) != 1 {
+		t.Fatalf("unexpected PB %+v", pbs)
+	}
+
+	slower := newSpeedrun(2, 16, 16, pbs, 0)
+	slower.split(2, 400)
+	if err := saveSpeedrunPB(path, slower, 5); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if pbs, _ := readSpeedrunPBs(path); pbs[sr.Key].Ticks != 300 {
+		t.Fatal("expected a slower run to keep the old PB")
+	}
+}
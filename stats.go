@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// floorSummary collects the floor and floor_done records for one floor.
+type floorSummary struct {
+	floor logRecord
+	done  *logRecord
+}
+
+// runSummary is what `stats` reports for one run log.
+type runSummary struct {
+	run       logRecord
+	floors    []floorSummary
+	peak      float64
+	watchers  int
+	cheats    map[string]int
+	snapshots int
+	end       *logRecord
+}
+
+// runStats implements the `stats LOG...` subcommand.
+func runStats(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: stats LOG.jsonl [LOG.jsonl...]")
+	}
+	for i, path := range args {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		s, err := readRunLog(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "%s\n", path)
+		s.write(out)
+	}
+	return nil
+}
+
+func readRunLog(r io.Reader) (*runSummary, error) {
+	s := &runSummary{cheats: map[string]int{}}
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		var rec logRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		s.add(rec)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if s.run.Type == "" && len(s.floors) == 0 {
+		return nil, errors.New("no run records")
+	}
+	return s, nil
+}
+
+func (s *runSummary) add(rec logRecord) {
+	switch rec.Type {
+	case logRun:
+		s.run = rec
+	case logFloor:
+		s.floors = append(s.floors, floorSummary{floor: rec})
+	case logFloorDone:
+		for i := len(s.floors) - 1; i >= 0; i-- {
+			if s.floors[i].floor.Depth == rec.Depth && s.floors[i].done == nil {
+				done := rec
+				s.floors[i].done = &done
+				break
+			}
+		}
+	case logCorruption:
+		if rec.Level > s.peak {
+			s.peak = rec.Level
+		}
+	case logWatcher:
+		s.watchers++
+	case logCheat:
+		s.cheats[rec.Action]++
+	case logSnapshot:
+		s.snapshots++
+	case logEnd:
+		end := rec
+		s.end = &end
+	}
+}
+
+func (s *runSummary) write(out io.Writer) {
+	deepest := 0
+	for _, f := range s.floors {
+		if f.floor.Depth > deepest {
+			deepest = f.floor.Depth
+		}
+	}
+	fmt.Fprintf(out, "Seed %d, floor size %dx%d\n", s.run.Seed, s.run.Width, s.run.Height)
+	fmt.Fprintf(out, "Floors: %d, deepest: %d", len(s.floors), deepest)
+	if s.end != nil {
+		fmt.Fprintf(out, ", played %s (%d ticks)", time.Duration(s.end.Millis)*time.Millisecond, s.end.Frame)
+	} else {
+		fmt.Fprint(out, " (log ends mid-run)")
+	}
+	fmt.Fprintln(out)
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "depth\tbiome\topen\tstairs\tsteps\tticks\ttime\twatchers\t")
+	var open, stairs, reachable, steps, ticks, finished int
+	var ms int64
+	for _, f := range s.floors {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t", f.floor.Depth, f.floor.Biome, f.floor.OpenCells, f.floor.StairsDistance)
+		open += f.floor.OpenCells
+		// A distance of -1 marks stairs that can't be reached on foot.
+		if f.floor.StairsDistance >= 0 {
+			stairs += f.floor.StairsDistance
+			reachable++
+		}
+		if f.done == nil {
+			fmt.Fprintln(tw, "-\t-\t-\t-\t")
+			continue
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%.1fs\t%d\t\n", f.done.Steps, f.done.Ticks, float64(f.done.FloorMs)/1000, f.done.Watchers)
+		steps += f.done.Steps
+		ticks += f.done.Ticks
+		ms += f.done.FloorMs
+		finished++
+	}
+	if n := len(s.floors); n > 0 {
+		avgStairs := "-"
+		if reachable > 0 {
+			avgStairs = strconv.Itoa(stairs / reachable)
+		}
+		fmt.Fprintf(tw, "avg\t\t%d\t%s\t", open/n, avgStairs)
+		if finished > 0 {
+			fmt.Fprintf(tw, "%d\t%d\t%.1fs\t\t\n", steps/finished, ticks/finished, float64(ms)/1000/float64(finished))
+		} else {
+			fmt.Fprintln(tw, "-\t-\t-\t\t")
+		}
+	}
+	tw.Flush()
+
+	fmt.Fprintf(out, "Peak corruption: %.0f%%\n", s.peak*100)
+	fmt.Fprintf(out, "Watchers seen: %d\n", s.watchers)
+	fmt.Fprintf(out, "Snapshots: %d\n", s.snapshots)
+	if len(s.cheats) == 0 {
+		fmt.Fprintln(out, "Cheats: none")
+		return
+	}
+	actions := make([]string, 0, len(s.cheats))
+	for a := range s.cheats {
+		actions = append(actions, a)
+	}
+	sort.Strings(actions)
+	parts := make([]string, 0, len(actions))
+	for _, a := range actions {
+		parts = append(parts, fmt.Sprintf("%s x%d", a, s.cheats[a]))
+	}
+	fmt.Fprintf(out, "Cheats: %s\n", strings.Join(parts, ", "))
+}
@@ -0,0 +1,39 @@
+package theme
+
+import "github.com/gdamore/tcell/v2"
+
+// Weighted is a table entry picked with probability proportional to Weight.
+type Weighted[T any] struct {
+	Value  T
+	Weight int
+}
+
+// Tables are a pack's resolved tables for a single depth.
+type Tables struct {
+	Whispers           []Weighted[string]
+	GlitchChars        []Weighted[rune]
+	CorruptColors      []Weighted[tcell.Color]
+	WatcherChar        rune
+	WatcherGlitchChars []Weighted[rune]
+}
+
+// Pick selects an entry using the noise value n. With equal weights this is
+// the same as indexing by n modulo the table length.
+func Pick[T any](table []Weighted[T], n uint64) (T, bool) {
+	var zero T
+	total := 0
+	for _, e := range table {
+		total += e.Weight
+	}
+	if total <= 0 {
+		return zero, false
+	}
+	target := int(n % uint64(total))
+	for _, e := range table {
+		if target < e.Weight {
+			return e.Value, true
+		}
+		target -= e.Weight
+	}
+	return zero, false
+}
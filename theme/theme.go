@@ -0,0 +1,261 @@
+// Package theme loads "theme packs": the whisper text, glitch glyphs and
+// corruption colors used by render and entities, defined per depth band so
+// writers can add content without touching Go code.
+package theme
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+//go:embed packs/default.json
+var packsFS embed.FS
+
+// Pack is a named list of depth bands. Later bands override earlier ones
+// where their depth ranges overlap.
+type Pack struct {
+	Name  string `json:"name"`
+	Bands []Band `json:"bands"`
+}
+
+// Band holds the tables for a depth range. Empty tables inherit from earlier
+// matching bands.
+type Band struct {
+	MinDepth           int           `json:"min_depth"`
+	MaxDepth           int           `json:"max_depth"` // 0 means no upper bound
+	Whispers           []WhisperSpec `json:"whispers"`
+	GlitchChars        []GlyphSpec   `json:"glitch_chars"`
+	CorruptColors      []ColorSpec   `json:"corrupt_colors"`
+	WatcherChar        string        `json:"watcher_char"`
+	WatcherGlitchChars []GlyphSpec   `json:"watcher_glitch_chars"`
+}
+
+// WhisperSpec is a whisper template; see Expand for the placeholders.
+type WhisperSpec struct {
+	Text   string `json:"text"`
+	Weight int    `json:"weight"`
+}
+
+type GlyphSpec struct {
+	Char   string `json:"char"`
+	Weight int    `json:"weight"`
+}
+
+type ColorSpec struct {
+	Color  string `json:"color"`
+	Weight int    `json:"weight"`
+}
+
+func (b Band) inRange(depth int) bool {
+	if depth < b.MinDepth {
+		return false
+	}
+	return b.MaxDepth == 0 || depth <= b.MaxDepth
+}
+
+var (
+	defaultOnce sync.Once
+	defaultPack *Pack
+)
+
+// Default returns the embedded default pack.
+func Default() *Pack {
+	defaultOnce.Do(func() {
+		data, err := packsFS.ReadFile("packs/default.json")
+		if err != nil {
+			panic(fmt.Sprintf("theme: read embedded default pack: %v", err))
+		}
+		p, err := Parse(data)
+		if err != nil {
+			panic(fmt.Sprintf("theme: parse embedded default pack: %v", err))
+		}
+		defaultPack = p
+	})
+	return defaultPack
+}
+
+// Parse decodes and validates a pack from JSON.
+func Parse(data []byte) (*Pack, error) {
+	var p Pack
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (p *Pack) validate() error {
+	for i, b := range p.Bands {
+		if b.MaxDepth != 0 && b.MaxDepth < b.MinDepth {
+			return fmt.Errorf("band %d: max_depth %d < min_depth %d", i, b.MaxDepth, b.MinDepth)
+		}
+		for _, g := range append(append([]GlyphSpec(nil), b.GlitchChars...), b.WatcherGlitchChars...) {
+			if len([]rune(g.Char)) != 1 {
+				return fmt.Errorf("band %d: glyph %q must be a single character", i, g.Char)
+			}
+		}
+		if b.WatcherChar != "" && len([]rune(b.WatcherChar)) != 1 {
+			return fmt.Errorf("band %d: watcher_char %q must be a single character", i, b.WatcherChar)
+		}
+		for _, c := range b.CorruptColors {
+			if _, err := parseColor(c.Color); err != nil {
+				return fmt.Errorf("band %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadFile reads a pack from a JSON file, or every *.json file in a directory
+// (in name order, layered onto each other).
+func LoadFile(path string) (*Pack, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		p, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return p, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	out := &Pack{Name: filepath.Base(path)}
+	for _, m := range matches {
+		p, err := LoadFile(m)
+		if err != nil {
+			return nil, err
+		}
+		out = out.Layer(p)
+	}
+	return out, nil
+}
+
+// Layer returns a new pack with other's bands applied on top of p's.
+func (p *Pack) Layer(other *Pack) *Pack {
+	out := &Pack{Name: p.Name}
+	out.Bands = append(out.Bands, p.Bands...)
+	if other != nil {
+		out.Bands = append(out.Bands, other.Bands...)
+		if other.Name != "" {
+			out.Name = p.Name + "+" + other.Name
+		}
+	}
+	return out
+}
+
+// Resolve flattens every band matching depth into the tables to use there.
+func (p *Pack) Resolve(depth int) *Tables {
+	t := &Tables{}
+	if p == nil {
+		return t
+	}
+	for _, b := range p.Bands {
+		if !b.inRange(depth) {
+			continue
+		}
+		if len(b.Whispers) > 0 {
+			t.Whispers = t.Whispers[:0]
+			for _, w := range b.Whispers {
+				t.Whispers = append(t.Whispers, Weighted[string]{Value: w.Text, Weight: weightOf(w.Weight)})
+			}
+		}
+		if len(b.GlitchChars) > 0 {
+			t.GlitchChars = glyphTable(b.GlitchChars)
+		}
+		if len(b.CorruptColors) > 0 {
+			t.CorruptColors = t.CorruptColors[:0]
+			for _, c := range b.CorruptColors {
+				color, _ := parseColor(c.Color)
+				t.CorruptColors = append(t.CorruptColors, Weighted[tcell.Color]{Value: color, Weight: weightOf(c.Weight)})
+			}
+		}
+		if b.WatcherChar != "" {
+			t.WatcherChar = []rune(b.WatcherChar)[0]
+		}
+		if len(b.WatcherGlitchChars) > 0 {
+			t.WatcherGlitchChars = glyphTable(b.WatcherGlitchChars)
+		}
+	}
+	return t
+}
+
+var (
+	defaultTablesMu sync.Mutex
+	defaultTables   = map[int]*Tables{}
+)
+
+// DefaultTables returns the default pack resolved at depth. Results are cached.
+func DefaultTables(depth int) *Tables {
+	defaultTablesMu.Lock()
+	defer defaultTablesMu.Unlock()
+	if t, ok := defaultTables[depth]; ok {
+		return t
+	}
+	t := Default().Resolve(depth)
+	defaultTables[depth] = t
+	return t
+}
+
+func glyphTable(specs []GlyphSpec) []Weighted[rune] {
+	out := make([]Weighted[rune], 0, len(specs))
+	for _, g := range specs {
+		out = append(out, Weighted[rune]{Value: []rune(g.Char)[0], Weight: weightOf(g.Weight)})
+	}
+	return out
+}
+
+func weightOf(w int) int {
+	if w <= 0 {
+		return 1
+	}
+	return w
+}
+
+func parseColor(name string) (tcell.Color, error) {
+	c := tcell.GetColor(strings.ToLower(strings.TrimSpace(name)))
+	if c == tcell.ColorDefault {
+		return c, fmt.Errorf("unknown color %q", name)
+	}
+	return c, nil
+}
+
+// RunState is the run information whispers may reference.
+type RunState struct {
+	Depth        int
+	WatchersSeen int
+	Corruption   float64
+}
+
+// Expand fills whisper placeholders: {depth}, {watchers} and {corruption} (as a percentage).
+func Expand(text string, state RunState) string {
+	if !strings.Contains(text, "{") {
+		return text
+	}
+	return strings.NewReplacer(
+		"{depth}", strconv.Itoa(state.Depth),
+		"{watchers}", strconv.Itoa(state.WatchersSeen),
+		"{corruption}", fmt.Sprintf("%.0f%%", state.Corruption*100),
+	).Replace(text)
+}
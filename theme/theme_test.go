@@ -0,0 +1,97 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestDefaultPackResolvesAllTables(t *testing.T) {
+	for _, depth := range []int{1, 20, 35, 60} {
+		tb := Default().Resolve(depth)
+		if len(tb.Whispers) == 0 || len(tb.GlitchChars) == 0 || len(tb.CorruptColors) == 0 || len(tb.WatcherGlitchChars) == 0 {
+			t.Fatalf("depth %d: expected all tables populated, got %+v", depth, tb)
+		}
+		if tb.WatcherChar != 'W' {
+			t.Fatalf("depth %d: expected watcher char W, got %q", depth, tb.WatcherChar)
+		}
+	}
+}
+
+func TestDeeperBandsOverrideWhispers(t *testing.T) {
+	shallow := Default().Resolve(1)
+	deep := Default().Resolve(45)
+	if len(shallow.Whispers) == len(deep.Whispers) && shallow.Whispers[0] == deep.Whispers[0] {
+		t.Fatal("expected deep band to replace whisper table")
+	}
+	// Glyphs aren't overridden by deeper bands and are inherited.
+	if len(deep.GlitchChars) != len(shallow.GlitchChars) {
+		t.Fatalf("expected inherited glitch chars, got %d vs %d", len(deep.GlitchChars), len(shallow.GlitchChars))
+	}
+}
+
+func TestPickRespectsWeights(t *testing.T) {
+	table := []Weighted[string]{{Value: "a", Weight: 1}, {Value: "b", Weight: 3}}
+	counts := map[string]int{}
+	for n := uint64(0); n < 400; n++ {
+		v, ok := Pick(table, n)
+		if !ok {
+			t.Fatal("expected pick")
+		}
+		counts[v]++
+	}
+	if counts["a"] != 100 || counts["b"] != 300 {
+		t.Fatalf("expected 1:3 split, got %v", counts)
+	}
+	if _, ok := Pick[string](nil, 5); ok {
+		t.Fatal("expected empty table to pick nothing")
+	}
+}
+
+func TestExpand(t *testing.T) {
+	got := Expand("{watchers} saw you on floor {depth} ({corruption})", RunState{Depth: 31, WatchersSeen: 4, Corruption: 0.5})
+	if got != "4 saw you on floor 31 (50%)" {
+		t.Fatalf("unexpected expansion %q", got)
+	}
+}
+
+func TestParseRejectsBadPacks(t *testing.T) {
+	bad := []string{
+		`{"bands":[{"min_depth":10,"max_depth":5}]}`,
+		`{"bands":[{"glitch_chars":[{"char":"ab"}]}]}`,
+		`{"bands":[{"corrupt_colors":[{"color":"notacolor"}]}]}`,
+		`not json`,
+	}
+	for _, src := range bad {
+		if _, err := Parse([]byte(src)); err == nil {
+			t.Fatalf("expected error for %s", src)
+		}
+	}
+}
+
+func TestLoadFileLayersOverDefault(t *testing.T) {
+	dir := t.TempDir()
+	src := `{"name":"extra","bands":[{"min_depth":10,"max_depth":20,"whispers":[{"text":"custom"}],"corrupt_colors":[{"color":"lime"}]}]}`
+	if err := os.WriteFile(filepath.Join(dir, "extra.json"), []byte(src), 0o644); err != nil {
+		t.Fatalf("write pack: %v", err)
+	}
+
+	p, err := LoadFile(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	pack := Default().Layer(p)
+
+	tb := pack.Resolve(15)
+	if len(tb.Whispers) != 1 || tb.Whispers[0].Value != "custom" {
+		t.Fatalf("expected custom whisper at depth 15, got %+v", tb.Whispers)
+	}
+	if tb.CorruptColors[0].Value != tcell.ColorLime {
+		t.Fatalf("expected lime corrupt color, got %v", tb.CorruptColors[0].Value)
+	}
+	if outside := pack.Resolve(25); outside.Whispers[0].Value == "custom" {
+		t.Fatal("expected custom band limited to its depth range")
+	}
+}
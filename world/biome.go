@@ -0,0 +1,143 @@
+package world
+
+import (
+	"game/render"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Algorithm selects how a floor's layout is carved.
+type Algorithm int
+
+const (
+	// AlgorithmDrunkWalk carves a single winding random walk.
+	AlgorithmDrunkWalk Algorithm = iota
+	// AlgorithmRooms carves rectangular rooms joined by corridors.
+	AlgorithmRooms
+	// AlgorithmCaves grows organic caverns with cellular automata.
+	AlgorithmCaves
+)
+
+// Biome is a depth band with its own layout, look and corruption feel.
+type Biome struct {
+	Name      string
+	MinDepth  int
+	MaxDepth  int // 0 means no upper bound
+	Algorithm Algorithm
+
+	Shading render.Shading
+	Palette render.Palette
+	Effects render.EffectScale
+
+	// WatcherDensity multiplies the Watcher count for the depth.
+	WatcherDensity float64
+}
+
+// Biomes are ordered by depth and cover every depth from 1 with no gaps.
+var Biomes = []Biome{
+	{
+		Name:           "The Cellars",
+		MinDepth:       1,
+		MaxDepth:       9,
+		Algorithm:      AlgorithmDrunkWalk,
+		Palette:        render.DefaultPalette,
+		Effects:        render.DefaultEffectScale,
+		WatcherDensity: 1,
+	},
+	{
+		Name:      "Drowned Halls",
+		MinDepth:  10,
+		MaxDepth:  19,
+		Algorithm: AlgorithmRooms,
+		Shading: render.Shading{
+			Walls:  []rune{'█', '▓', '▒', '░', '~', ' '},
+			Floors: []rune{'≈', '~', '-', ' '},
+		},
+		Palette:        render.Palette{Wall: tcell.ColorCadetBlue, Ceiling: tcell.ColorNavy, Floor: tcell.ColorTeal},
+		Effects:        render.EffectScale{Glitch: 1, Bleed: 1.5, Whisper: 0.75, FakeGeo: 1},
+		WatcherDensity: 1,
+	},
+	{
+		Name:      "Flesh Caves",
+		MinDepth:  20,
+		MaxDepth:  34,
+		Algorithm: AlgorithmCaves,
+		Shading: render.Shading{
+			Walls:  []rune{'█', '▓', '▒', '░', ',', ' '},
+			Floors: []rune{',', '\'', '`', ' '},
+		},
+		Palette:        render.Palette{Wall: tcell.ColorIndianRed, Ceiling: tcell.ColorMaroon, Floor: tcell.ColorRosyBrown},
+		Effects:        render.EffectScale{Glitch: 1.25, Bleed: 1.5, Whisper: 1, FakeGeo: 1},
+		WatcherDensity: 1.5,
+	},
+	{
+		Name:      "Cyclopean Vaults",
+		MinDepth:  35,
+		MaxDepth:  44,
+		Algorithm: AlgorithmRooms,
+		Shading: render.Shading{
+			Walls:  []rune{'#', '=', '+', '-', '.', ' '},
+			Floors: []rune{'=', '-', '.', ' '},
+		},
+		Palette:        render.Palette{Wall: tcell.ColorDarkKhaki, Ceiling: tcell.ColorDarkOliveGreen, Floor: tcell.ColorOlive},
+		Effects:        render.EffectScale{Glitch: 1, Bleed: 1, Whisper: 1.5, FakeGeo: 1.25},
+		WatcherDensity: 1,
+	},
+	{
+		Name:      "The Abyss",
+		MinDepth:  45,
+		Algorithm: AlgorithmDrunkWalk,
+		Shading: render.Shading{
+			Walls:  []rune{'▓', '▒', '░', '·', ' '},
+			Floors: []rune{'·', ' ', ' '},
+		},
+		Palette:        render.Palette{Wall: tcell.ColorMediumPurple, Ceiling: tcell.ColorBlack, Floor: tcell.ColorIndigo},
+		Effects:        render.EffectScale{Glitch: 1.5, Bleed: 1.5, Whisper: 1.5, FakeGeo: 1.5},
+		WatcherDensity: 2,
+	},
+}
+
+// BiomeForDepth returns the biome for depth. The first floor of every band
+// after the first is a transition floor blending the previous band into it.
+func BiomeForDepth(depth int) Biome {
+	if depth < 1 {
+		depth = 1
+	}
+	for i, b := range Biomes {
+		if depth < b.MinDepth || (b.MaxDepth != 0 && depth > b.MaxDepth) {
+			continue
+		}
+		if i > 0 && depth == b.MinDepth {
+			return transitionBiome(Biomes[i-1], b)
+		}
+		return b
+	}
+	return Biomes[len(Biomes)-1]
+}
+
+// transitionBiome keeps the upper band's floor and ceiling while the lower
+// band's walls close in.
+func transitionBiome(from, to Biome) Biome {
+	return Biome{
+		Name:      "Threshold of " + to.Name,
+		MinDepth:  to.MinDepth,
+		MaxDepth:  to.MinDepth,
+		Algorithm: to.Algorithm,
+		Shading: render.Shading{
+			Walls:  to.Shading.Walls,
+			Floors: from.Shading.Floors,
+		},
+		Palette: render.Palette{
+			Wall:    to.Palette.Wall,
+			Ceiling: from.Palette.Ceiling,
+			Floor:   from.Palette.Floor,
+		},
+		Effects: render.EffectScale{
+			Glitch:  (from.Effects.Glitch + to.Effects.Glitch) / 2,
+			Bleed:   (from.Effects.Bleed + to.Effects.Bleed) / 2,
+			Whisper: (from.Effects.Whisper + to.Effects.Whisper) / 2,
+			FakeGeo: (from.Effects.FakeGeo + to.Effects.FakeGeo) / 2,
+		},
+		WatcherDensity: (from.WatcherDensity + to.WatcherDensity) / 2,
+	}
+}
@@ -0,0 +1,68 @@
+package world
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBiomesCoverEveryDepth(t *testing.T) {
+	next := 1
+	for i, b := range Biomes {
+		if b.MinDepth != next {
+			t.Fatalf("biome %d (%s) starts at %d, expected %d", i, b.Name, b.MinDepth, next)
+		}
+		if b.MaxDepth == 0 {
+			if i != len(Biomes)-1 {
+				t.Fatalf("only the last biome may be unbounded, got %s", b.Name)
+			}
+			return
+		}
+		next = b.MaxDepth + 1
+	}
+	t.Fatal("expected the last biome to be unbounded")
+}
+
+func TestBiomeForDepthTransitions(t *testing.T) {
+	if got := BiomeForDepth(1).Name; got != "The Cellars" {
+		t.Fatalf("expected Cellars at depth 1, got %q", got)
+	}
+	if got := BiomeForDepth(0).Name; got != "The Cellars" {
+		t.Fatalf("expected depth 0 clamped to Cellars, got %q", got)
+	}
+
+	for i := 1; i < len(Biomes); i++ {
+		prev, cur := Biomes[i-1], Biomes[i]
+		tr := BiomeForDepth(cur.MinDepth)
+		if !strings.Contains(tr.Name, cur.Name) || tr.Name == cur.Name {
+			t.Fatalf("expected transition into %s at depth %d, got %q", cur.Name, cur.MinDepth, tr.Name)
+		}
+		if tr.Palette.Wall != cur.Palette.Wall || tr.Palette.Floor != prev.Palette.Floor {
+			t.Fatalf("transition into %s should blend palettes, got %+v", cur.Name, tr.Palette)
+		}
+		if got := BiomeForDepth(cur.MinDepth + 1).Name; got != cur.Name {
+			t.Fatalf("expected %s after its transition floor, got %q", cur.Name, got)
+		}
+	}
+	if got := BiomeForDepth(500).Name; got != Biomes[len(Biomes)-1].Name {
+		t.Fatalf("expected deepest biome far down, got %q", got)
+	}
+}
+
+func TestFloorManagerAppliesBiome(t *testing.T) {
+	fm := NewFloorManagerWithSize(24, 24)
+	fm.Generator.WithSeed(11)
+	for depth := 1; depth <= 50; depth++ {
+		f := fm.TeleportToDepth(depth)
+		want := BiomeForDepth(depth)
+		if f.Biome.Name != want.Name {
+			t.Fatalf("depth %d: expected biome %q, got %q", depth, want.Name, f.Biome.Name)
+		}
+		if fm.Generator.Algorithm != want.Algorithm {
+			t.Fatalf("depth %d: expected algorithm %d, got %d", depth, want.Algorithm, fm.Generator.Algorithm)
+		}
+		reachable, stairsReached := floodFillCount(f.Map, f.SpawnPos)
+		if !stairsReached || reachable != countPassable(f.Map) {
+			t.Fatalf("depth %d: floor not fully connected", depth)
+		}
+	}
+}
@@ -1,10 +1,16 @@
 package world
 
+import "game/events"
+
 const (
 	corruptionStartDepth = 10
 	corruptionMaxDepth   = 50
 )
 
+// CorruptionThresholds are the levels that publish CorruptionThresholdCrossed
+// when Update carries the level up through them.
+var CorruptionThresholds = []float64{0.25, 0.50, 0.65, 0.90}
+
 type Corruption struct {
 	Level float64 // 0.0 to 1.0
 	Bias  float64 // additive override, -1.0 to 1.0 (clamped in GetLevel)
@@ -12,6 +18,11 @@ type Corruption struct {
 	Ticks int     // Frame counter for animation
 	// Exposure accumulates from passive effects like Watchers.
 	Exposure float64
+	// Events, when set, receives CorruptionThresholdCrossed from Update.
+	Events *events.Bus
+
+	// lastLevel is GetLevel as of the previous Update.
+	lastLevel float64
 }
 
 func NewCorruption() *Corruption {
@@ -26,6 +37,14 @@ func (c *Corruption) Update(depth int) {
 	c.Ticks++
 	c.Depth = depth
 	c.Level = c.calculateLevel(depth)
+
+	prev, cur := c.lastLevel, c.GetLevel()
+	c.lastLevel = cur
+	for _, t := range CorruptionThresholds {
+		if prev < t && cur >= t {
+			c.Events.Publish(events.CorruptionThresholdCrossed{Threshold: t, Level: cur, Depth: depth})
+		}
+	}
 }
 
 func (c *Corruption) GetLevel() float64 {
@@ -35,6 +54,15 @@ func (c *Corruption) GetLevel() float64 {
 	return clamp01(c.Level + c.Bias + c.Exposure)
 }
 
+// BaseLevelAt returns the level a floor at depth starts from, leaving out
+// bias and exposure so it depends on depth alone.
+func (c *Corruption) BaseLevelAt(depth int) float64 {
+	if c == nil {
+		return 0
+	}
+	return c.calculateLevel(depth)
+}
+
 func (c *Corruption) GetBias() float64 {
 	if c == nil {
 		return 0
@@ -49,6 +77,15 @@ func (c *Corruption) AdjustBias(delta float64) {
 	c.Bias = clampNeg1To1(c.Bias + delta)
 }
 
+// SetLevel sets the bias so GetLevel reads level at the current depth and
+// exposure. Later exposure still adds on top.
+func (c *Corruption) SetLevel(level float64) {
+	if c == nil {
+		return
+	}
+	c.Bias = clampNeg1To1(clamp01(level) - c.Level - c.Exposure)
+}
+
 func (c *Corruption) AddExposure(delta float64) {
 	if c == nil {
 		return
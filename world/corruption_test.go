@@ -3,6 +3,8 @@ package world
 import (
 	"math"
 	"testing"
+
+	"game/events"
 )
 
 func TestCorruptionCalculateLevel(t *testing.T) {
@@ -96,3 +98,63 @@ func TestCorruptionExposureAddsToLevel(t *testing.T) {
 		t.Fatalf("expected level clamped to 1.0, got %f", got)
 	}
 }
+
+func TestCorruptionBaseLevelAtIgnoresBiasAndExposure(t *testing.T) {
+	c := NewCorruption()
+	if c.BaseLevelAt(5) != 0 {
+		t.Fatalf("expected no corruption above depth %d", corruptionStartDepth)
+	}
+	want := c.calculateLevel(30)
+	c.AdjustBias(0.1)
+	c.AddExposure(0.05)
+	if got := c.BaseLevelAt(30); got != want {
+		t.Fatalf("expected %f at depth 30, got %f", want, got)
+	}
+	var nilCorruption *Corruption
+	if nilCorruption.BaseLevelAt(30) != 0 {
+		t.Fatal("expected nil corruption to report 0")
+	}
+}
+
+func TestCorruptionUpdatePublishesThresholdCrossings(t *testing.T) {
+	c := NewCorruption()
+	c.Events = events.NewBus()
+	var crossed []float64
+	events.Subscribe(c.Events, func(e events.CorruptionThresholdCrossed) {
+		crossed = append(crossed, e.Threshold)
+	})
+
+	c.Update(1)
+	c.AdjustBias(0.6)
+	c.Update(1) // 0 -> 0.6 crosses 0.25 and 0.50
+	c.Update(1) // no change
+	c.AdjustBias(-0.5)
+	c.Update(1) // falling never publishes
+	c.AdjustBias(0.2)
+	c.Update(1) // 0.1 -> 0.3 crosses 0.25 again
+
+	want := []float64{0.25, 0.50, 0.25}
+	if len(crossed) != len(want) {
+		t.Fatalf("expected %v, got %v", want, crossed)
+	}
+	for i := range want {
+		if crossed[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, crossed)
+		}
+	}
+}
+
+func TestCorruptionSetLevelOverridesDepthAndExposure(t *testing.T) {
+	c := NewCorruption()
+	c.Update(30) // depth level 0.5
+	c.AddExposure(0.1)
+
+	c.SetLevel(0.8)
+	if got := c.GetLevel(); math.Abs(got-0.8) > 1e-9 {
+		t.Fatalf("expected level 0.8, got %f", got)
+	}
+	c.SetLevel(0)
+	if got := c.GetLevel(); math.Abs(got) > 1e-9 {
+		t.Fatalf("expected level 0, got %f", got)
+	}
+}
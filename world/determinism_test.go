@@ -0,0 +1,75 @@
+package world
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Daily challenges and replays rely on floors being a pure function of the
+// generator seed and depth.
+func TestFloorGenerationIsDeterministic(t *testing.T) {
+	for _, seed := range []int64{1, 42, -7, 1 << 40} {
+		a := NewFloorManagerWithSize(32, 32)
+		a.Generator.WithSeed(seed)
+		b := NewFloorManagerWithSize(32, 32)
+		b.Generator.WithSeed(seed)
+
+		for _, depth := range []int{1, 9, 10, 12, 20, 27, 35, 45, 60} {
+			fa := a.TeleportToDepth(depth)
+			fb := b.TeleportToDepth(depth)
+			if !reflect.DeepEqual(fa.Map.Cells, fb.Map.Cells) || !reflect.DeepEqual(fa.Map.Portals, fb.Map.Portals) {
+				t.Fatalf("seed %d depth %d: maps differ", seed, depth)
+			}
+			if fa.SpawnPos != fb.SpawnPos || fa.StairsPos != fb.StairsPos || fa.Stats != fb.Stats {
+				t.Fatalf("seed %d depth %d: spawn/stairs/stats differ", seed, depth)
+			}
+			if !reflect.DeepEqual(fa.Notes, fb.Notes) || !reflect.DeepEqual(fa.Portals, fb.Portals) || !reflect.DeepEqual(fa.Illusions, fb.Illusions) {
+				t.Fatalf("seed %d depth %d: placements differ", seed, depth)
+			}
+			if !reflect.DeepEqual(fa.Watchers.Watchers, fb.Watchers.Watchers) {
+				t.Fatalf("seed %d depth %d: watchers differ", seed, depth)
+			}
+		}
+	}
+}
+
+func TestFloorGenerationIgnoresHistory(t *testing.T) {
+	// Reaching a depth by descending or by teleporting gives the same floor.
+	walked := NewFloorManagerWithSize(24, 24)
+	walked.Generator.WithSeed(5)
+	walked.GenerateFirstFloor()
+	for i := 1; i < 15; i++ {
+		walked.DescendToNextFloor()
+	}
+	jumped := NewFloorManagerWithSize(24, 24)
+	jumped.Generator.WithSeed(5)
+	jumped.TeleportToDepth(15)
+
+	if !reflect.DeepEqual(walked.CurrentFloor.Map.Cells, jumped.CurrentFloor.Map.Cells) {
+		t.Fatal("expected depth 15 to be identical however it was reached")
+	}
+}
+
+func TestFloorGenerationIgnoresCorruptionBiasAndExposure(t *testing.T) {
+	// Illusions follow the depth's base corruption, so a cheat or Watcher
+	// exposure before descending cannot change the floor.
+	a := NewFloorManagerWithSize(32, 32)
+	a.Generator.WithSeed(8)
+	a.Corruption = NewCorruption()
+	b := NewFloorManagerWithSize(32, 32)
+	b.Generator.WithSeed(8)
+	b.Corruption = NewCorruption()
+	b.Corruption.AdjustBias(0.4)
+	b.Corruption.AddExposure(0.3)
+
+	for _, depth := range []int{20, 45} {
+		fa := a.TeleportToDepth(depth)
+		fb := b.TeleportToDepth(depth)
+		if depth == 45 && len(fa.Illusions) == 0 {
+			t.Fatalf("expected illusions at depth %d", depth)
+		}
+		if !reflect.DeepEqual(fa.Map.Cells, fb.Map.Cells) || !reflect.DeepEqual(fa.Illusions, fb.Illusions) {
+			t.Fatalf("depth %d: floors differ with different bias and exposure", depth)
+		}
+	}
+}
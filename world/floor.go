@@ -5,6 +5,8 @@ import (
 
 	"game/engine"
 	"game/entities"
+	"game/events"
+	"game/theme"
 )
 
 type Floor struct {
@@ -13,6 +15,85 @@ type Floor struct {
 	SpawnPos  Point
 	StairsPos Point
 	Watchers  *entities.WatcherManager
+	Notes     []NotePlacement
+	Portals   []PortalPlacement
+	Illusions []IllusionPlacement
+	// Shifter moves unobserved walls at high corruption.
+	Shifter *Shifter
+	// Biome is the depth band this floor belongs to.
+	Biome Biome
+	// Theme is the theme pack resolved at this floor's depth.
+	Theme *theme.Tables
+	// Stats measures the layout as generated.
+	Stats FloorStats
+}
+
+// FloorStats are layout measurements taken when a floor is generated, for
+// run logs and tuning.
+type FloorStats struct {
+	// OpenCells counts walkable cells.
+	OpenCells int
+	// StairsDistance is the shortest walk from spawn to stairs in cells, or
+	// -1 if the stairs are unreachable.
+	StairsDistance int
+}
+
+func measureFloor(m *engine.GameMap, spawn, stairs Point) FloorStats {
+	stats := FloorStats{StairsDistance: -1}
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			if !m.IsWall(x, y) {
+				stats.OpenCells++
+			}
+		}
+	}
+	if _, dist := bfs(m, spawn); dist != nil {
+		if d, ok := dist[stairs]; ok {
+			stats.StairsDistance = d
+		}
+	}
+	return stats
+}
+
+func (f *Floor) applyTheme(pack *theme.Pack) {
+	if pack == nil {
+		pack = theme.Default()
+	}
+	f.Theme = pack.Resolve(f.Depth)
+	if f.Watchers != nil {
+		f.Watchers.Theme = f.Theme
+	}
+}
+
+// NoteAt returns the note placed at (x, y), if any.
+func (f *Floor) NoteAt(x, y int) (NotePlacement, bool) {
+	if f == nil {
+		return NotePlacement{}, false
+	}
+	for _, n := range f.Notes {
+		if n.Pos.X == x && n.Pos.Y == y {
+			return n, true
+		}
+	}
+	return NotePlacement{}, false
+}
+
+// TakeNote removes the note at (x, y) from the floor, clearing its cell.
+func (f *Floor) TakeNote(x, y int) (NotePlacement, bool) {
+	if f == nil {
+		return NotePlacement{}, false
+	}
+	for i, n := range f.Notes {
+		if n.Pos.X != x || n.Pos.Y != y {
+			continue
+		}
+		f.Notes = append(f.Notes[:i:i], f.Notes[i+1:]...)
+		if f.Map != nil && f.Map.GetCell(x, y) == engine.CellNote {
+			f.Map.Cells[y][x] = engine.CellEmpty
+		}
+		return n, true
+	}
+	return NotePlacement{}, false
 }
 
 type FloorManager struct {
@@ -20,6 +101,16 @@ type FloorManager struct {
 	Generator    *FloorGenerator
 	MapWidth     int
 	MapHeight    int
+	// Theme is the pack floors resolve their tables from; nil uses the
+	// embedded default.
+	Theme *theme.Pack
+	// Corruption, when set, scales each new floor's illusions by the base
+	// level at its depth. Bias and exposure are left out so a floor depends
+	// only on the seed and depth.
+	Corruption *Corruption
+	// Events, when set, receives FloorEntered for every new floor and is
+	// handed to each floor's Watchers.
+	Events *events.Bus
 }
 
 const (
@@ -47,6 +138,14 @@ func NewFloorManagerWithSize(width, height int) *FloorManager {
 	}
 }
 
+// SetTheme switches theme packs, re-resolving the current floor's tables.
+func (fm *FloorManager) SetTheme(pack *theme.Pack) {
+	fm.Theme = pack
+	if fm.CurrentFloor != nil {
+		fm.CurrentFloor.applyTheme(pack)
+	}
+}
+
 func (fm *FloorManager) GenerateFirstFloor() *Floor {
 	return fm.generateAtDepth(1)
 }
@@ -86,17 +185,46 @@ func (fm *FloorManager) generateAtDepth(depth int) *Floor {
 		fm.Generator = NewFloorGenerator(w, h, depth)
 	}
 
+	biome := BiomeForDepth(depth)
 	fm.Generator.Depth = depth
+	fm.Generator.Algorithm = biome.Algorithm
+	fm.Generator.Corruption = fm.Corruption.BaseLevelAt(depth)
+	from := fm.GetCurrentDepth()
 	m := fm.Generator.Generate()
 
-	watchers := entities.NewWatcherManager(depth, fm.Generator.Seed, engine.DefaultFOV)
+	watchers := entities.NewWatcherManagerScaled(depth, fm.Generator.Seed, engine.DefaultFOV, biome.WatcherDensity)
+	watchers.Events = fm.Events
 	f := &Floor{
 		Map:       m,
 		Depth:     depth,
 		SpawnPos:  fm.Generator.SpawnPos,
 		StairsPos: fm.Generator.StairsPos,
 		Watchers:  watchers,
+		Notes:     fm.Generator.Notes,
+		Portals:   fm.Generator.Portals,
+		Illusions: fm.Generator.Illusions,
+		Shifter:   NewShifter(fm.Generator.Seed, depth),
+		Biome:     biome,
+		Stats:     measureFloor(m, fm.Generator.SpawnPos, fm.Generator.StairsPos),
 	}
+	f.applyTheme(fm.Theme)
 	fm.CurrentFloor = f
+	fm.Events.Publish(events.FloorEntered{Depth: depth, From: from, Biome: biome.Name, Seed: fm.Generator.Seed})
 	return f
 }
+
+// Listen subscribes fm to bus: stepping onto the current floor's stairs
+// descends. New floors are announced on bus.
+func (fm *FloorManager) Listen(bus *events.Bus) {
+	fm.Events = bus
+	if f := fm.CurrentFloor; f != nil && f.Watchers != nil {
+		f.Watchers.Events = bus
+	}
+	events.Subscribe(bus, func(e events.PlayerMoved) {
+		f := fm.CurrentFloor
+		if f == nil || f.Map == nil || f.Map.GetCell(e.X, e.Y) != engine.CellStairs {
+			return
+		}
+		fm.DescendToNextFloor()
+	})
+}
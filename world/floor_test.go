@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"game/engine"
+	"game/events"
+	"game/theme"
 )
 
 func TestFloorManagerGenerateFirstFloor(t *testing.T) {
@@ -77,3 +79,105 @@ func TestFloorManagerWithSizeUsesRequestedDimensions(t *testing.T) {
 		t.Fatalf("expected map 16x20, got %dx%d", f.Map.Width, f.Map.Height)
 	}
 }
+
+func TestFloorTakeNoteClearsCell(t *testing.T) {
+	fm := NewFloorManagerWithSize(24, 24)
+	var f *Floor
+	for seed := int64(1); seed <= 50; seed++ {
+		fm.Generator.WithSeed(seed)
+		f = fm.TeleportToDepth(3)
+		if len(f.Notes) > 0 {
+			break
+		}
+	}
+	if len(f.Notes) == 0 {
+		t.Fatal("expected a floor with a note")
+	}
+
+	pos := f.Notes[0].Pos
+	if _, ok := f.NoteAt(pos.X, pos.Y); !ok {
+		t.Fatal("expected NoteAt to find the note")
+	}
+	n, ok := f.TakeNote(pos.X, pos.Y)
+	if !ok || n.Pos != pos {
+		t.Fatalf("expected to take note at %+v, got %+v ok=%v", pos, n, ok)
+	}
+	if f.Map.GetCell(pos.X, pos.Y) != engine.CellEmpty {
+		t.Fatal("expected note cell cleared")
+	}
+	if _, ok := f.TakeNote(pos.X, pos.Y); ok {
+		t.Fatal("expected note to be gone after taking it")
+	}
+}
+
+func TestFloorThemeResolvedPerDepth(t *testing.T) {
+	fm := NewFloorManagerWithSize(16, 16)
+	fm.Generator.WithSeed(3)
+	f := fm.TeleportToDepth(20)
+	if f.Theme == nil || len(f.Theme.Whispers) == 0 {
+		t.Fatal("expected default theme tables on the floor")
+	}
+	if f.Watchers.Theme != f.Theme {
+		t.Fatal("expected watchers to share the floor theme")
+	}
+
+	custom, err := theme.Parse([]byte(`{"bands":[{"min_depth":20,"whispers":[{"text":"custom"}]}]}`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	fm.SetTheme(theme.Default().Layer(custom))
+	if got := fm.CurrentFloor.Theme.Whispers[0].Value; got != "custom" {
+		t.Fatalf("expected current floor re-themed, got %q", got)
+	}
+	if f := fm.TeleportToDepth(5); f.Theme.Whispers[0].Value == "custom" {
+		t.Fatal("expected custom band limited to depth 20+")
+	}
+}
+
+func TestFloorManagerListenDescendsOnStairs(t *testing.T) {
+	fm := NewFloorManagerWithSize(16, 16)
+	fm.Generator.WithSeed(42)
+	f1 := fm.GenerateFirstFloor()
+
+	bus := events.NewBus()
+	var entered []events.FloorEntered
+	events.Subscribe(bus, func(e events.FloorEntered) { entered = append(entered, e) })
+	fm.Listen(bus)
+	if f1.Watchers.Events != bus {
+		t.Fatal("expected the current floor's watchers to publish on the bus")
+	}
+
+	bus.Publish(events.PlayerMoved{X: f1.SpawnPos.X, Y: f1.SpawnPos.Y})
+	if fm.CurrentFloor != f1 || len(entered) != 0 {
+		t.Fatal("expected no descent off the stairs")
+	}
+	bus.Publish(events.PlayerMoved{X: f1.StairsPos.X, Y: f1.StairsPos.Y})
+	if fm.GetCurrentDepth() != 2 {
+		t.Fatalf("expected descent to depth 2, got %d", fm.GetCurrentDepth())
+	}
+	if len(entered) != 1 || entered[0].Depth != 2 || entered[0].From != 1 || entered[0].Biome != fm.CurrentFloor.Biome.Name {
+		t.Fatalf("unexpected FloorEntered events %+v", entered)
+	}
+	if fm.CurrentFloor.Watchers.Events != bus {
+		t.Fatal("expected new floors' watchers to publish on the bus")
+	}
+}
+
+func TestFloorStatsMeasureLayout(t *testing.T) {
+	fm := NewFloorManagerWithSize(20, 20)
+	fm.Generator.WithSeed(8)
+	f := fm.GenerateFirstFloor()
+
+	if got := countPassable(f.Map); f.Stats.OpenCells != got {
+		t.Fatalf("expected %d open cells, got %d", got, f.Stats.OpenCells)
+	}
+	_, dist := bfs(f.Map, f.SpawnPos)
+	if want := dist[f.StairsPos]; f.Stats.StairsDistance != want || want <= 0 {
+		t.Fatalf("expected stairs distance %d, got %d", want, f.Stats.StairsDistance)
+	}
+
+	m := newSolidWallMap(3, 1)
+	if s := measureFloor(m, Point{}, Point{X: 2}); s.OpenCells != 0 || s.StairsDistance != -1 {
+		t.Fatalf("expected unreachable stairs on a solid map, got %+v", s)
+	}
+}
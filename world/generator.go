@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"game/engine"
+	"game/lore"
 )
 
 type Point struct {
@@ -14,16 +15,28 @@ type Point struct {
 
 // FloorGenerator generates a new map for a given depth.
 //
-// It uses a connected "drunk walk" carve so all empty tiles are reachable
-// from SpawnPos by construction, then places StairsPos at the farthest
-// reachable tile.
+// The default Algorithm is a connected "drunk walk" carve; rooms and caves
+// drop any pocket not reachable from SpawnPos, so every empty tile is
+// reachable either way. StairsPos is placed at the farthest reachable tile.
 type FloorGenerator struct {
 	Width, Height int
 	Depth         int
 	Seed          int64
+	Algorithm     Algorithm
+	// Corruption is the level expected on the floor, scaling illusion density.
+	Corruption float64
 
 	SpawnPos  Point
 	StairsPos Point
+	Notes     []NotePlacement
+	Portals   []PortalPlacement
+	Illusions []IllusionPlacement
+}
+
+// NotePlacement records which lore note sits on which cell.
+type NotePlacement struct {
+	Pos    Point
+	NoteID string
 }
 
 const (
@@ -37,6 +50,12 @@ const (
 	turnChanceIncrease  = 0.45
 	maxStepsPerCell     = 12
 	minTargetOpenCells  = 2
+	noteChance          = 0.5
+	roomAreaPerAttempt  = 64
+	roomMinSize         = 3
+	roomSizeRange       = 4
+	caveFillChance      = 0.45
+	caveSmoothPasses    = 4
 )
 
 func NewFloorGenerator(width, height, depth int) *FloorGenerator {
@@ -76,12 +95,57 @@ func (g *FloorGenerator) Generate() *engine.GameMap {
 		targetOpenCells = minTargetOpenCells
 	}
 
-	turnChance := g.turnChance()
+	switch g.Algorithm {
+	case AlgorithmRooms:
+		carveRooms(m, rng, spawn)
+		openCells = keepReachable(m, spawn)
+	case AlgorithmCaves:
+		carveCaves(m, rng, spawn)
+		openCells = keepReachable(m, spawn)
+		// Small pockets get a walk carved outward so the floor stays playable.
+		if openCells < targetOpenCells/2 {
+			openCells = carveDrunkWalk(m, rng, spawn, openCells, targetOpenCells/2, g.turnChance())
+		}
+	default:
+		openCells = carveDrunkWalk(m, rng, spawn, openCells, targetOpenCells, g.turnChance())
+	}
+
+	// Ensure at least two reachable tiles so stairs can be distinct from spawn.
+	if openCells < 2 {
+		for _, d := range []Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			nx, ny := spawn.X+d.X, spawn.Y+d.Y
+			if nx > 0 && nx < w-1 && ny > 0 && ny < h-1 {
+				m.Cells[ny][nx] = engine.CellEmpty
+				openCells++
+				break
+			}
+		}
+	}
+
+	stairs := farthestReachableCell(m, spawn)
+	if stairs == spawn {
+		// Extremely small or unlucky maps: pick any other reachable cell.
+		stairs = firstReachableDifferentCell(m, spawn)
+	}
+	m.Cells[stairs.Y][stairs.X] = engine.CellStairs
+
+	g.SpawnPos = spawn
+	g.StairsPos = stairs
+	g.Notes = g.placeNotes(m, rng, spawn, stairs)
+	g.Portals = g.placePortals(m, rng, spawn, stairs)
+	g.Illusions = g.placeIllusions(m, spawn, stairs)
+	return m
+}
+
+// carveDrunkWalk walks from spawn carving walls until target cells are open,
+// returning the new open count. Every carved cell stays connected to spawn.
+func carveDrunkWalk(m *engine.GameMap, rng *rand.Rand, spawn Point, openCells, target int, turnChance float64) int {
+	w, h := m.Width, m.Height
 	x, y := spawn.X, spawn.Y
 	dx, dy := randomDir(rng)
 	maxSteps := w * h * maxStepsPerCell
 
-	for steps := 0; steps < maxSteps && openCells < targetOpenCells; steps++ {
+	for steps := 0; steps < maxSteps && openCells < target; steps++ {
 		if rng.Float64() < turnChance {
 			dx, dy = randomDir(rng)
 		}
@@ -98,29 +162,133 @@ func (g *FloorGenerator) Generate() *engine.GameMap {
 			openCells++
 		}
 	}
+	return openCells
+}
 
-	// Ensure at least two reachable tiles so stairs can be distinct from spawn.
-	if openCells < 2 {
-		for _, d := range []Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
-			nx, ny := spawn.X+d.X, spawn.Y+d.Y
-			if nx > 0 && nx < w-1 && ny > 0 && ny < h-1 {
-				m.Cells[ny][nx] = engine.CellEmpty
-				openCells++
-				break
+// carveRooms carves a room around spawn plus randomly placed rooms, each
+// joined to the previous one by an L-shaped corridor.
+func carveRooms(m *engine.GameMap, rng *rand.Rand, spawn Point) {
+	w, h := m.Width, m.Height
+	carveRect(m, spawn.X-1, spawn.Y-1, spawn.X+1, spawn.Y+1)
+	prev := spawn
+
+	attempts := w * h / roomAreaPerAttempt
+	if attempts < 1 {
+		attempts = 1
+	}
+	for i := 0; i < attempts; i++ {
+		rw := clampInt(roomMinSize+rng.Intn(roomSizeRange), 1, w-2)
+		rh := clampInt(roomMinSize+rng.Intn(roomSizeRange), 1, h-2)
+		x0 := 1 + rng.Intn(w-1-rw)
+		y0 := 1 + rng.Intn(h-1-rh)
+		carveRect(m, x0, y0, x0+rw-1, y0+rh-1)
+
+		center := Point{X: x0 + rw/2, Y: y0 + rh/2}
+		if rng.Intn(2) == 0 {
+			carveRect(m, prev.X, prev.Y, center.X, prev.Y)
+			carveRect(m, center.X, prev.Y, center.X, center.Y)
+		} else {
+			carveRect(m, prev.X, prev.Y, prev.X, center.Y)
+			carveRect(m, prev.X, center.Y, center.X, center.Y)
+		}
+		prev = center
+	}
+}
+
+// carveRect opens every interior cell in the rectangle spanning both corners.
+func carveRect(m *engine.GameMap, x0, y0, x1, y1 int) {
+	if x0 > x1 {
+		x0, x1 = x1, x0
+	}
+	if y0 > y1 {
+		y0, y1 = y1, y0
+	}
+	for y := clampInt(y0, 1, m.Height-2); y <= clampInt(y1, 1, m.Height-2); y++ {
+		for x := clampInt(x0, 1, m.Width-2); x <= clampInt(x1, 1, m.Width-2); x++ {
+			m.Cells[y][x] = engine.CellEmpty
+		}
+	}
+}
+
+// carveCaves seeds the interior with random walls and smooths it into
+// caverns, keeping the area around spawn open.
+func carveCaves(m *engine.GameMap, rng *rand.Rand, spawn Point) {
+	w, h := m.Width, m.Height
+	for y := 1; y < h-1; y++ {
+		for x := 1; x < w-1; x++ {
+			if rng.Float64() >= caveFillChance {
+				m.Cells[y][x] = engine.CellEmpty
+			}
+		}
+	}
+	carveRect(m, spawn.X-1, spawn.Y-1, spawn.X+1, spawn.Y+1)
+
+	next := newSolidWallMap(w, h)
+	for pass := 0; pass < caveSmoothPasses; pass++ {
+		for y := 1; y < h-1; y++ {
+			for x := 1; x < w-1; x++ {
+				walls := 0
+				for dy := -1; dy <= 1; dy++ {
+					for dx := -1; dx <= 1; dx++ {
+						if (dx != 0 || dy != 0) && m.Cells[y+dy][x+dx] == engine.CellWall {
+							walls++
+						}
+					}
+				}
+				switch {
+				case walls >= 5:
+					next.Cells[y][x] = engine.CellWall
+				case walls <= 3:
+					next.Cells[y][x] = engine.CellEmpty
+				default:
+					next.Cells[y][x] = m.Cells[y][x]
+				}
+			}
+		}
+		m.Cells, next.Cells = next.Cells, m.Cells
+	}
+	m.Cells[spawn.Y][spawn.X] = engine.CellEmpty
+}
+
+// keepReachable walls off every open cell not reachable from spawn and
+// returns the number of open cells left.
+func keepReachable(m *engine.GameMap, spawn Point) int {
+	reachable := reachableCells(m, spawn)
+	keep := make(map[Point]bool, len(reachable))
+	for _, p := range reachable {
+		keep[p] = true
+	}
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			if m.Cells[y][x] != engine.CellWall && !keep[Point{X: x, Y: y}] {
+				m.Cells[y][x] = engine.CellWall
 			}
 		}
 	}
+	return len(reachable)
+}
 
-	stairs := farthestReachableCell(m, spawn)
-	if stairs == spawn {
-		// Extremely small or unlucky maps: pick any other reachable cell.
-		stairs = firstReachableDifferentCell(m, spawn)
+// placeNotes may drop a lore note for this depth on a reachable cell other
+// than spawn or stairs, marking it as CellNote.
+func (g *FloorGenerator) placeNotes(m *engine.GameMap, rng *rand.Rand, spawn, stairs Point) []NotePlacement {
+	candidates := lore.ForDepth(g.Depth)
+	if len(candidates) == 0 || rng.Float64() >= noteChance {
+		return nil
 	}
-	m.Cells[stairs.Y][stairs.X] = engine.CellStairs
+	note := candidates[rng.Intn(len(candidates))]
 
-	g.SpawnPos = spawn
-	g.StairsPos = stairs
-	return m
+	cells := make([]Point, 0, m.Width*m.Height)
+	for _, p := range reachableCells(m, spawn) {
+		if p != spawn && p != stairs {
+			cells = append(cells, p)
+		}
+	}
+	if len(cells) == 0 {
+		return nil
+	}
+	pos := cells[rng.Intn(len(cells))]
+	m.Cells[pos.Y][pos.X] = engine.CellNote
+	return []NotePlacement{{Pos: pos, NoteID: note.ID}}
 }
 
 func (g *FloorGenerator) targetOpenCells(w, h int) int {
@@ -170,87 +338,54 @@ func randomDir(rng *rand.Rand) (int, int) {
 	}
 }
 
-func farthestReachableCell(m *engine.GameMap, from Point) Point {
-	type node struct {
-		p    Point
-		dist int
-	}
-
-	visited := make([][]bool, m.Height)
-	for y := range visited {
-		visited[y] = make([]bool, m.Width)
-	}
-
-	q := make([]node, 0, m.Width*m.Height)
-	head := 0
-	q = append(q, node{p: from, dist: 0})
-	visited[from.Y][from.X] = true
-
-	best := from
-	bestDist := 0
+// bfs walks every cell reachable from `from` without crossing walls or
+// portals, returning them in visit order with their step distances.
+func bfs(m *engine.GameMap, from Point) ([]Point, map[Point]int) {
+	dist := map[Point]int{from: 0}
+	q := make([]Point, 0, m.Width*m.Height)
+	q = append(q, from)
 
-	for head < len(q) {
+	for head := 0; head < len(q); head++ {
 		cur := q[head]
-		head++
-
-		if cur.dist > bestDist {
-			bestDist = cur.dist
-			best = cur.p
-		}
-
 		for _, d := range []Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
-			nx, ny := cur.p.X+d.X, cur.p.Y+d.Y
-			if nx < 0 || nx >= m.Width || ny < 0 || ny >= m.Height {
-				continue
-			}
-			if visited[ny][nx] {
+			next := Point{X: cur.X + d.X, Y: cur.Y + d.Y}
+			if !m.IsValid(next.X, next.Y) || m.IsWall(next.X, next.Y) {
 				continue
 			}
-			if m.Cells[ny][nx] == engine.CellWall {
+			if _, seen := dist[next]; seen {
 				continue
 			}
-			visited[ny][nx] = true
-			q = append(q, node{p: Point{X: nx, Y: ny}, dist: cur.dist + 1})
+			dist[next] = dist[cur] + 1
+			q = append(q, next)
 		}
 	}
-
-	return best
+	return q, dist
 }
 
-func firstReachableDifferentCell(m *engine.GameMap, from Point) Point {
-	visited := make([][]bool, m.Height)
-	for y := range visited {
-		visited[y] = make([]bool, m.Width)
+// farthestReachableCell returns the first cell, in BFS order, at the greatest
+// walking distance from `from`.
+func farthestReachableCell(m *engine.GameMap, from Point) Point {
+	order, dist := bfs(m, from)
+	best := from
+	for _, p := range order {
+		if dist[p] > dist[best] {
+			best = p
+		}
 	}
+	return best
+}
 
-	q := make([]Point, 0, m.Width*m.Height)
-	head := 0
-	q = append(q, from)
-	visited[from.Y][from.X] = true
-
-	for head < len(q) {
-		cur := q[head]
-		head++
-		if cur != from {
-			return cur
-		}
+// reachableCells returns every cell reachable from `from`, in BFS order.
+func reachableCells(m *engine.GameMap, from Point) []Point {
+	order, _ := bfs(m, from)
+	return order
+}
 
-		for _, d := range []Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
-			nx, ny := cur.X+d.X, cur.Y+d.Y
-			if nx < 0 || nx >= m.Width || ny < 0 || ny >= m.Height {
-				continue
-			}
-			if visited[ny][nx] {
-				continue
-			}
-			if m.Cells[ny][nx] == engine.CellWall {
-				continue
-			}
-			visited[ny][nx] = true
-			q = append(q, Point{X: nx, Y: ny})
-		}
+func firstReachableDifferentCell(m *engine.GameMap, from Point) Point {
+	order, _ := bfs(m, from)
+	if len(order) > 1 {
+		return order[1]
 	}
-
 	return from
 }
 
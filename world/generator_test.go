@@ -1,9 +1,11 @@
 package world
 
 import (
+	"fmt"
 	"testing"
 
 	"game/engine"
+	"game/lore"
 )
 
 func TestFloorGeneratorDeterministicWithSeed(t *testing.T) {
@@ -91,7 +93,7 @@ func countPassable(m *engine.GameMap) int {
 	total := 0
 	for y := 0; y < m.Height; y++ {
 		for x := 0; x < m.Width; x++ {
-			if m.Cells[y][x] != engine.CellWall {
+			if !m.IsWall(x, y) {
 				total++
 			}
 		}
@@ -128,7 +130,7 @@ func floodFillCount(m *engine.GameMap, start Point) (reachable int, stairsReache
 			if visited[ny][nx] {
 				continue
 			}
-			if m.Cells[ny][nx] == engine.CellWall {
+			if m.IsWall(nx, ny) {
 				continue
 			}
 			visited[ny][nx] = true
@@ -138,3 +140,70 @@ func floodFillCount(m *engine.GameMap, start Point) (reachable int, stairsReache
 
 	return reachable, stairsReached
 }
+
+func TestFloorGeneratorPlacesNotesOnReachableCells(t *testing.T) {
+	placed := 0
+	for seed := int64(1); seed <= 20; seed++ {
+		g := NewFloorGenerator(24, 24, 5).WithSeed(seed)
+		m := g.Generate()
+
+		reachable := map[Point]bool{}
+		for _, p := range reachableCells(m, g.SpawnPos) {
+			reachable[p] = true
+		}
+		for _, n := range g.Notes {
+			placed++
+			if m.GetCell(n.Pos.X, n.Pos.Y) != engine.CellNote {
+				t.Fatalf("seed %d: note cell not marked at %+v", seed, n.Pos)
+			}
+			if n.Pos == g.SpawnPos || n.Pos == g.StairsPos {
+				t.Fatalf("seed %d: note placed on spawn or stairs at %+v", seed, n.Pos)
+			}
+			if !reachable[n.Pos] {
+				t.Fatalf("seed %d: note unreachable at %+v", seed, n.Pos)
+			}
+			if _, ok := lore.Get(n.NoteID); !ok {
+				t.Fatalf("seed %d: unknown note id %q", seed, n.NoteID)
+			}
+		}
+	}
+	if placed == 0 {
+		t.Fatal("expected at least one note across 20 seeds")
+	}
+}
+
+func TestFloorGeneratorAlgorithmsKeepFloorsConnected(t *testing.T) {
+	for _, alg := range []Algorithm{AlgorithmDrunkWalk, AlgorithmRooms, AlgorithmCaves} {
+		for _, size := range []int{5, 16, 32} {
+			for seed := int64(1); seed <= 20; seed++ {
+				g := NewFloorGenerator(size, size, 25).WithSeed(seed)
+				g.Algorithm = alg
+				m := g.Generate()
+
+				if g.StairsPos == g.SpawnPos {
+					t.Fatalf("alg %d size %d seed %d: stairs on spawn", alg, size, seed)
+				}
+				reachable, stairsReached := floodFillCount(m, g.SpawnPos)
+				if !stairsReached {
+					t.Fatalf("alg %d size %d seed %d: stairs unreachable", alg, size, seed)
+				}
+				if total := countPassable(m); reachable != total {
+					t.Fatalf("alg %d size %d seed %d: reachable=%d total=%d", alg, size, seed, reachable, total)
+				}
+			}
+		}
+	}
+}
+
+func TestFloorGeneratorAlgorithmsDiffer(t *testing.T) {
+	layouts := map[string]Algorithm{}
+	for _, alg := range []Algorithm{AlgorithmDrunkWalk, AlgorithmRooms, AlgorithmCaves} {
+		g := NewFloorGenerator(32, 32, 25).WithSeed(7)
+		g.Algorithm = alg
+		key := fmt.Sprint(g.Generate().Cells)
+		if prev, ok := layouts[key]; ok {
+			t.Fatalf("algorithms %d and %d produced the same layout", prev, alg)
+		}
+		layouts[key] = alg
+	}
+}
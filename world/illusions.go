@@ -0,0 +1,83 @@
+package world
+
+import (
+	"math"
+	"math/rand"
+
+	"game/engine"
+)
+
+const (
+	// illusionStartLevel is the corruption level where illusions begin.
+	illusionStartLevel = 0.20
+	// illusionMaxFraction is the share of interior cells turned into
+	// illusions at full corruption.
+	illusionMaxFraction = 0.04
+	illusionSeedSalt    = int64(0x111051)
+)
+
+// IllusionPlacement records a cell turned into CellFakeWall or CellFakeOpen.
+type IllusionPlacement struct {
+	Pos  Point
+	Cell int
+}
+
+// placeIllusions turns some open cells into walls you can walk through and
+// some walls beside open floor into openings that block, more of both the
+// higher the corruption. Fake walls stay walkable and fake openings were
+// already walls, so connectivity is unchanged. A dedicated rng keeps the
+// choice deterministic per floor seed without disturbing other placement.
+func (g *FloorGenerator) placeIllusions(m *engine.GameMap, spawn, stairs Point) []IllusionPlacement {
+	if g.Corruption < illusionStartLevel {
+		return nil
+	}
+	rng := rand.New(rand.NewSource(g.Seed + int64(g.Depth)*seedDepthMultiplier + illusionSeedSalt))
+
+	interior := (m.Width - 2) * (m.Height - 2)
+	strength := (g.Corruption - illusionStartLevel) / (1 - illusionStartLevel)
+	count := int(math.Round(clamp01(strength) * illusionMaxFraction * float64(interior)))
+	if count < 1 {
+		count = 1
+	}
+
+	protected := map[Point]bool{spawn: true, stairs: true}
+	for _, n := range g.Notes {
+		protected[n.Pos] = true
+	}
+	protectPortals(protected, g.Portals)
+
+	var placed []IllusionPlacement
+	for i := 0; i < count; i++ {
+		for try := 0; try < shiftCandidateTries; try++ {
+			p := Point{X: 1 + rng.Intn(m.Width-2), Y: 1 + rng.Intn(m.Height-2)}
+			if protected[p] {
+				continue
+			}
+			cell, ok := illusionFor(m, p)
+			if !ok {
+				continue
+			}
+			m.Cells[p.Y][p.X] = cell
+			protected[p] = true
+			placed = append(placed, IllusionPlacement{Pos: p, Cell: cell})
+			break
+		}
+	}
+	return placed
+}
+
+// illusionFor returns the illusion p can become: open floor becomes a fake
+// wall, and a wall next to open floor becomes a fake opening.
+func illusionFor(m *engine.GameMap, p Point) (int, bool) {
+	switch m.Cells[p.Y][p.X] {
+	case engine.CellEmpty:
+		return engine.CellFakeWall, true
+	case engine.CellWall:
+		for _, d := range []Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			if m.GetCell(p.X+d.X, p.Y+d.Y) == engine.CellEmpty {
+				return engine.CellFakeOpen, true
+			}
+		}
+	}
+	return 0, false
+}
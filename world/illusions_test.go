@@ -0,0 +1,69 @@
+package world
+
+import (
+	"testing"
+
+	"game/engine"
+)
+
+func generateWithCorruption(seed int64, depth int, level float64) (*FloorGenerator, *engine.GameMap) {
+	g := NewFloorGenerator(32, 32, depth).WithSeed(seed)
+	g.Corruption = level
+	return g, g.Generate()
+}
+
+func TestIllusionsScaleWithCorruption(t *testing.T) {
+	if g, _ := generateWithCorruption(4, 30, illusionStartLevel-0.01); len(g.Illusions) != 0 {
+		t.Fatalf("expected no illusions below %.2f corruption, got %d", illusionStartLevel, len(g.Illusions))
+	}
+	low, _ := generateWithCorruption(4, 30, 0.3)
+	high, _ := generateWithCorruption(4, 30, 1.0)
+	if len(low.Illusions) == 0 || len(high.Illusions) <= len(low.Illusions) {
+		t.Fatalf("expected more illusions at higher corruption (low=%d high=%d)", len(low.Illusions), len(high.Illusions))
+	}
+}
+
+func TestIllusionsKeepFloorConnectedAndDeterministic(t *testing.T) {
+	kinds := map[int]bool{}
+	for seed := int64(1); seed <= 20; seed++ {
+		g, m := generateWithCorruption(seed, 40, 1.0)
+		reachable, stairsReached := floodFillCount(m, g.SpawnPos)
+		if !stairsReached || reachable != countPassable(m) {
+			t.Fatalf("seed %d: illusions broke connectivity", seed)
+		}
+		for _, il := range g.Illusions {
+			if m.GetCell(il.Pos.X, il.Pos.Y) != il.Cell {
+				t.Fatalf("seed %d: illusion %+v not on the map", seed, il)
+			}
+			if il.Pos == g.SpawnPos || il.Pos == g.StairsPos {
+				t.Fatalf("seed %d: illusion placed on spawn or stairs", seed)
+			}
+			kinds[il.Cell] = true
+		}
+
+		again, _ := generateWithCorruption(seed, 40, 1.0)
+		if len(again.Illusions) != len(g.Illusions) {
+			t.Fatalf("seed %d: illusions not deterministic", seed)
+		}
+		for i := range g.Illusions {
+			if g.Illusions[i] != again.Illusions[i] {
+				t.Fatalf("seed %d: illusion %d differs", seed, i)
+			}
+		}
+	}
+	if !kinds[engine.CellFakeWall] || !kinds[engine.CellFakeOpen] {
+		t.Fatalf("expected both illusion kinds, got %v", kinds)
+	}
+}
+
+func TestFloorManagerUsesCorruptionForIllusions(t *testing.T) {
+	fm := NewFloorManagerWithSize(32, 32)
+	fm.Generator.WithSeed(8)
+	if f := fm.TeleportToDepth(45); len(f.Illusions) != 0 {
+		t.Fatal("expected no illusions without a corruption source")
+	}
+	fm.Corruption = NewCorruption()
+	if f := fm.TeleportToDepth(45); len(f.Illusions) == 0 {
+		t.Fatal("expected illusions at depth 45 with corruption tracked")
+	}
+}
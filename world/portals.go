@@ -0,0 +1,173 @@
+package world
+
+import (
+	"math/rand"
+
+	"game/engine"
+)
+
+// PortalKind describes the shape of a placed portal link.
+type PortalKind int
+
+const (
+	// PortalFold links two faces both ways between cells far apart on foot,
+	// so a short step covers a long corridor.
+	PortalFold PortalKind = iota
+	// PortalTwist links two faces both ways but turns you a quarter turn, so
+	// walking the loop back returns you rotated.
+	PortalTwist
+	// PortalOneWay links a face to a cell with no way back through it.
+	PortalOneWay
+)
+
+// PortalPlacement records one linked face placed by the generator.
+type PortalPlacement struct {
+	Kind PortalKind
+	From engine.PortalFace
+	To   engine.Portal
+}
+
+// protectPortals marks the cells that keep each portal usable: the approach
+// cell in front of its face and the cell it lets out into.
+func protectPortals(protected map[Point]bool, portals []PortalPlacement) {
+	for _, p := range portals {
+		protected[Point{X: p.To.ToX, Y: p.To.ToY}] = true
+		dx, dy := p.From.Face.Delta()
+		protected[Point{X: p.From.X + dx, Y: p.From.Y + dy}] = true
+	}
+}
+
+const (
+	portalStartDepth    = 12
+	portalDepthPerExtra = 15
+	maxPortalLinks      = 3
+	portalFoldSamples   = 8
+)
+
+// portalSite is a wall cell that can be walked into from an open cell.
+type portalSite struct {
+	wall     Point
+	approach Point
+	travel   engine.Dir // direction of travel from approach into wall
+}
+
+// placePortals links wall faces once floors are deep enough. Portal cells
+// stay solid for pathfinding and every destination is an open cell of the
+// single connected floor, so stairs remain reachable whatever the links.
+func (g *FloorGenerator) placePortals(m *engine.GameMap, rng *rand.Rand, spawn, stairs Point) []PortalPlacement {
+	if g.Depth < portalStartDepth {
+		return nil
+	}
+	links := 1 + (g.Depth-portalStartDepth)/portalDepthPerExtra
+	if links > maxPortalLinks {
+		links = maxPortalLinks
+	}
+
+	sites := portalSites(m)
+	used := make(map[Point]bool)
+	var placed []PortalPlacement
+
+	take := func() (portalSite, bool) {
+		for tries := 0; tries < len(sites); tries++ {
+			s := sites[rng.Intn(len(sites))]
+			if !used[s.wall] && !used[s.approach] && s.approach != stairs {
+				used[s.wall] = true
+				used[s.approach] = true
+				return s, true
+			}
+		}
+		return portalSite{}, false
+	}
+
+	for i := 0; i < links && len(sites) > 0; i++ {
+		a, ok := take()
+		if !ok {
+			break
+		}
+
+		kind := PortalKind(rng.Intn(3))
+		if kind == PortalOneWay {
+			open := reachableCells(m, spawn)
+			dest := open[rng.Intn(len(open))]
+			link := engine.Portal{ToX: dest.X, ToY: dest.Y, Exit: engine.Dir(rng.Intn(4))}
+			placed = append(placed, addPortal(m, kind, a, link))
+			continue
+		}
+
+		b, ok := farSite(m, rng, sites, used, a, stairs)
+		if !ok {
+			break
+		}
+		twist := 0
+		if kind == PortalTwist {
+			twist = 1
+			if rng.Intn(2) == 0 {
+				twist = -1
+			}
+		}
+		// Walking into a comes out of b's face, stepping back into b's
+		// approach cell (and vice versa), turned by the twist.
+		toB := engine.Portal{ToX: b.approach.X, ToY: b.approach.Y, Exit: b.travel.Opposite().Rotate(twist)}
+		toA := engine.Portal{ToX: a.approach.X, ToY: a.approach.Y, Exit: a.travel.Opposite().Rotate(-twist)}
+		placed = append(placed, addPortal(m, kind, a, toB), addPortal(m, kind, b, toA))
+	}
+	return placed
+}
+
+func addPortal(m *engine.GameMap, kind PortalKind, s portalSite, link engine.Portal) PortalPlacement {
+	face := s.travel.Opposite()
+	m.AddPortal(s.wall.X, s.wall.Y, face, link)
+	return PortalPlacement{
+		Kind: kind,
+		From: engine.PortalFace{X: s.wall.X, Y: s.wall.Y, Face: face},
+		To:   link,
+	}
+}
+
+// farSite samples unused sites and returns the one farthest on foot from a.
+func farSite(m *engine.GameMap, rng *rand.Rand, sites []portalSite, used map[Point]bool, a portalSite, stairs Point) (portalSite, bool) {
+	dist := walkDistances(m, a.approach)
+	best, bestDist := portalSite{}, -1
+	for i := 0; i < portalFoldSamples; i++ {
+		s := sites[rng.Intn(len(sites))]
+		if used[s.wall] || used[s.approach] || s.approach == stairs {
+			continue
+		}
+		if d, ok := dist[s.approach]; ok && d > bestDist {
+			best, bestDist = s, d
+		}
+	}
+	if bestDist < 0 {
+		return portalSite{}, false
+	}
+	used[best.wall] = true
+	used[best.approach] = true
+	return best, true
+}
+
+// portalSites lists every interior wall cell with an open neighbour, in map
+// order so placement is deterministic for a seed.
+func portalSites(m *engine.GameMap) []portalSite {
+	var sites []portalSite
+	for y := 1; y < m.Height-1; y++ {
+		for x := 1; x < m.Width-1; x++ {
+			if m.Cells[y][x] != engine.CellWall {
+				continue
+			}
+			for _, travel := range []engine.Dir{engine.DirEast, engine.DirSouth, engine.DirWest, engine.DirNorth} {
+				dx, dy := travel.Delta()
+				ax, ay := x-dx, y-dy
+				if m.GetCell(ax, ay) == engine.CellEmpty {
+					sites = append(sites, portalSite{wall: Point{X: x, Y: y}, approach: Point{X: ax, Y: ay}, travel: travel})
+				}
+			}
+		}
+	}
+	return sites
+}
+
+// walkDistances returns the BFS step count to every cell reachable from `from`.
+func walkDistances(m *engine.GameMap, from Point) map[Point]int {
+	_, dist := bfs(m, from)
+	return dist
+}
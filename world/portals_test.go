@@ -0,0 +1,77 @@
+package world
+
+import (
+	"testing"
+
+	"game/engine"
+)
+
+func TestPlacePortalsOnlyAtDepth(t *testing.T) {
+	g := NewFloorGenerator(32, 32, portalStartDepth-1).WithSeed(5)
+	g.Generate()
+	if len(g.Portals) != 0 {
+		t.Fatalf("expected no portals above depth %d, got %d", portalStartDepth, len(g.Portals))
+	}
+}
+
+func TestPlacePortalsKeepFloorsPlayable(t *testing.T) {
+	kinds := map[PortalKind]bool{}
+	for seed := int64(1); seed <= 40; seed++ {
+		for _, alg := range []Algorithm{AlgorithmDrunkWalk, AlgorithmRooms, AlgorithmCaves} {
+			g := NewFloorGenerator(32, 32, 45).WithSeed(seed)
+			g.Algorithm = alg
+			m := g.Generate()
+
+			reachable, stairsReached := floodFillCount(m, g.SpawnPos)
+			if !stairsReached || reachable != countPassable(m) {
+				t.Fatalf("seed %d alg %d: portals broke connectivity", seed, alg)
+			}
+			for _, p := range g.Portals {
+				kinds[p.Kind] = true
+				if m.GetCell(p.From.X, p.From.Y) != engine.CellPortal {
+					t.Fatalf("seed %d: portal face %+v is not a portal cell", seed, p.From)
+				}
+				if m.IsWall(p.To.ToX, p.To.ToY) {
+					t.Fatalf("seed %d: portal %+v leads into a wall", seed, p)
+				}
+				// The linked face is the one you walk into from the open side.
+				dx, dy := p.From.Face.Delta()
+				if m.IsWall(p.From.X+dx, p.From.Y+dy) {
+					t.Fatalf("seed %d: portal face %+v is not approachable", seed, p.From)
+				}
+			}
+		}
+	}
+	for _, k := range []PortalKind{PortalFold, PortalTwist, PortalOneWay} {
+		if !kinds[k] {
+			t.Fatalf("expected portal kind %d to be placed for some seed", k)
+		}
+	}
+}
+
+func TestFoldPortalsLinkBothWays(t *testing.T) {
+	for seed := int64(1); seed <= 40; seed++ {
+		g := NewFloorGenerator(32, 32, 45).WithSeed(seed)
+		m := g.Generate()
+		for _, p := range g.Portals {
+			if p.Kind != PortalFold {
+				continue
+			}
+			// Stepping through and turning around must lead back out of the first face.
+			travel := p.From.Face.Opposite()
+			x, y, dir, ok := m.FollowPortals(p.From.X, p.From.Y, travel)
+			if !ok {
+				t.Fatalf("seed %d: fold portal not passable", seed)
+			}
+			back := dir.Opposite()
+			dx, dy := back.Delta()
+			bx, by, bdir, ok := m.FollowPortals(x+dx, y+dy, back)
+			fx, fy := p.From.Face.Delta()
+			if !ok || bx != p.From.X+fx || by != p.From.Y+fy || bdir != p.From.Face {
+				t.Fatalf("seed %d: fold portal did not return to its entrance (%d,%d %d ok=%v)", seed, bx, by, bdir, ok)
+			}
+			return
+		}
+	}
+	t.Fatal("expected a fold portal for some seed")
+}
@@ -0,0 +1,159 @@
+package world
+
+import (
+	"math/rand"
+
+	"game/engine"
+)
+
+// shiftBand is a corruption threshold and how restless the walls become past it.
+type shiftBand struct {
+	Level    float64
+	Interval int // update ticks between shifts
+	Toggles  int // cells changed per shift
+}
+
+// shiftBands are ordered by Level; the highest band reached applies.
+var shiftBands = []shiftBand{
+	{Level: 0.60, Interval: 240, Toggles: 1},
+	{Level: 0.75, Interval: 150, Toggles: 2},
+	{Level: 0.90, Interval: 90, Toggles: 3},
+}
+
+const (
+	shiftSeedSalt       = int64(0x5EED5)
+	shiftCandidateTries = 24
+)
+
+// Shifter moves walls while nobody is looking. Once corruption passes a
+// threshold it periodically opens and closes cells outside the player's view,
+// never breaking the walk from spawn or the player to the stairs.
+type Shifter struct {
+	rng   *rand.Rand
+	ticks int
+
+	// Shifts counts the cells changed so far.
+	Shifts int
+}
+
+// NewShifter returns a shifter whose choices are deterministic for seed and depth.
+func NewShifter(seed int64, depth int) *Shifter {
+	return &Shifter{rng: rand.New(rand.NewSource(seed + int64(depth)*seedDepthMultiplier + shiftSeedSalt))}
+}
+
+// Tick advances the shifter by one update at the given corruption level and
+// reports whether a shift is due now.
+func (s *Shifter) Tick(level float64) bool {
+	if s == nil {
+		return false
+	}
+	band, ok := shiftBandFor(level)
+	if !ok {
+		s.ticks = 0
+		return false
+	}
+	s.ticks++
+	if s.ticks < band.Interval {
+		return false
+	}
+	s.ticks = 0
+	return true
+}
+
+// Shift toggles walls on floor f outside visible, at the given corruption
+// level, and returns how many cells changed.
+func (s *Shifter) Shift(f *Floor, level float64, visible *engine.Visibility, player Point) int {
+	if s == nil || f == nil || f.Map == nil {
+		return 0
+	}
+	band, ok := shiftBandFor(level)
+	if !ok {
+		return 0
+	}
+	m := f.Map
+	if m.Width < 3 || m.Height < 3 {
+		return 0
+	}
+	protected := f.protectedCells(player)
+
+	changed := 0
+	for i := 0; i < band.Toggles; i++ {
+		for try := 0; try < shiftCandidateTries; try++ {
+			p := Point{X: 1 + s.rng.Intn(m.Width-2), Y: 1 + s.rng.Intn(m.Height-2)}
+			if protected[p] || visible.Contains(p.X, p.Y) {
+				continue
+			}
+			if s.toggle(f, p, player) {
+				changed++
+				break
+			}
+		}
+	}
+	s.Shifts += changed
+	return changed
+}
+
+// toggle opens a wall next to open floor, or closes open floor whose loss
+// keeps the stairs reachable from spawn and the player.
+func (s *Shifter) toggle(f *Floor, p Point, player Point) bool {
+	m := f.Map
+	switch m.Cells[p.Y][p.X] {
+	case engine.CellWall:
+		for _, d := range []Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			if !m.IsWall(p.X+d.X, p.Y+d.Y) {
+				m.Cells[p.Y][p.X] = engine.CellEmpty
+				return true
+			}
+		}
+	case engine.CellEmpty:
+		m.Cells[p.Y][p.X] = engine.CellWall
+		if f.stairsReachableFrom(f.SpawnPos, player) {
+			return true
+		}
+		m.Cells[p.Y][p.X] = engine.CellEmpty
+	}
+	return false
+}
+
+// stairsReachableFrom reports whether the stairs and every note remain
+// walkable from each of the given cells.
+func (f *Floor) stairsReachableFrom(from ...Point) bool {
+	_, dist := bfs(f.Map, f.StairsPos)
+	for _, p := range from {
+		if _, ok := dist[p]; !ok {
+			return false
+		}
+	}
+	for _, n := range f.Notes {
+		if _, ok := dist[n.Pos]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// protectedCells are cells the shifter must never change: the player, spawn,
+// stairs, notes and the cells in front of and behind each portal.
+func (f *Floor) protectedCells(player Point) map[Point]bool {
+	protected := map[Point]bool{
+		player:      true,
+		f.SpawnPos:  true,
+		f.StairsPos: true,
+	}
+	for _, n := range f.Notes {
+		protected[n.Pos] = true
+	}
+	protectPortals(protected, f.Portals)
+	return protected
+}
+
+func shiftBandFor(level float64) (shiftBand, bool) {
+	var band shiftBand
+	found := false
+	for _, b := range shiftBands {
+		if level >= b.Level {
+			band, found = b, true
+		}
+	}
+	return band, found
+}
@@ -0,0 +1,114 @@
+package world
+
+import (
+	"math"
+	"testing"
+
+	"game/engine"
+)
+
+func TestShifterTickCadence(t *testing.T) {
+	s := NewShifter(1, 40)
+	for i := 0; i < 1000; i++ {
+		if s.Tick(0.5) {
+			t.Fatal("expected no shifts below the first threshold")
+		}
+	}
+
+	due := 0
+	for i := 0; i < 900; i++ {
+		if s.Tick(1.0) {
+			due++
+		}
+	}
+	if want := 900 / shiftBands[len(shiftBands)-1].Interval; due != want {
+		t.Fatalf("expected %d shifts at full corruption, got %d", want, due)
+	}
+}
+
+func TestShifterKeepsStairsReachableAndRespectsView(t *testing.T) {
+	fm := NewFloorManagerWithSize(24, 24)
+	fm.Generator.WithSeed(17)
+	f := fm.TeleportToDepth(45)
+	r := engine.NewRaycaster(80, 24)
+
+	changed := 0
+	for i := 0; i < 200; i++ {
+		// The player can only stand where the stairs can still be reached from.
+		open := reachableCells(f.Map, f.StairsPos)
+		pos := open[(i*7)%len(open)]
+		player := engine.NewPlayerAtCell(pos.X, pos.Y, float64(i%4)*math.Pi/2)
+		visible := r.VisibleCells(player, f.Map)
+
+		before := snapshotCells(f.Map)
+		changed += f.Shifter.Shift(f, 1.0, visible, pos)
+
+		for y := range before {
+			for x := range before[y] {
+				if before[y][x] != f.Map.Cells[y][x] && visible.Contains(x, y) {
+					t.Fatalf("shift %d changed visible cell (%d,%d)", i, x, y)
+				}
+			}
+		}
+		if !f.stairsReachableFrom(f.SpawnPos, pos) {
+			t.Fatalf("shift %d cut the stairs off from spawn or player", i)
+		}
+		if f.Map.IsWall(pos.X, pos.Y) {
+			t.Fatalf("shift %d walled in the player", i)
+		}
+	}
+	if changed == 0 || f.Shifter.Shifts != changed {
+		t.Fatalf("expected shifts to happen and be counted (changed=%d counted=%d)", changed, f.Shifter.Shifts)
+	}
+}
+
+func TestShifterLeavesPortalsUsable(t *testing.T) {
+	fm := NewFloorManagerWithSize(24, 24)
+	fm.Generator.WithSeed(17)
+	f := fm.TeleportToDepth(45)
+	if len(f.Portals) == 0 {
+		t.Fatal("expected portals at depth 45")
+	}
+	var keep []Point
+	for _, p := range f.Portals {
+		dx, dy := p.From.Face.Delta()
+		keep = append(keep, Point{X: p.From.X + dx, Y: p.From.Y + dy}, Point{X: p.To.ToX, Y: p.To.ToY})
+	}
+	before := snapshotCells(f.Map)
+	for i := 0; i < 500; i++ {
+		f.Shifter.Shift(f, 1.0, nil, f.SpawnPos)
+	}
+	for _, p := range keep {
+		if f.Map.Cells[p.Y][p.X] != before[p.Y][p.X] {
+			t.Fatalf("shifter changed portal approach or exit cell %v", p)
+		}
+	}
+}
+
+func TestShifterDeterministic(t *testing.T) {
+	run := func() [][]int {
+		fm := NewFloorManagerWithSize(24, 24)
+		fm.Generator.WithSeed(3)
+		f := fm.TeleportToDepth(30)
+		for i := 0; i < 20; i++ {
+			f.Shifter.Shift(f, 0.95, nil, f.SpawnPos)
+		}
+		return f.Map.Cells
+	}
+	a, b := run(), run()
+	for y := range a {
+		for x := range a[y] {
+			if a[y][x] != b[y][x] {
+				t.Fatalf("shifts differ at (%d,%d)", x, y)
+			}
+		}
+	}
+}
+
+func snapshotCells(m *engine.GameMap) [][]int {
+	out := make([][]int, len(m.Cells))
+	for y := range m.Cells {
+		out[y] = append([]int(nil), m.Cells[y]...)
+	}
+	return out
+}